@@ -2,6 +2,8 @@ package logging
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
@@ -10,9 +12,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tfsdklog"
 )
 
-// InitContext creates SDK logger contexts.
+// InitContext creates SDK logger contexts and injects the mux version into
+// the mux logger context.
 func InitContext(ctx context.Context) context.Context {
 	ctx = tfsdklog.NewSubsystem(ctx, SubsystemMux, tfsdklog.WithLevelFromEnv(EnvTfLogSdkMux))
+	ctx = tfsdklog.SubsystemSetField(ctx, SubsystemMux, KeyTfMuxVersion, MuxVersion())
 
 	return ctx
 }
@@ -26,6 +30,42 @@ func RpcContext(ctx context.Context, rpc string) context.Context {
 	return ctx
 }
 
+// requestIdContextKey is the context key of the request ID.
+type requestIdContextKey struct{}
+
+// RequestIdContext generates a new request ID and injects it into the context
+// and logger contexts.
+func RequestIdContext(ctx context.Context) context.Context {
+	requestId := newRequestId()
+	ctx = context.WithValue(ctx, requestIdContextKey{}, requestId)
+	ctx = tflog.SetField(ctx, KeyTfMuxRequestId, requestId)
+	ctx = tfsdklog.SetField(ctx, KeyTfMuxRequestId, requestId)
+	ctx = tfsdklog.SubsystemSetField(ctx, SubsystemMux, KeyTfMuxRequestId, requestId)
+
+	return ctx
+}
+
+// RequestIdFromContext returns the request ID injected by RequestIdContext,
+// or an empty string.
+func RequestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdContextKey{}).(string)
+
+	return requestId
+}
+
+// newRequestId returns a short random hexadecimal identifier.
+func newRequestId() string {
+	b := make([]byte, 8)
+
+	// crypto/rand.Read only fails if the operating system random source is
+	// unavailable, in which case requests are left uncorrelated.
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
 // Tfprotov5ProviderServerContext injects the chosen provider Go type
 func Tfprotov5ProviderServerContext(ctx context.Context, p tfprotov5.ProviderServer) context.Context {
 	providerType := fmt.Sprintf("%T", p)
@@ -5,9 +5,42 @@ package logging
 // Practitioners or tooling reading logs may be depending on these keys, so be
 // conscious of that when changing them.
 const (
+	// Error returned by a provider server or mux operation.
+	KeyError = "error"
+
 	// Go type of the provider selected by mux.
 	KeyTfMuxProvider = "tf_mux_provider"
 
+	// Sorted data source type names declared by a provider server.
+	KeyTfMuxDataSourceTypes = "tf_mux_data_source_types"
+
+	// Request or response field of a DynamicValue, such as
+	// "request PriorState".
+	KeyTfMuxDynamicValueField = "tf_mux_dynamic_value_field"
+
+	// Sorted resource type names declared by a provider server.
+	KeyTfMuxResourceTypes = "tf_mux_resource_types"
+
+	// Total byte size of the request or response payloads of a downstream
+	// provider server call.
+	KeyTfMuxPayloadSize = "tf_mux_payload_size"
+
+	// Randomly generated identifier of an RPC received by mux, shared by all
+	// log entries of the RPC.
+	KeyTfMuxRequestId = "tf_mux_request_id"
+
+	// Version of the terraform-plugin-mux module, such as "v0.8.0".
+	KeyTfMuxVersion = "tf_mux_version"
+
+	// Index of the provider server, in the order given to mux.
+	KeyTfMuxServerIndex = "tf_mux_server_index"
+
+	// The data source type name of the request.
+	KeyTfDataSourceType = "tf_data_source_type"
+
+	// The resource type name of the request.
+	KeyTfResourceType = "tf_resource_type"
+
 	// The RPC being run, such as "ApplyResourceChange"
 	KeyTfRpc = "tf_rpc"
 )
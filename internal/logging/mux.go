@@ -11,7 +11,27 @@ const (
 	SubsystemMux = "mux"
 )
 
+// MuxDebug emits a mux subsystem log at DEBUG level.
+func MuxDebug(ctx context.Context, msg string, additionalFields ...map[string]interface{}) {
+	tfsdklog.SubsystemDebug(ctx, SubsystemMux, msg, additionalFields...)
+}
+
+// MuxError emits a mux subsystem log at ERROR level.
+func MuxError(ctx context.Context, msg string, additionalFields ...map[string]interface{}) {
+	tfsdklog.SubsystemError(ctx, SubsystemMux, msg, additionalFields...)
+}
+
+// MuxInfo emits a mux subsystem log at INFO level.
+func MuxInfo(ctx context.Context, msg string, additionalFields ...map[string]interface{}) {
+	tfsdklog.SubsystemInfo(ctx, SubsystemMux, msg, additionalFields...)
+}
+
 // MuxTrace emits a mux subsystem log at TRACE level.
 func MuxTrace(ctx context.Context, msg string, additionalFields ...map[string]interface{}) {
 	tfsdklog.SubsystemTrace(ctx, SubsystemMux, msg, additionalFields...)
 }
+
+// MuxWarn emits a mux subsystem log at WARN level.
+func MuxWarn(ctx context.Context, msg string, additionalFields ...map[string]interface{}) {
+	tfsdklog.SubsystemWarn(ctx, SubsystemMux, msg, additionalFields...)
+}
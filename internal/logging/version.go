@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the Go module path of terraform-plugin-mux.
+const modulePath = "github.com/hashicorp/terraform-plugin-mux"
+
+// unknownMuxVersion is the mux version logged when it cannot be determined.
+const unknownMuxVersion = "unknown"
+
+// muxVersion is the terraform-plugin-mux module version. It may be stamped at
+// build time, such as with:
+//
+//	-ldflags "-X github.com/hashicorp/terraform-plugin-mux/internal/logging.muxVersion=v0.8.0"
+//
+// Otherwise, it is read from the build information of the binary.
+var muxVersion string
+
+var muxVersionOnce sync.Once
+
+// MuxVersion returns the terraform-plugin-mux module version, which is the
+// build-stamped version, the version from the build information of the
+// binary, or "unknown" if neither is available.
+func MuxVersion() string {
+	muxVersionOnce.Do(func() {
+		if muxVersion != "" {
+			return
+		}
+
+		muxVersion = muxVersionFromBuildInfo(debug.ReadBuildInfo())
+	})
+
+	return muxVersion
+}
+
+// muxVersionFromBuildInfo returns the terraform-plugin-mux module version from
+// the build information, or "unknown" if the build information is
+// unavailable or does not contain the module.
+func muxVersionFromBuildInfo(info *debug.BuildInfo, ok bool) string {
+	if !ok || info == nil {
+		return unknownMuxVersion
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep == nil || dep.Path != modulePath {
+			continue
+		}
+
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+
+		if dep.Version != "" {
+			return dep.Version
+		}
+	}
+
+	return unknownMuxVersion
+}
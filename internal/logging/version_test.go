@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"runtime/debug"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tfsdklogtest"
+)
+
+func TestInitContextMuxVersion(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	ctx := tfsdklogtest.RootLogger(context.Background(), &output)
+	ctx = InitContext(ctx)
+
+	MuxTrace(ctx, "test message")
+
+	entries, err := tfsdklogtest.MultilineJSONDecode(&output)
+
+	if err != nil {
+		t.Fatalf("unable to read log entries: %s", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+
+	if got := entries[0][KeyTfMuxVersion]; got != MuxVersion() {
+		t.Errorf("expected %s %q, got %v", KeyTfMuxVersion, MuxVersion(), got)
+	}
+
+	if MuxVersion() == "" {
+		t.Errorf("expected mux version")
+	}
+}
+
+func TestMuxVersionFromBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		info     *debug.BuildInfo
+		ok       bool
+		expected string
+	}{
+		"dependency": {
+			info: &debug.BuildInfo{
+				Main: debug.Module{
+					Path:    "example.com/terraform-provider-example",
+					Version: "v1.0.0",
+				},
+				Deps: []*debug.Module{
+					{
+						Path:    "github.com/hashicorp/terraform-plugin-go",
+						Version: "v0.14.2",
+					},
+					{
+						Path:    "github.com/hashicorp/terraform-plugin-mux",
+						Version: "v0.8.0",
+					},
+				},
+			},
+			ok:       true,
+			expected: "v0.8.0",
+		},
+		"dependency-missing": {
+			info: &debug.BuildInfo{
+				Main: debug.Module{
+					Path:    "example.com/terraform-provider-example",
+					Version: "v1.0.0",
+				},
+			},
+			ok:       true,
+			expected: "unknown",
+		},
+		"dependency-replaced": {
+			info: &debug.BuildInfo{
+				Deps: []*debug.Module{
+					{
+						Path:    "github.com/hashicorp/terraform-plugin-mux",
+						Version: "v0.8.0",
+						Replace: &debug.Module{
+							Path:    "example.com/terraform-plugin-mux",
+							Version: "v0.8.1",
+						},
+					},
+				},
+			},
+			ok:       true,
+			expected: "v0.8.1",
+		},
+		"main": {
+			info: &debug.BuildInfo{
+				Main: debug.Module{
+					Path:    "github.com/hashicorp/terraform-plugin-mux",
+					Version: "(devel)",
+				},
+			},
+			ok:       true,
+			expected: "(devel)",
+		},
+		"unavailable": {
+			expected: "unknown",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := muxVersionFromBuildInfo(testCase.info, testCase.ok)
+
+			if got != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, got)
+			}
+		})
+	}
+}
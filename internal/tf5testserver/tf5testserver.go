@@ -13,12 +13,15 @@ type TestServer struct {
 	ProviderMetaSchema *tfprotov5.Schema
 	ProviderSchema     *tfprotov5.Schema
 	ResourceSchemas    map[string]*tfprotov5.Schema
+	ServerCapabilities *tfprotov5.ServerCapabilities
 
 	ApplyResourceChangeCalled map[string]bool
 
-	ConfigureProviderCalled bool
+	ConfigureProviderCalled   bool
+	ConfigureProviderResponse *tfprotov5.ConfigureProviderResponse
 
-	GetProviderSchemaCalled bool
+	GetProviderSchemaCalled      bool
+	GetProviderSchemaDiagnostics []*tfprotov5.Diagnostic
 
 	ImportResourceStateCalled map[string]bool
 
@@ -38,7 +41,8 @@ type TestServer struct {
 
 	ValidateDataSourceConfigCalled map[string]bool
 
-	ValidateResourceTypeConfigCalled map[string]bool
+	ValidateResourceTypeConfigCalled   map[string]bool
+	ValidateResourceTypeConfigResponse *tfprotov5.ValidateResourceTypeConfigResponse
 }
 
 func (s *TestServer) ProviderServer() tfprotov5.ProviderServer {
@@ -56,6 +60,11 @@ func (s *TestServer) ApplyResourceChange(_ context.Context, req *tfprotov5.Apply
 
 func (s *TestServer) ConfigureProvider(_ context.Context, _ *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
 	s.ConfigureProviderCalled = true
+
+	if s.ConfigureProviderResponse != nil {
+		return s.ConfigureProviderResponse, nil
+	}
+
 	return &tfprotov5.ConfigureProviderResponse{}, nil
 }
 
@@ -71,10 +80,12 @@ func (s *TestServer) GetProviderSchema(_ context.Context, _ *tfprotov5.GetProvid
 	}
 
 	return &tfprotov5.GetProviderSchemaResponse{
-		Provider:          s.ProviderSchema,
-		ProviderMeta:      s.ProviderMetaSchema,
-		ResourceSchemas:   s.ResourceSchemas,
-		DataSourceSchemas: s.DataSourceSchemas,
+		Provider:           s.ProviderSchema,
+		ProviderMeta:       s.ProviderMetaSchema,
+		ResourceSchemas:    s.ResourceSchemas,
+		DataSourceSchemas:  s.DataSourceSchemas,
+		ServerCapabilities: s.ServerCapabilities,
+		Diagnostics:        s.GetProviderSchemaDiagnostics,
 	}, nil
 }
 
@@ -150,7 +161,7 @@ func (s *TestServer) ValidateResourceTypeConfig(_ context.Context, req *tfprotov
 	}
 
 	s.ValidateResourceTypeConfigCalled[req.TypeName] = true
-	return nil, nil
+	return s.ValidateResourceTypeConfigResponse, nil
 }
 
 func (s *TestServer) PrepareProviderConfig(_ context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
@@ -13,12 +13,15 @@ type TestServer struct {
 	ProviderMetaSchema *tfprotov6.Schema
 	ProviderSchema     *tfprotov6.Schema
 	ResourceSchemas    map[string]*tfprotov6.Schema
+	ServerCapabilities *tfprotov6.ServerCapabilities
 
 	ApplyResourceChangeCalled map[string]bool
 
-	ConfigureProviderCalled bool
+	ConfigureProviderCalled   bool
+	ConfigureProviderResponse *tfprotov6.ConfigureProviderResponse
 
-	GetProviderSchemaCalled bool
+	GetProviderSchemaCalled      bool
+	GetProviderSchemaDiagnostics []*tfprotov6.Diagnostic
 
 	ImportResourceStateCalled map[string]bool
 
@@ -38,7 +41,8 @@ type TestServer struct {
 	ValidateProviderConfigCalled   bool
 	ValidateProviderConfigResponse *tfprotov6.ValidateProviderConfigResponse
 
-	ValidateResourceConfigCalled map[string]bool
+	ValidateResourceConfigCalled   map[string]bool
+	ValidateResourceConfigResponse *tfprotov6.ValidateResourceConfigResponse
 }
 
 func (s *TestServer) ProviderServer() tfprotov6.ProviderServer {
@@ -56,6 +60,11 @@ func (s *TestServer) ApplyResourceChange(_ context.Context, req *tfprotov6.Apply
 
 func (s *TestServer) ConfigureProvider(_ context.Context, _ *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
 	s.ConfigureProviderCalled = true
+
+	if s.ConfigureProviderResponse != nil {
+		return s.ConfigureProviderResponse, nil
+	}
+
 	return &tfprotov6.ConfigureProviderResponse{}, nil
 }
 
@@ -71,10 +80,12 @@ func (s *TestServer) GetProviderSchema(_ context.Context, _ *tfprotov6.GetProvid
 	}
 
 	return &tfprotov6.GetProviderSchemaResponse{
-		Provider:          s.ProviderSchema,
-		ProviderMeta:      s.ProviderMetaSchema,
-		ResourceSchemas:   s.ResourceSchemas,
-		DataSourceSchemas: s.DataSourceSchemas,
+		Provider:           s.ProviderSchema,
+		ProviderMeta:       s.ProviderMetaSchema,
+		ResourceSchemas:    s.ResourceSchemas,
+		DataSourceSchemas:  s.DataSourceSchemas,
+		ServerCapabilities: s.ServerCapabilities,
+		Diagnostics:        s.GetProviderSchemaDiagnostics,
 	}, nil
 }
 
@@ -150,7 +161,7 @@ func (s *TestServer) ValidateResourceConfig(_ context.Context, req *tfprotov6.Va
 	}
 
 	s.ValidateResourceConfigCalled[req.TypeName] = true
-	return nil, nil
+	return s.ValidateResourceConfigResponse, nil
 }
 
 func (s *TestServer) ValidateProviderConfig(_ context.Context, req *tfprotov6.ValidateProviderConfigRequest) (*tfprotov6.ValidateProviderConfigResponse, error) {
@@ -1,10 +1,15 @@
 package tfprotov5tov6
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
+var ErrSchemaValueTypeMismatch error = errors.New("schema value type is not identical in protocol version 6")
+
 func ApplyResourceChangeRequest(in *tfprotov5.ApplyResourceChangeRequest) *tfprotov6.ApplyResourceChangeRequest {
 	if in == nil {
 		return nil
@@ -97,30 +102,54 @@ func GetProviderSchemaRequest(in *tfprotov5.GetProviderSchemaRequest) *tfprotov6
 	return &tfprotov6.GetProviderSchemaRequest{}
 }
 
-func GetProviderSchemaResponse(in *tfprotov5.GetProviderSchemaResponse) *tfprotov6.GetProviderSchemaResponse {
+func GetProviderSchemaResponse(in *tfprotov5.GetProviderSchemaResponse) (*tfprotov6.GetProviderSchemaResponse, error) {
 	if in == nil {
-		return nil
+		return nil, nil
 	}
 
 	dataSourceSchemas := make(map[string]*tfprotov6.Schema, len(in.DataSourceSchemas))
 
 	for k, v := range in.DataSourceSchemas {
-		dataSourceSchemas[k] = Schema(v)
+		v6Schema, err := Schema(v)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert data source %q schema: %w", k, err)
+		}
+
+		dataSourceSchemas[k] = v6Schema
+	}
+
+	provider, err := Schema(in.Provider)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert provider schema: %w", err)
+	}
+
+	providerMeta, err := Schema(in.ProviderMeta)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert provider meta schema: %w", err)
 	}
 
 	resourceSchemas := make(map[string]*tfprotov6.Schema, len(in.ResourceSchemas))
 
 	for k, v := range in.ResourceSchemas {
-		resourceSchemas[k] = Schema(v)
+		v6Schema, err := Schema(v)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert resource %q schema: %w", k, err)
+		}
+
+		resourceSchemas[k] = v6Schema
 	}
 
 	return &tfprotov6.GetProviderSchemaResponse{
 		DataSourceSchemas: dataSourceSchemas,
 		Diagnostics:       Diagnostics(in.Diagnostics),
-		Provider:          Schema(in.Provider),
-		ProviderMeta:      Schema(in.ProviderMeta),
+		Provider:          provider,
+		ProviderMeta:      providerMeta,
 		ResourceSchemas:   resourceSchemas,
-	}
+	}, nil
 }
 
 func ImportResourceStateRequest(in *tfprotov5.ImportResourceStateRequest) *tfprotov6.ImportResourceStateRequest {
@@ -255,15 +284,23 @@ func ReadResourceResponse(in *tfprotov5.ReadResourceResponse) *tfprotov6.ReadRes
 	}
 }
 
-func Schema(in *tfprotov5.Schema) *tfprotov6.Schema {
+func Schema(in *tfprotov5.Schema) (*tfprotov6.Schema, error) {
 	if in == nil {
-		return nil
+		return nil, nil
 	}
 
-	return &tfprotov6.Schema{
+	out := &tfprotov6.Schema{
 		Block:   SchemaBlock(in.Block),
 		Version: in.Version,
 	}
+
+	// Guard against conversion changes silently altering the data
+	// Terraform sends and receives for the schema.
+	if !out.ValueType().Equal(in.ValueType()) {
+		return nil, fmt.Errorf("%w: %s converted to %s", ErrSchemaValueTypeMismatch, in.ValueType(), out.ValueType())
+	}
+
+	return out, nil
 }
 
 func SchemaAttribute(in *tfprotov5.SchemaAttribute) *tfprotov6.SchemaAttribute {
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tfprotov5tov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tfprotov6tov5"
 )
 
 var (
@@ -399,7 +400,11 @@ func TestGetProviderSchemaResponse(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			got := tfprotov5tov6.GetProviderSchemaResponse(testCase.in)
+			got, err := tfprotov5tov6.GetProviderSchemaResponse(testCase.in)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
 
 			if diff := cmp.Diff(got, testCase.expected); diff != "" {
 				t.Errorf("unexpected difference: %s", diff)
@@ -886,7 +891,11 @@ func TestSchema(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			got := tfprotov5tov6.Schema(testCase.in)
+			got, err := tfprotov5tov6.Schema(testCase.in)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
 
 			if diff := cmp.Diff(got, testCase.expected); diff != "" {
 				t.Errorf("unexpected difference: %s", diff)
@@ -895,6 +904,124 @@ func TestSchema(t *testing.T) {
 	}
 }
 
+func TestSchemaRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		attributeType tftypes.Type
+	}{
+		"bool": {
+			attributeType: tftypes.Bool,
+		},
+		"dynamic": {
+			attributeType: tftypes.DynamicPseudoType,
+		},
+		"list": {
+			attributeType: tftypes.List{
+				ElementType: tftypes.String,
+			},
+		},
+		"map": {
+			attributeType: tftypes.Map{
+				ElementType: tftypes.Number,
+			},
+		},
+		"number": {
+			attributeType: tftypes.Number,
+		},
+		"object": {
+			attributeType: tftypes.Object{
+				AttributeTypes: map[string]tftypes.Type{
+					"test_bool":   tftypes.Bool,
+					"test_string": tftypes.String,
+				},
+			},
+		},
+		"object-optional-attributes": {
+			attributeType: tftypes.Object{
+				AttributeTypes: map[string]tftypes.Type{
+					"test_bool":   tftypes.Bool,
+					"test_string": tftypes.String,
+				},
+				OptionalAttributes: map[string]struct{}{
+					"test_string": {},
+				},
+			},
+		},
+		"set": {
+			attributeType: tftypes.Set{
+				ElementType: tftypes.Bool,
+			},
+		},
+		"string": {
+			attributeType: tftypes.String,
+		},
+		"tuple": {
+			attributeType: tftypes.Tuple{
+				ElementTypes: []tftypes.Type{
+					tftypes.String,
+					tftypes.Number,
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			in := &tfprotov5.Schema{
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:     "test_attribute",
+							Optional: true,
+							Type:     testCase.attributeType,
+						},
+					},
+					BlockTypes: []*tfprotov5.SchemaNestedBlock{
+						{
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{
+									{
+										Name:     "test_nested_attribute",
+										Optional: true,
+										Type:     testCase.attributeType,
+									},
+								},
+							},
+							Nesting:  tfprotov5.SchemaNestedBlockNestingModeSet,
+							TypeName: "test_block",
+						},
+					},
+				},
+			}
+
+			out, err := tfprotov5tov6.Schema(in)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !out.ValueType().Equal(in.ValueType()) {
+				t.Fatalf("expected value type %s, got: %s", in.ValueType(), out.ValueType())
+			}
+
+			roundTrip, err := tfprotov6tov5.Schema(out)
+
+			if err != nil {
+				t.Fatalf("unexpected round trip error: %s", err)
+			}
+
+			if diff := cmp.Diff(roundTrip, in); diff != "" {
+				t.Errorf("unexpected round trip difference: %s", diff)
+			}
+		})
+	}
+}
+
 func TestSchemaAttribute(t *testing.T) {
 	t.Parallel()
 
@@ -10,6 +10,8 @@ import (
 
 var ErrSchemaAttributeNestedTypeNotImplemented error = errors.New("SchemaAttribute NestedType is not implemented in protocol version 5")
 
+var ErrSchemaValueTypeMismatch error = errors.New("schema value type is not identical in protocol version 5")
+
 func ApplyResourceChangeRequest(in *tfprotov6.ApplyResourceChangeRequest) *tfprotov5.ApplyResourceChangeRequest {
 	if in == nil {
 		return nil
@@ -315,10 +317,18 @@ func Schema(in *tfprotov6.Schema) (*tfprotov5.Schema, error) {
 		return nil, err
 	}
 
-	return &tfprotov5.Schema{
+	out := &tfprotov5.Schema{
 		Block:   block,
 		Version: in.Version,
-	}, nil
+	}
+
+	// Guard against conversion changes silently altering the data
+	// Terraform sends and receives for the schema.
+	if !out.ValueType().Equal(in.ValueType()) {
+		return nil, fmt.Errorf("%w: %s converted to %s", ErrSchemaValueTypeMismatch, in.ValueType(), out.ValueType())
+	}
+
+	return out, nil
 }
 
 func SchemaAttribute(in *tfprotov6.SchemaAttribute) (*tfprotov5.SchemaAttribute, error) {
@@ -0,0 +1,127 @@
+package tf5muxserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// attributeCountLargestServers is the number of servers with the most
+// attributes named in the error of the WithMaxAttributeCount option.
+const attributeCountLargestServers = 3
+
+// AttributeCounts are the numbers of schema attributes of a muxed server,
+// including the attributes within nested blocks, returned by
+// AttributeCounts.
+type AttributeCounts struct {
+	// Total is the number of attributes in the muxed server
+	// GetProviderSchema response, including the provider and provider meta
+	// schemas.
+	Total int
+
+	// Servers is the number of attributes in the resource and data source
+	// schemas routed to each server, indexed by server in the order given to
+	// NewMuxServer, excluding servers excluded via the WithBestEffortSchema
+	// option.
+	Servers []int
+}
+
+// AttributeCounts returns the numbers of schema attributes of the muxed
+// server, such as to observe the growth of generated schemas. It is safe to
+// call concurrently with other methods.
+func (s muxServer) AttributeCounts() AttributeCounts {
+	result := AttributeCounts{
+		Total:   countSchemaAttributes(s.providerSchema) + countSchemaAttributes(s.providerMetaSchema),
+		Servers: make([]int, len(s.serverProviderSchemas)),
+	}
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	for _, kind := range []struct {
+		schemas       map[string]*tfprotov5.Schema
+		serverIndexes map[string]int
+	}{
+		{s.dataSourceSchemas, s.dataSourceServerIndex},
+		{s.resourceSchemas, s.resourceServerIndex},
+	} {
+		for typeName, schema := range kind.schemas {
+			count := countSchemaAttributes(schema)
+			result.Total += count
+
+			if serverIndex, ok := kind.serverIndexes[typeName]; ok && serverIndex < len(result.Servers) {
+				result.Servers[serverIndex] += count
+			}
+		}
+	}
+
+	return result
+}
+
+// checkAttributeCount returns an error naming the servers with the most
+// attributes if the number of attributes of the muxed server exceeds the
+// maximum configured via the WithMaxAttributeCount option.
+func (s muxServer) checkAttributeCount() error {
+	counts := s.AttributeCounts()
+
+	if counts.Total <= s.options.maxAttributeCount {
+		return nil
+	}
+
+	serverIndexes := make([]int, len(counts.Servers))
+
+	for serverIndex := range serverIndexes {
+		serverIndexes[serverIndex] = serverIndex
+	}
+
+	sort.SliceStable(serverIndexes, func(i, j int) bool {
+		return counts.Servers[serverIndexes[i]] > counts.Servers[serverIndexes[j]]
+	})
+
+	if len(serverIndexes) > attributeCountLargestServers {
+		serverIndexes = serverIndexes[:attributeCountLargestServers]
+	}
+
+	largest := make([]string, 0, len(serverIndexes))
+
+	for _, serverIndex := range serverIndexes {
+		largest = append(largest, fmt.Sprintf("server %d (%T): %d attributes", serverIndex, s.servers[serverIndex], counts.Servers[serverIndex]))
+	}
+
+	return fmt.Errorf("the muxed provider schemas declare %d attributes, which exceeds the maximum of %d attributes. "+
+		"Largest servers: %s", counts.Total, s.options.maxAttributeCount, strings.Join(largest, ", "))
+}
+
+// countSchemaAttributes returns the number of attributes of the schema,
+// including the attributes within nested blocks.
+func countSchemaAttributes(schema *tfprotov5.Schema) int {
+	if schema == nil {
+		return 0
+	}
+
+	return countSchemaBlockAttributes(schema.Block)
+}
+
+func countSchemaBlockAttributes(block *tfprotov5.SchemaBlock) int {
+	if block == nil {
+		return 0
+	}
+
+	count := 0
+
+	for _, attribute := range block.Attributes {
+		if attribute != nil {
+			count++
+		}
+	}
+
+	for _, nestedBlock := range block.BlockTypes {
+		if nestedBlock != nil {
+			count += countSchemaBlockAttributes(nestedBlock.Block)
+		}
+	}
+
+	return count
+}
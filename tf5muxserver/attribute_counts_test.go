@@ -0,0 +1,124 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithMaxAttributeCount(t *testing.T) {
+	t.Parallel()
+
+	attributes := func(names ...string) []*tfprotov5.SchemaAttribute {
+		result := make([]*tfprotov5.SchemaAttribute, 0, len(names))
+
+		for _, name := range names {
+			result = append(result, &tfprotov5.SchemaAttribute{
+				Name:     name,
+				Type:     tftypes.String,
+				Optional: true,
+			})
+		}
+
+		return result
+	}
+
+	servers := []func() tfprotov5.ProviderServer{
+		(&tf5testserver.TestServer{
+			ProviderSchema: &tfprotov5.Schema{
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: attributes("region"),
+				},
+			},
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource_server1": {
+					Block: &tfprotov5.SchemaBlock{
+						Attributes: attributes("id"),
+					},
+				},
+			},
+		}).ProviderServer,
+		(&tf5testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov5.Schema{
+				"test_data_source_server2": {
+					Block: &tfprotov5.SchemaBlock{
+						Attributes: attributes("id", "name"),
+					},
+				},
+			},
+			ProviderSchema: &tfprotov5.Schema{
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: attributes("region"),
+				},
+			},
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource_server2": {
+					Block: &tfprotov5.SchemaBlock{
+						Attributes: attributes("id"),
+						BlockTypes: []*tfprotov5.SchemaNestedBlock{
+							{
+								TypeName: "rule",
+								Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+								Block: &tfprotov5.SchemaBlock{
+									Attributes: attributes("action", "priority"),
+								},
+							},
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+	}
+
+	testCases := map[string]struct {
+		maxAttributeCount int
+		expectedError     string
+	}{
+		"disabled": {},
+		"exceeded": {
+			maxAttributeCount: 6,
+			expectedError: "the muxed provider schemas declare 7 attributes, which exceeds the maximum of 6 attributes. " +
+				"Largest servers: server 1 (*tf5testserver.TestServer): 5 attributes, server 0 (*tf5testserver.TestServer): 1 attributes",
+		},
+		"not-exceeded": {
+			maxAttributeCount: 7,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithMaxAttributeCount(testCase.maxAttributeCount)}, servers...)
+
+			if err != nil {
+				if testCase.expectedError == "" || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			expectedCounts := tf5muxserver.AttributeCounts{
+				Total:   7,
+				Servers: []int{1, 5},
+			}
+
+			if diff := cmp.Diff(muxServer.AttributeCounts(), expectedCounts); diff != "" {
+				t.Errorf("unexpected attribute counts difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package tf5muxserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// caseCollisionDiagnostics returns a warning Diagnostic, which is also logged,
+// for each pair of resource or data source type names implemented by
+// different servers which differ only by case.
+func (s muxServer) caseCollisionDiagnostics(ctx context.Context) []*tfprotov5.Diagnostic {
+	var diags []*tfprotov5.Diagnostic
+
+	diags = append(diags, caseCollisionTypeDiagnostics(ctx, "resource", s.resourceSchemas, s.resources, s.resourceServerIndex)...)
+	diags = append(diags, caseCollisionTypeDiagnostics(ctx, "data source", s.dataSourceSchemas, s.dataSources, s.dataSourceServerIndex)...)
+
+	return diags
+}
+
+// caseCollisionTypeDiagnostics returns a warning Diagnostic for each pair of
+// type names implemented by different servers which differ only by case.
+func caseCollisionTypeDiagnostics(ctx context.Context, kind string, schemas map[string]*tfprotov5.Schema, servers map[string]tfprotov5.ProviderServer, serverIndexes map[string]int) []*tfprotov5.Diagnostic {
+	var diags []*tfprotov5.Diagnostic
+
+	typeNamesByLower := make(map[string][]string)
+	var lowerTypeNames []string
+
+	for _, typeName := range sortedSchemaTypeNames(schemas) {
+		lowerTypeName := strings.ToLower(typeName)
+
+		if _, ok := typeNamesByLower[lowerTypeName]; !ok {
+			lowerTypeNames = append(lowerTypeNames, lowerTypeName)
+		}
+
+		typeNamesByLower[lowerTypeName] = append(typeNamesByLower[lowerTypeName], typeName)
+	}
+
+	for _, lowerTypeName := range lowerTypeNames {
+		typeNames := typeNamesByLower[lowerTypeName]
+
+		for i := 0; i < len(typeNames); i++ {
+			for j := i + 1; j < len(typeNames); j++ {
+				iTypeName, jTypeName := typeNames[i], typeNames[j]
+
+				if serverIndexes[iTypeName] == serverIndexes[jTypeName] {
+					continue
+				}
+
+				detail := fmt.Sprintf("The %s types %q, implemented by server %d (%T), and %q, implemented by server %d (%T), differ only by case. "+
+					"This is usually caused by a typo. Both types are served by the provider.",
+					kind, iTypeName, serverIndexes[iTypeName], servers[iTypeName], jTypeName, serverIndexes[jTypeName], servers[jTypeName])
+
+				logging.MuxWarn(ctx, detail)
+
+				diags = append(diags, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "Type Names Differ Only By Case",
+					Detail:   detail,
+				})
+			}
+		}
+	}
+
+	return diags
+}
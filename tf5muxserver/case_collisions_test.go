@@ -0,0 +1,123 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithCaseCollisionWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled             bool
+		servers             []func() tfprotov5.ProviderServer
+		expectedDiagnostics []*tfprotov5.Diagnostic
+	}{
+		"disabled": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_Resource": {},
+					},
+				}).ProviderServer,
+			},
+		},
+		"enabled-collisions": {
+			enabled: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source": {},
+					},
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_Data_Source": {},
+					},
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_Resource": {},
+					},
+				}).ProviderServer,
+			},
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "Type Names Differ Only By Case",
+					Detail: "The resource types \"test_Resource\", implemented by server 1 (*tf5testserver.TestServer), " +
+						"and \"test_resource\", implemented by server 0 (*tf5testserver.TestServer), differ only by case. " +
+						"This is usually caused by a typo. Both types are served by the provider.",
+				},
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "Type Names Differ Only By Case",
+					Detail: "The data source types \"test_Data_Source\", implemented by server 1 (*tf5testserver.TestServer), " +
+						"and \"test_data_source\", implemented by server 0 (*tf5testserver.TestServer), differ only by case. " +
+						"This is usually caused by a typo. Both types are served by the provider.",
+				},
+			},
+		},
+		"enabled-no-collisions": {
+			enabled: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_a": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_b": {},
+					},
+				}).ProviderServer,
+			},
+		},
+		"enabled-same-server": {
+			enabled: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+						"test_Resource": {},
+					},
+				}).ProviderServer,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithCaseCollisionWarnings(testCase.enabled)}, testCase.servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
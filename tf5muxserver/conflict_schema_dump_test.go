@@ -0,0 +1,133 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithConflictSchemaDump(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		servers      []func() tfprotov5.ProviderServer
+		expectedFile bool
+	}{
+		"conflict": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source_server1": {},
+					},
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Version: 1,
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedFile: true,
+		},
+		"no-conflict": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server1": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server2": {},
+					},
+				}).ProviderServer,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "conflict-schema-dump.json")
+
+			_, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithConflictSchemaDump(path)}, testCase.servers...)
+
+			if testCase.expectedFile && err == nil {
+				t.Fatalf("expected error")
+			}
+
+			if !testCase.expectedFile && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			data, err := os.ReadFile(path)
+
+			if !testCase.expectedFile {
+				if !os.IsNotExist(err) {
+					t.Fatalf("expected no conflict schema dump file, got error: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error reading conflict schema dump file: %s", err)
+			}
+
+			var got struct {
+				Servers []struct {
+					ServerIndex       int                        `json:"server_index"`
+					ServerType        string                     `json:"server_type"`
+					DataSourceSchemas map[string]json.RawMessage `json:"data_source_schemas"`
+					ResourceSchemas   map[string]struct {
+						Version int64 `json:"version"`
+					} `json:"resource_schemas"`
+				} `json:"servers"`
+			}
+
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("unexpected error decoding conflict schema dump file: %s", err)
+			}
+
+			if len(got.Servers) != 2 {
+				t.Fatalf("expected 2 servers, got %d", len(got.Servers))
+			}
+
+			for serverIndex, server := range got.Servers {
+				if server.ServerIndex != serverIndex {
+					t.Errorf("expected server index %d, got %d", serverIndex, server.ServerIndex)
+				}
+
+				if server.ServerType != "*tf5testserver.TestServer" {
+					t.Errorf("unexpected server %d type: %s", serverIndex, server.ServerType)
+				}
+
+				if _, ok := server.ResourceSchemas["test_resource"]; !ok {
+					t.Errorf("expected server %d test_resource schema", serverIndex)
+				}
+			}
+
+			if diff := cmp.Diff(got.Servers[1].ResourceSchemas["test_resource"].Version, int64(1)); diff != "" {
+				t.Errorf("unexpected server 1 test_resource schema version difference: %s", diff)
+			}
+
+			if _, ok := got.Servers[0].DataSourceSchemas["test_data_source_server1"]; !ok {
+				t.Errorf("expected server 0 test_data_source_server1 schema")
+			}
+		})
+	}
+}
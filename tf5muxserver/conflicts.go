@@ -0,0 +1,117 @@
+package tf5muxserver
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// ConflictKind is the kind of schema declaration which conflicted across
+// servers.
+type ConflictKind string
+
+const (
+	// ConflictKindDataSource is a data source type declared by multiple
+	// servers.
+	ConflictKindDataSource ConflictKind = "data source"
+
+	// ConflictKindProviderMetaSchema is a provider meta schema which differs
+	// across servers.
+	ConflictKindProviderMetaSchema ConflictKind = "provider meta schema"
+
+	// ConflictKindProviderSchema is a provider schema which differs across
+	// servers or, with WithProviderSchemaMerge, cannot be merged.
+	ConflictKindProviderSchema ConflictKind = "provider schema"
+
+	// ConflictKindResource is a managed resource type declared by multiple
+	// servers.
+	ConflictKindResource ConflictKind = "resource"
+)
+
+// ConflictResolution is a policy for resolving schema declarations which
+// conflict across servers.
+type ConflictResolution int
+
+const (
+	// ConflictResolutionError returns the first conflict as an error during
+	// muxed server creation. This is the default.
+	ConflictResolutionError ConflictResolution = iota
+
+	// ConflictResolutionFirstServer keeps the declaration from the first
+	// server and ignores the conflicting declarations of later servers.
+	// Servers declaring the same resource type must declare the same
+	// resource schema version, otherwise an error is returned during muxed
+	// server creation, as state upgrades would break.
+	ConflictResolutionFirstServer
+
+	// ConflictResolutionLastServer replaces the declaration with the
+	// conflicting declaration of each later server. Provider schemas which
+	// cannot be merged with WithProviderSchemaMerge are always ignored, as
+	// replacing the merged provider schema would discard the attributes of
+	// other servers. As with ConflictResolutionFirstServer, servers
+	// declaring the same resource type must declare the same resource schema
+	// version.
+	ConflictResolutionLastServer
+)
+
+// ConflictError is a schema declaration which conflicted across servers.
+// It is returned by muxed server creation under ConflictResolutionError and
+// collected by the muxed server Conflicts method otherwise.
+type ConflictError struct {
+	// Kind is the kind of schema declaration which conflicted.
+	Kind ConflictKind
+
+	// TypeName is the conflicting resource or data source type name. It is
+	// empty for provider and provider meta schema conflicts.
+	TypeName string
+
+	// ServerIndex is the index of the server, in the order given during
+	// muxed server creation, whose declaration conflicted with the
+	// declarations of previous servers.
+	ServerIndex int
+
+	// Err describes the conflict.
+	Err error
+}
+
+// Error returns the description of the conflict.
+func (e *ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Conflicts returns the schema declarations which conflicted across servers
+// during muxed server creation, in the order they were found. Conflicts are
+// only collected when WithConflictResolution is configured with a policy
+// other than ConflictResolutionError.
+func (s muxServer) Conflicts() []*ConflictError {
+	return s.conflicts
+}
+
+// checkResourceSchemaVersion returns an error if the resource schema declared
+// by the server has a different version than the schema of the server which
+// already declared the resource type. Servers declaring the same resource
+// type under ConflictResolutionFirstServer or ConflictResolutionLastServer
+// are replicas, so the state written by either must be upgradable by both.
+func (s muxServer) checkResourceSchemaVersion(resourceType string, serverIndex int, server tfprotov5.ProviderServer, schema *tfprotov5.Schema) error {
+	var version, existingVersion int64
+
+	if schema != nil {
+		version = schema.Version
+	}
+
+	if existingSchema := s.resourceSchemas[resourceType]; existingSchema != nil {
+		existingVersion = existingSchema.Version
+	}
+
+	if version == existingVersion {
+		return nil
+	}
+
+	return fmt.Errorf("resource %q schema version %d of server %d (%T) does not match schema version %d of %T; "+
+		"servers declaring the same resource must declare the same schema version", resourceType, version, serverIndex, server, existingVersion, s.resources[resourceType])
+}
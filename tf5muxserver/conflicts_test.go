@@ -0,0 +1,290 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithConflictResolution(t *testing.T) {
+	t.Parallel()
+
+	type conflict struct {
+		Kind        tf5muxserver.ConflictKind
+		TypeName    string
+		ServerIndex int
+	}
+
+	newProviderSchema := func(attributeName string) *tfprotov5.Schema {
+		return &tfprotov5.Schema{
+			Block: &tfprotov5.SchemaBlock{
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:     attributeName,
+						Type:     tftypes.String,
+						Optional: true,
+					},
+				},
+			},
+		}
+	}
+
+	expectedConflicts := []conflict{
+		{
+			Kind:        tf5muxserver.ConflictKindProviderSchema,
+			ServerIndex: 1,
+		},
+		{
+			Kind:        tf5muxserver.ConflictKindResource,
+			TypeName:    "test_resource",
+			ServerIndex: 1,
+		},
+		{
+			Kind:        tf5muxserver.ConflictKindDataSource,
+			TypeName:    "test_data_source",
+			ServerIndex: 1,
+		},
+		{
+			Kind:        tf5muxserver.ConflictKindResource,
+			TypeName:    "test_resource",
+			ServerIndex: 2,
+		},
+	}
+
+	testCases := map[string]struct {
+		policy                      tf5muxserver.ConflictResolution
+		expectedError               *conflict
+		expectedConflicts           []conflict
+		expectedDataSourceServer    int
+		expectedProviderSchemaIndex int
+		expectedResourceServer      int
+	}{
+		"error": {
+			policy: tf5muxserver.ConflictResolutionError,
+			expectedError: &conflict{
+				Kind:        tf5muxserver.ConflictKindProviderSchema,
+				ServerIndex: 1,
+			},
+		},
+		"first-server": {
+			policy:                      tf5muxserver.ConflictResolutionFirstServer,
+			expectedConflicts:           expectedConflicts,
+			expectedDataSourceServer:    0,
+			expectedProviderSchemaIndex: 0,
+			expectedResourceServer:      0,
+		},
+		"last-server": {
+			policy:                      tf5muxserver.ConflictResolutionLastServer,
+			expectedConflicts:           expectedConflicts,
+			expectedDataSourceServer:    1,
+			expectedProviderSchemaIndex: 1,
+			expectedResourceServer:      2,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			providerSchemas := []*tfprotov5.Schema{
+				newProviderSchema("attribute1"),
+				newProviderSchema("attribute2"),
+			}
+			testServers := []*tf5testserver.TestServer{
+				{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source": {},
+					},
+					ProviderSchema: providerSchemas[0],
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				},
+				{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source": {},
+					},
+					ProviderSchema: providerSchemas[1],
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				},
+				{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				},
+			}
+
+			var servers []func() tfprotov5.ProviderServer
+
+			for _, testServer := range testServers {
+				servers = append(servers, testServer.ProviderServer)
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{tf5muxserver.WithConflictResolution(testCase.policy)}, servers...)
+
+			if testCase.expectedError != nil {
+				var conflictErr *tf5muxserver.ConflictError
+
+				if !errors.As(err, &conflictErr) {
+					t.Fatalf("expected ConflictError, got: %v", err)
+				}
+
+				got := conflict{
+					Kind:        conflictErr.Kind,
+					TypeName:    conflictErr.TypeName,
+					ServerIndex: conflictErr.ServerIndex,
+				}
+
+				if diff := cmp.Diff(got, *testCase.expectedError); diff != "" {
+					t.Errorf("unexpected error difference: %s", diff)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var gotConflicts []conflict
+
+			for _, conflictErr := range muxServer.Conflicts() {
+				gotConflicts = append(gotConflicts, conflict{
+					Kind:        conflictErr.Kind,
+					TypeName:    conflictErr.TypeName,
+					ServerIndex: conflictErr.ServerIndex,
+				})
+			}
+
+			if diff := cmp.Diff(gotConflicts, testCase.expectedConflicts); diff != "" {
+				t.Errorf("unexpected conflicts difference: %s", diff)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if resp.Provider != providerSchemas[testCase.expectedProviderSchemaIndex] {
+				t.Errorf("expected provider schema from server%d", testCase.expectedProviderSchemaIndex+1)
+			}
+
+			_, err = muxServer.ProviderServer().ReadDataSource(ctx, &tfprotov5.ReadDataSourceRequest{
+				TypeName: "test_data_source",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			for num, testServer := range testServers {
+				expected := num == testCase.expectedDataSourceServer
+
+				if testServer.ReadDataSourceCalled["test_data_source"] != expected {
+					t.Errorf("expected ReadDataSource called %t on server%d", expected, num+1)
+				}
+
+				expected = num == testCase.expectedResourceServer
+
+				if testServer.ReadResourceCalled["test_resource"] != expected {
+					t.Errorf("expected ReadResource called %t on server%d", expected, num+1)
+				}
+			}
+		})
+	}
+}
+
+func TestWithConflictResolutionSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		policy        tf5muxserver.ConflictResolution
+		version       int64
+		expectedError string
+	}{
+		"error-mismatched": {
+			policy:        tf5muxserver.ConflictResolutionError,
+			version:       2,
+			expectedError: `resource "test_resource" is implemented by multiple servers; only one implementation allowed`,
+		},
+		"first-server-matching": {
+			policy:  tf5muxserver.ConflictResolutionFirstServer,
+			version: 1,
+		},
+		"first-server-mismatched": {
+			policy:  tf5muxserver.ConflictResolutionFirstServer,
+			version: 2,
+			expectedError: `resource "test_resource" schema version 2 of server 1 (*tf5testserver.TestServer) does not match schema version 1 of *tf5testserver.TestServer; ` +
+				"servers declaring the same resource must declare the same schema version",
+		},
+		"last-server-matching": {
+			policy:  tf5muxserver.ConflictResolutionLastServer,
+			version: 1,
+		},
+		"last-server-mismatched": {
+			policy:  tf5muxserver.ConflictResolutionLastServer,
+			version: 0,
+			expectedError: `resource "test_resource" schema version 0 of server 1 (*tf5testserver.TestServer) does not match schema version 1 of *tf5testserver.TestServer; ` +
+				"servers declaring the same resource must declare the same schema version",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tf5muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf5muxserver.ServerOption{tf5muxserver.WithConflictResolution(testCase.policy)},
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Version: 1,
+						},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Version: testCase.version,
+						},
+					},
+				}).ProviderServer,
+			)
+
+			if err != nil {
+				if err.Error() != testCase.expectedError {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error %q", testCase.expectedError)
+			}
+		})
+	}
+}
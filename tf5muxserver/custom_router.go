@@ -0,0 +1,38 @@
+package tf5muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// Router selects the server handling a request for a resource or data source
+// type, such as to partition the resources of one type across servers by a
+// field of the request configuration or state. The request is the RPC request
+// type, such as *tfprotov5.ReadResourceRequest. The returned server index is
+// the position of the server in the order given to NewMuxServer, excluding
+// servers excluded via the WithBestEffortSchema option.
+type Router func(ctx context.Context, req interface{}) (int, error)
+
+// customRoute returns the server selected by the Router for the request.
+func (s muxServer) customRoute(ctx context.Context, typeName string, router Router, req interface{}) (tfprotov5.ProviderServer, bool, error) {
+	serverIndex, err := router(ctx, req)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to route %q: %w", typeName, err)
+	}
+
+	servers := s.currentServers()
+
+	if serverIndex < 0 || serverIndex >= len(servers) {
+		return nil, false, fmt.Errorf("unable to route %q: router returned server index %d, expected 0 to %d", typeName, serverIndex, len(servers)-1)
+	}
+
+	logging.MuxTrace(ctx, "custom router selected server", map[string]interface{}{
+		logging.KeyTfMuxServerIndex: serverIndex,
+	})
+
+	return servers[serverIndex], true, nil
+}
@@ -0,0 +1,159 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithCustomRouter(t *testing.T) {
+	t.Parallel()
+
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name:     "region",
+					Type:     tftypes.String,
+					Required: true,
+				},
+			},
+		},
+	}
+	schemaType := schema.ValueType()
+
+	// regionRouter routes ReadResource requests by the region in the
+	// current state.
+	regionRouter := func(_ context.Context, req interface{}) (int, error) {
+		readReq, ok := req.(*tfprotov5.ReadResourceRequest)
+
+		if !ok {
+			return 0, fmt.Errorf("unexpected request type %T", req)
+		}
+
+		state, err := readReq.CurrentState.Unmarshal(schemaType)
+
+		if err != nil {
+			return 0, err
+		}
+
+		var attributes map[string]tftypes.Value
+
+		if err := state.As(&attributes); err != nil {
+			return 0, err
+		}
+
+		var region string
+
+		if err := attributes["region"].As(&region); err != nil {
+			return 0, err
+		}
+
+		switch region {
+		case "eu":
+			return 1, nil
+		case "us":
+			return 0, nil
+		case "unknown":
+			return 2, nil
+		}
+
+		return 0, errors.New("unsupported region")
+	}
+
+	testCases := map[string]struct {
+		region                string
+		expectedError         bool
+		expectedServer1Called bool
+		expectedServer2Called bool
+	}{
+		"eu": {
+			region:                "eu",
+			expectedServer2Called: true,
+		},
+		"router-error": {
+			region:        "ap",
+			expectedError: true,
+		},
+		"server-index-out-of-range": {
+			region:        "unknown",
+			expectedError: true,
+		},
+		"us": {
+			region:                "us",
+			expectedServer1Called: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server1 := &tf5testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource": schema,
+				},
+			}
+			server2 := &tf5testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource": schema,
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf5muxserver.ServerOption{
+					tf5muxserver.WithConflictResolution(tf5muxserver.ConflictResolutionFirstServer),
+					tf5muxserver.WithCustomRouter("test_resource", regionRouter),
+				},
+				server1.ProviderServer,
+				server2.ProviderServer,
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			currentState, err := tfprotov5.NewDynamicValue(schemaType, tftypes.NewValue(schemaType, map[string]tftypes.Value{
+				"region": tftypes.NewValue(tftypes.String, testCase.region),
+			}))
+
+			if err != nil {
+				t.Fatalf("unable to create state: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+				CurrentState: &currentState,
+				TypeName:     "test_resource",
+			})
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			if server1.ReadResourceCalled["test_resource"] != testCase.expectedServer1Called {
+				t.Errorf("expected server1 ReadResource called to be %t", testCase.expectedServer1Called)
+			}
+
+			if server2.ReadResourceCalled["test_resource"] != testCase.expectedServer2Called {
+				t.Errorf("expected server2 ReadResource called to be %t", testCase.expectedServer2Called)
+			}
+		})
+	}
+}
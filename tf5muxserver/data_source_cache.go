@@ -0,0 +1,175 @@
+package tf5muxserver
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// dataSourceCacheMaxEntries is the maximum number of cached ReadDataSource
+// responses.
+const dataSourceCacheMaxEntries = 1024
+
+// dataSourceCache caches ReadDataSource responses for a configured set of
+// data source types. Entries are keyed by type name and a hash of the request
+// Config and ProviderMeta and expire after the configured TTL. It is safe for
+// concurrent use.
+type dataSourceCache struct {
+	mu      sync.RWMutex
+	entries map[dataSourceCacheKey]dataSourceCacheEntry
+
+	// generation is incremented by clear, so responses to requests keyed
+	// before clearing are not stored.
+	generation uint64
+
+	ttl   time.Duration
+	types map[string]struct{}
+}
+
+type dataSourceCacheKey struct {
+	generation  uint64
+	typeName    string
+	requestHash [sha256.Size]byte
+}
+
+type dataSourceCacheEntry struct {
+	expires time.Time
+	resp    *tfprotov5.ReadDataSourceResponse
+}
+
+func newDataSourceCache(types []string, ttl time.Duration) *dataSourceCache {
+	cache := &dataSourceCache{
+		entries: make(map[dataSourceCacheKey]dataSourceCacheEntry),
+		ttl:     ttl,
+		types:   make(map[string]struct{}, len(types)),
+	}
+
+	for _, typeName := range types {
+		cache.types[typeName] = struct{}{}
+	}
+
+	return cache
+}
+
+// key returns the cache key for the request and whether the request data
+// source type is cached.
+func (c *dataSourceCache) key(req *tfprotov5.ReadDataSourceRequest) (dataSourceCacheKey, bool) {
+	if c == nil {
+		return dataSourceCacheKey{}, false
+	}
+
+	if _, ok := c.types[req.TypeName]; !ok {
+		return dataSourceCacheKey{}, false
+	}
+
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	key := dataSourceCacheKey{
+		generation: generation,
+		typeName:   req.TypeName,
+	}
+
+	// Identical values using different encodings hash differently, which
+	// only results in a cache miss.
+	hash := sha256.New()
+
+	for _, value := range []*tfprotov5.DynamicValue{req.Config, req.ProviderMeta} {
+		if value == nil {
+			writeLengthPrefixed(hash, nil)
+			writeLengthPrefixed(hash, nil)
+
+			continue
+		}
+
+		writeLengthPrefixed(hash, value.MsgPack)
+		writeLengthPrefixed(hash, value.JSON)
+	}
+
+	copy(key.requestHash[:], hash.Sum(nil))
+
+	return key, true
+}
+
+// get returns the unexpired response for the key, if any. Expired entries
+// are removed.
+func (c *dataSourceCache) get(key dataSourceCacheKey) (*tfprotov5.ReadDataSourceResponse, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().Before(entry.expires) {
+		return entry.resp, true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another request may have already replaced the expired entry.
+	if entry, ok := c.entries[key]; ok && !time.Now().Before(entry.expires) {
+		delete(c.entries, key)
+	}
+
+	return nil, false
+}
+
+// set stores the response for the key until the TTL expires. If the cache is
+// full, expired entries are removed, then all entries if it is still full.
+func (c *dataSourceCache) set(key dataSourceCacheKey, resp *tfprotov5.ReadDataSourceResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key.generation != c.generation {
+		return
+	}
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= dataSourceCacheMaxEntries {
+		now := time.Now()
+
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+
+		if len(c.entries) >= dataSourceCacheMaxEntries {
+			c.entries = make(map[dataSourceCacheKey]dataSourceCacheEntry)
+		}
+	}
+
+	c.entries[key] = dataSourceCacheEntry{
+		expires: time.Now().Add(c.ttl),
+		resp:    resp,
+	}
+}
+
+// clear removes all entries, such as when data source routing changes.
+func (c *dataSourceCache) clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[dataSourceCacheKey]dataSourceCacheEntry)
+	c.generation++
+}
+
+func writeLengthPrefixed(w io.Writer, b []byte) {
+	var length [8]byte
+
+	binary.BigEndian.PutUint64(length[:], uint64(len(b)))
+
+	_, _ = w.Write(length[:])
+	_, _ = w.Write(b)
+}
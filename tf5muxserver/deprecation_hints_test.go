@@ -0,0 +1,169 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// warningServer is a server whose PlanResourceChange and ReadResource
+// methods respond with a warning Diagnostic.
+type warningServer struct {
+	*tf5testserver.TestServer
+}
+
+func (s warningServer) PlanResourceChange(_ context.Context, _ *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	return &tfprotov5.PlanResourceChangeResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{
+			{
+				Severity: tfprotov5.DiagnosticSeverityWarning,
+				Summary:  "test warning summary",
+			},
+		},
+	}, nil
+}
+
+func (s warningServer) ReadResource(_ context.Context, _ *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	return &tfprotov5.ReadResourceResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{
+			{
+				Severity: tfprotov5.DiagnosticSeverityWarning,
+				Summary:  "test warning summary",
+			},
+		},
+	}, nil
+}
+
+func TestWithDeprecationHint(t *testing.T) {
+	t.Parallel()
+
+	servers := []func() tfprotov5.ProviderServer{
+		func() tfprotov5.ProviderServer {
+			return warningServer{
+				TestServer: &tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_old_resource": {},
+					},
+				},
+			}
+		},
+		func() tfprotov5.ProviderServer {
+			return warningServer{
+				TestServer: &tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_new_resource": {},
+					},
+				},
+			}
+		},
+	}
+
+	serverWarning := &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "test warning summary",
+	}
+	hintWarning := &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Deprecated Resource Type",
+		Detail: `The resource type "test_old_resource" is deprecated and replaced by the resource type "test_new_resource". ` +
+			"Migrate configurations to the replacement resource type, as the deprecated resource type may be removed in a future version.",
+	}
+
+	testCases := map[string]struct {
+		oldType             string
+		newType             string
+		typeName            string
+		expectedDiagnostics []*tfprotov5.Diagnostic
+		expectedError       string
+	}{
+		"new-type": {
+			oldType:  "test_old_resource",
+			newType:  "test_new_resource",
+			typeName: "test_new_resource",
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				serverWarning,
+			},
+		},
+		"old-type": {
+			oldType:  "test_old_resource",
+			newType:  "test_new_resource",
+			typeName: "test_old_resource",
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				serverWarning,
+				hintWarning,
+			},
+		},
+		"unknown-new-type": {
+			oldType:       "test_old_resource",
+			newType:       "test_unknown_resource",
+			expectedError: `unable to add deprecation hint for resource "test_old_resource": replacement resource "test_unknown_resource" isn't supported by any servers`,
+		},
+		"unknown-old-type": {
+			oldType:       "test_unknown_resource",
+			newType:       "test_new_resource",
+			expectedError: `unable to add deprecation hint for resource "test_unknown_resource": type isn't supported by any servers`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+				ctx,
+				[]tf5muxserver.ServerOption{
+					tf5muxserver.WithDeprecationHint(testCase.oldType, testCase.newType),
+				},
+				servers...,
+			)
+
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if err.Error() != testCase.expectedError {
+					t.Fatalf("expected error %q, got %q", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			planResp, err := muxServer.ProviderServer().PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+				TypeName: testCase.typeName,
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected PlanResourceChange error: %s", err)
+			}
+
+			if diff := cmp.Diff(planResp.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected PlanResourceChange diagnostics difference: %s", diff)
+			}
+
+			readResp, err := muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+				TypeName: testCase.typeName,
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected ReadResource error: %s", err)
+			}
+
+			if diff := cmp.Diff(readResp.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected ReadResource diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package tf5muxserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// deprecationsDiagnostic returns a warning Diagnostic listing the deprecated
+// provider schema attributes and nested blocks, managed resources, and data
+// sources of the muxed server, or nil if nothing is deprecated.
+func (s muxServer) deprecationsDiagnostic() *tfprotov5.Diagnostic {
+	var deprecations []string
+
+	if s.providerSchema != nil {
+		paths := deprecatedSchemaBlockPaths("", s.providerSchema.Block)
+
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			deprecations = append(deprecations, fmt.Sprintf("provider %s", path))
+		}
+	}
+
+	for _, resourceType := range sortedSchemaTypeNames(s.resourceSchemas) {
+		if schema := s.resourceSchemas[resourceType]; schema != nil && schema.Block != nil && schema.Block.Deprecated {
+			deprecations = append(deprecations, fmt.Sprintf("resource %q", resourceType))
+		}
+	}
+
+	for _, dataSourceType := range sortedSchemaTypeNames(s.dataSourceSchemas) {
+		if schema := s.dataSourceSchemas[dataSourceType]; schema != nil && schema.Block != nil && schema.Block.Deprecated {
+			deprecations = append(deprecations, fmt.Sprintf("data source %q", dataSourceType))
+		}
+	}
+
+	if len(deprecations) == 0 {
+		return nil
+	}
+
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Deprecated Provider Features",
+		Detail: "The provider declares the following deprecated features, which may be removed in a future version. " +
+			"Configurations using them should be migrated.\n\n  - " + strings.Join(deprecations, "\n  - "),
+	}
+}
+
+// deprecatedSchemaBlockPaths returns descriptions of the deprecated
+// attributes and nested blocks within the block, including within nested
+// blocks, such as `attribute "feature.enabled"`.
+func deprecatedSchemaBlockPaths(prefix string, block *tfprotov5.SchemaBlock) []string {
+	if block == nil {
+		return nil
+	}
+
+	var result []string
+
+	for _, attribute := range block.Attributes {
+		if attribute == nil || !attribute.Deprecated {
+			continue
+		}
+
+		result = append(result, fmt.Sprintf("attribute %q", prefix+attribute.Name))
+	}
+
+	for _, nestedBlock := range block.BlockTypes {
+		if nestedBlock == nil {
+			continue
+		}
+
+		path := prefix + nestedBlock.TypeName
+
+		if nestedBlock.Block != nil && nestedBlock.Block.Deprecated {
+			result = append(result, fmt.Sprintf("block %q", path))
+		}
+
+		result = append(result, deprecatedSchemaBlockPaths(path+".", nestedBlock.Block)...)
+	}
+
+	return result
+}
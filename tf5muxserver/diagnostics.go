@@ -0,0 +1,80 @@
+package tf5muxserver
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// hasErrorDiagnostics returns true if any Diagnostic has severity error.
+func hasErrorDiagnostics(diags []*tfprotov5.Diagnostic) bool {
+	for _, diag := range diags {
+		if diag == nil {
+			continue
+		}
+
+		if diag.Severity == tfprotov5.DiagnosticSeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diagnosticsWithProvenance returns copies of the Diagnostics with the Go
+// type of the server which produced them appended to each Detail. Nil
+// Diagnostics are preserved.
+func diagnosticsWithProvenance(diags []*tfprotov5.Diagnostic, server tfprotov5.ProviderServer) []*tfprotov5.Diagnostic {
+	if diags == nil {
+		return nil
+	}
+
+	result := make([]*tfprotov5.Diagnostic, 0, len(diags))
+
+	for _, diag := range diags {
+		if diag == nil {
+			result = append(result, nil)
+			continue
+		}
+
+		diagCopy := *diag
+		provenance := fmt.Sprintf("(via %T)", server)
+
+		if diagCopy.Detail == "" {
+			diagCopy.Detail = provenance
+		} else {
+			diagCopy.Detail += " " + provenance
+		}
+
+		result = append(result, &diagCopy)
+	}
+
+	return result
+}
+
+// diagnosticsWithWarningsAsErrors returns copies of the Diagnostics with
+// warning severity promoted to error severity. Nil Diagnostics are preserved.
+func diagnosticsWithWarningsAsErrors(diags []*tfprotov5.Diagnostic) []*tfprotov5.Diagnostic {
+	if diags == nil {
+		return nil
+	}
+
+	result := make([]*tfprotov5.Diagnostic, 0, len(diags))
+
+	for _, diag := range diags {
+		if diag == nil {
+			result = append(result, nil)
+			continue
+		}
+
+		diagCopy := *diag
+
+		if diagCopy.Severity == tfprotov5.DiagnosticSeverityWarning {
+			diagCopy.Severity = tfprotov5.DiagnosticSeverityError
+		}
+
+		result = append(result, &diagCopy)
+	}
+
+	return result
+}
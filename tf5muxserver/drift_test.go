@@ -0,0 +1,126 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestMuxServerCheckDrift(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		mutate          func(server1 *tf5testserver.TestServer, server2 *tf5testserver.TestServer)
+		expectedReports []tf5muxserver.DriftReport
+		expectedError   bool
+	}{
+		"no-drift": {
+			mutate: func(_ *tf5testserver.TestServer, _ *tf5testserver.TestServer) {},
+		},
+		"drift": {
+			mutate: func(_ *tf5testserver.TestServer, server2 *tf5testserver.TestServer) {
+				server2.DataSourceSchemas = map[string]*tfprotov5.Schema{
+					"test_data_source_server2_added": {},
+				}
+				server2.ResourceSchemas = map[string]*tfprotov5.Schema{
+					"test_resource_server2_added": {},
+				}
+			},
+			expectedReports: []tf5muxserver.DriftReport{
+				{
+					ServerIndex:        1,
+					AddedResources:     []string{"test_resource_server2_added"},
+					RemovedResources:   []string{"test_resource_server2"},
+					AddedDataSources:   []string{"test_data_source_server2_added"},
+					RemovedDataSources: []string{"test_data_source_server2"},
+				},
+			},
+		},
+		"error": {
+			mutate: func(server1 *tf5testserver.TestServer, _ *tf5testserver.TestServer) {
+				server1.GetProviderSchemaDiagnostics = []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "test error summary",
+					},
+				}
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server1 := &tf5testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource_server1": {},
+				},
+			}
+			server2 := &tf5testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov5.Schema{
+					"test_data_source_server2": {},
+				},
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource_server2": {},
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServer(context.Background(), server1.ProviderServer, server2.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			testCase.mutate(server1, server2)
+
+			reports, err := muxServer.CheckDrift(context.Background())
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			if diff := cmp.Diff(reports, testCase.expectedReports); diff != "" {
+				t.Errorf("unexpected reports difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDriftReportDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	report := tf5muxserver.DriftReport{
+		ServerIndex:        1,
+		AddedResources:     []string{"test_resource_added"},
+		RemovedDataSources: []string{"test_data_source_removed"},
+	}
+
+	expected := &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Provider Schema Drift",
+		Detail: "Server 1 changed its schema after the provider started, so requests for the following types may be routed incorrectly. " +
+			"This is always an issue in the provider implementation and should be reported to the provider developers.\n\n" +
+			"  - added resource \"test_resource_added\"\n" +
+			"  - removed data source \"test_data_source_removed\"",
+	}
+
+	if diff := cmp.Diff(report.Diagnostic(), expected); diff != "" {
+		t.Errorf("unexpected diagnostic difference: %s", diff)
+	}
+}
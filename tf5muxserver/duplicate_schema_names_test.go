@@ -0,0 +1,185 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithDuplicateSchemaNameValidation(t *testing.T) {
+	t.Parallel()
+
+	attribute := func(name string) *tfprotov5.SchemaAttribute {
+		return &tfprotov5.SchemaAttribute{
+			Name:     name,
+			Type:     tftypes.String,
+			Optional: true,
+		}
+	}
+
+	testCases := map[string]struct {
+		enabled       bool
+		servers       []func() tfprotov5.ProviderServer
+		expectedError string
+	}{
+		"disabled": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{
+									attribute("id"),
+									attribute("id"),
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+		"duplicate-attribute": {
+			enabled: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{
+									attribute("id"),
+									attribute("name"),
+									attribute("id"),
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `duplicate schema names, which Terraform rejects: resource "test_resource" schema declares "id" more than once`,
+		},
+		"duplicate-attribute-and-block": {
+			enabled: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source": {
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{
+									attribute("filter"),
+								},
+								BlockTypes: []*tfprotov5.SchemaNestedBlock{
+									{
+										TypeName: "filter",
+										Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+										Block:    &tfprotov5.SchemaBlock{},
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `duplicate schema names, which Terraform rejects: data source "test_data_source" schema declares "filter" more than once`,
+		},
+		"duplicate-nested-block-attribute": {
+			enabled: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ProviderSchema: &tfprotov5.Schema{
+						Block: &tfprotov5.SchemaBlock{
+							BlockTypes: []*tfprotov5.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov5.SchemaNestedBlockNestingModeSingle,
+									Block: &tfprotov5.SchemaBlock{
+										Attributes: []*tfprotov5.SchemaAttribute{
+											attribute("enabled"),
+											attribute("enabled"),
+										},
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{
+									attribute("id"),
+									attribute("id"),
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `duplicate schema names, which Terraform rejects: provider schema declares "feature.enabled" more than once; resource "test_resource" schema declares "id" more than once`,
+		},
+		"unique": {
+			enabled: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{
+									attribute("id"),
+									attribute("name"),
+								},
+								BlockTypes: []*tfprotov5.SchemaNestedBlock{
+									{
+										TypeName: "setting",
+										Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+										Block: &tfprotov5.SchemaBlock{
+											Attributes: []*tfprotov5.SchemaAttribute{
+												attribute("name"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tf5muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf5muxserver.ServerOption{
+					tf5muxserver.WithDuplicateSchemaNameValidation(testCase.enabled),
+				},
+				testCase.servers...,
+			)
+
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if err.Error() != testCase.expectedError {
+					t.Fatalf("expected error %q, got %q", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+		})
+	}
+}
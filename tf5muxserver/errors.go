@@ -0,0 +1,19 @@
+package tf5muxserver
+
+import (
+	"fmt"
+)
+
+// NoProviderSchemaError is returned during muxed server creation when the
+// WithRequireProviderSchema option is enabled and no server declared a
+// provider schema.
+type NoProviderSchemaError struct {
+	// ServerCount is the number of servers given during muxed server
+	// creation.
+	ServerCount int
+}
+
+// Error returns a description of the error.
+func (e *NoProviderSchemaError) Error() string {
+	return fmt.Sprintf("none of the %d servers declared a provider schema; at least one server must declare the provider schema", e.ServerCount)
+}
@@ -0,0 +1,166 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// concurrencyTracker records the number of in-flight downstream calls.
+type concurrencyTracker struct {
+	inFlight int64
+	max      int64
+}
+
+func (t *concurrencyTracker) track() {
+	inFlight := atomic.AddInt64(&t.inFlight, 1)
+
+	for {
+		max := atomic.LoadInt64(&t.max)
+
+		if inFlight <= max || atomic.CompareAndSwapInt64(&t.max, max, inFlight) {
+			break
+		}
+	}
+
+	time.Sleep(time.Millisecond)
+
+	atomic.AddInt64(&t.inFlight, -1)
+}
+
+func TestWithMaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		maxConcurrency int
+	}{
+		"sequential": {
+			maxConcurrency: 1,
+		},
+		"limited": {
+			maxConcurrency: 3,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			tracker := &concurrencyTracker{}
+
+			var servers []func() tfprotov5.ProviderServer
+
+			for i := 0; i < 5; i++ {
+				servers = append(servers, (&tf5testserver.TestServer{}).ProviderServer)
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+				ctx,
+				[]tf5muxserver.ServerOption{
+					tf5muxserver.WithMaxConcurrency(testCase.maxConcurrency),
+					tf5muxserver.WithWarmupFunc(func(_ context.Context, _ tfprotov5.ProviderServer) error {
+						tracker.track()
+
+						return nil
+					}),
+				},
+				servers...,
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			var wg sync.WaitGroup
+
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					_ = muxServer.Warmup(ctx)
+				}()
+			}
+
+			wg.Wait()
+
+			if got := atomic.LoadInt64(&tracker.max); got > int64(testCase.maxConcurrency) {
+				t.Errorf("expected at most %d concurrent downstream calls, got %d", testCase.maxConcurrency, got)
+			}
+		})
+	}
+}
+
+func TestWithMaxConcurrencyStopProvider(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options []tf5muxserver.ServerOption
+	}{
+		"concurrent": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithConcurrentStopProvider(true),
+			},
+		},
+		"sequential": {},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			server := &tf5testserver.TestServer{}
+			warming := make(chan struct{})
+			unblock := make(chan struct{})
+
+			defer close(unblock)
+
+			options := append([]tf5muxserver.ServerOption{
+				tf5muxserver.WithMaxConcurrency(1),
+				tf5muxserver.WithWarmupFunc(func(_ context.Context, _ tfprotov5.ProviderServer) error {
+					close(warming)
+					<-unblock
+
+					return nil
+				}),
+			}, testCase.options...)
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, options, server.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			// Warmup holds the only fan-out call permitted until unblocked.
+			go func() {
+				_ = muxServer.Warmup(ctx)
+			}()
+
+			<-warming
+
+			stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			if _, err := muxServer.ProviderServer().StopProvider(stopCtx, &tfprotov5.StopProviderRequest{}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !server.StopProviderCalled {
+				t.Errorf("expected StopProvider to be called")
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// importFeatureServer is a server which advertises handling all import
+// operations.
+type importFeatureServer struct {
+	*tf5testserver.TestServer
+}
+
+func (s importFeatureServer) Features() []tf5muxserver.Feature {
+	return []tf5muxserver.Feature{tf5muxserver.FeatureImport}
+}
+
+func TestMuxServerImportResourceStateFeatureProvider(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		typeName       string
+		featureServer  bool
+		expectedServer int
+		expectedError  bool
+	}{
+		"declared": {
+			typeName:       "test_resource_server1",
+			featureServer:  true,
+			expectedServer: 0,
+		},
+		"undeclared": {
+			typeName:       "test_resource_undeclared",
+			featureServer:  true,
+			expectedServer: 1,
+		},
+		"declared-data-source": {
+			typeName:      "test_data_source_server1",
+			featureServer: true,
+			expectedError: true,
+		},
+		"undeclared-no-feature-server": {
+			typeName:      "test_resource_undeclared",
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf5testserver.TestServer{
+				{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source_server1": {},
+					},
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server1": {},
+					},
+				},
+				{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server2": {},
+					},
+				},
+			}
+
+			server2 := servers[1].ProviderServer
+
+			if testCase.featureServer {
+				server2 = func() tfprotov5.ProviderServer {
+					return importFeatureServer{
+						TestServer: servers[1],
+					}
+				}
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServer(context.Background(), servers[0].ProviderServer, server2)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{
+				TypeName: testCase.typeName,
+			})
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			if !servers[testCase.expectedServer].ImportResourceStateCalled[testCase.typeName] {
+				t.Errorf("expected server %d ImportResourceState to be called", testCase.expectedServer)
+			}
+		})
+	}
+}
+
+func TestWithFeatureServerOverlapWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled             bool
+		expectedDiagnostics []*tfprotov5.Diagnostic
+	}{
+		"disabled": {},
+		"enabled": {
+			enabled: true,
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "Feature Server Declares Overlapping Type",
+					Detail: "Server 1 (tf5muxserver_test.importFeatureServer) advertises features for undeclared type names and declares the resource type \"test_resource\", " +
+						"which is routed to server 0 (*tf5testserver.TestServer). The declaration of server 1 is ignored.",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf5testserver.TestServer{
+				{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				},
+				{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource":         {},
+						"test_resource_server2": {},
+					},
+				},
+			}
+
+			featureServer := func() tfprotov5.ProviderServer {
+				return importFeatureServer{
+					TestServer: servers[1],
+				}
+			}
+
+			options := []tf5muxserver.ServerOption{
+				tf5muxserver.WithConflictResolution(tf5muxserver.ConflictResolutionFirstServer),
+				tf5muxserver.WithFeatureServerOverlapWarnings(testCase.enabled),
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), options, servers[0].ProviderServer, featureServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+
+			// The type owned by the first server is never routed to the
+			// feature server.
+			_, err = muxServer.ProviderServer().ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !servers[0].ImportResourceStateCalled["test_resource"] {
+				t.Errorf("expected server 0 ImportResourceState to be called")
+			}
+
+			if servers[1].ImportResourceStateCalled["test_resource"] {
+				t.Errorf("unexpected server 1 ImportResourceState call")
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package tf5muxserver
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// importedResourcesDiagnostics returns Diagnostics for the imported resources
+// returned by the server handling the import of the type name. Resource types
+// which are not implemented by any server result in an error Diagnostic, as
+// the imported resources cannot be managed. Resource types implemented by a
+// server other than the server implementing the type name result in a
+// warning Diagnostic, or an error Diagnostic if the
+// WithCrossServerImportErrors option is enabled, as later operations on the
+// imported resources are routed to the other server. Resource types with a
+// router configured via the WithCustomRouter option are not checked, as
+// their routing depends on each request, and the server implementing each
+// resource type is only checked when the type name is implemented by a
+// server, rather than handled via FeatureImport.
+func (s muxServer) importedResourcesDiagnostics(typeName string, server tfprotov5.ProviderServer, importedResources []*tfprotov5.ImportedResource) []*tfprotov5.Diagnostic {
+	var diags []*tfprotov5.Diagnostic
+
+	crossServerSeverity := tfprotov5.DiagnosticSeverityWarning
+
+	if s.options.crossServerImportErrors {
+		crossServerSeverity = tfprotov5.DiagnosticSeverityError
+	}
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	importerIndex, importerOk := s.resourceServerIndex[typeName]
+
+	if _, ok := s.options.customRouters[typeName]; ok {
+		importerOk = false
+	}
+
+	for _, importedResource := range importedResources {
+		if importedResource == nil {
+			continue
+		}
+
+		if _, ok := s.options.customRouters[importedResource.TypeName]; ok {
+			continue
+		}
+
+		ownerIndex, ok := s.resourceServerIndex[importedResource.TypeName]
+
+		if !ok {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Unsupported Imported Resource Type",
+				Detail: fmt.Sprintf("The import of %q by %T returned a resource of type %q, which is not implemented by any server of the provider, so it cannot be managed. ", typeName, server, importedResource.TypeName) +
+					"This is always an issue in the provider implementation and should be reported to the provider developers.",
+			})
+
+			continue
+		}
+
+		if !importerOk || ownerIndex == importerIndex {
+			continue
+		}
+
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: crossServerSeverity,
+			Summary:  "Imported Resource Type Implemented by Another Server",
+			Detail: fmt.Sprintf("The import of %q by %T returned a resource of type %q, which is implemented by %T. ", typeName, server, importedResource.TypeName, s.servers[ownerIndex]) +
+				"Later operations on the imported resource are handled by that server, which may not support the imported state. " +
+				"This is always an issue in the provider implementation and should be reported to the provider developers.",
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,71 @@
+package tf5muxserver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// validateInvariants returns an error if the routing and schemas of the muxed
+// server are inconsistent: every routed type must have a schema and a valid
+// server index, and every type with a schema must be routed. This catches
+// bugs in muxed server creation rather than failing individual requests.
+func (s muxServer) validateInvariants() error {
+	var problems []string
+
+	problems = append(problems, typeInvariantProblems("data source", s.dataSources, s.dataSourceSchemas, s.dataSourceServerIndex, len(s.servers))...)
+	problems = append(problems, typeInvariantProblems("resource", s.resources, s.resourceSchemas, s.resourceServerIndex, len(s.servers))...)
+
+	if len(s.serverProviderSchemas) != len(s.servers) {
+		problems = append(problems, fmt.Sprintf("%d server provider schemas for %d servers", len(s.serverProviderSchemas), len(s.servers)))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errors.New("muxed server invariants violated: " + strings.Join(problems, "; "))
+}
+
+// typeInvariantProblems returns a sorted description of each inconsistency
+// between the routing, schemas, and server indexes of one kind of type.
+func typeInvariantProblems(kind string, routing map[string]tfprotov5.ProviderServer, schemas map[string]*tfprotov5.Schema, serverIndexes map[string]int, serverCount int) []string {
+	var problems []string
+
+	for typeName := range routing {
+		if _, ok := schemas[typeName]; !ok {
+			problems = append(problems, fmt.Sprintf("%s %q is routed but has no schema", kind, typeName))
+		}
+
+		serverIndex, ok := serverIndexes[typeName]
+
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s %q is routed but has no server index", kind, typeName))
+
+			continue
+		}
+
+		if serverIndex < 0 || serverIndex >= serverCount {
+			problems = append(problems, fmt.Sprintf("%s %q has server index %d, expected 0 to %d", kind, typeName, serverIndex, serverCount-1))
+		}
+	}
+
+	for typeName := range schemas {
+		if _, ok := routing[typeName]; !ok {
+			problems = append(problems, fmt.Sprintf("%s %q has a schema but is not routed", kind, typeName))
+		}
+	}
+
+	for typeName := range serverIndexes {
+		if _, ok := routing[typeName]; !ok {
+			problems = append(problems, fmt.Sprintf("%s %q has a server index but is not routed", kind, typeName))
+		}
+	}
+
+	sort.Strings(problems)
+
+	return problems
+}
@@ -0,0 +1,36 @@
+package tf5muxserver
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// PreviewMerge returns the resource type and data source type routing which
+// NewMuxServer would create for the given servers, mapping each type name to
+// the index of the server implementing it, such as for code generation tools
+// which need the routing but not a muxed server. The GetProviderSchema method
+// of each server is called and validated in the same manner as NewMuxServer,
+// so an error is returned if the servers cannot be muxed, such as when a type
+// name is implemented by multiple servers.
+func PreviewMerge(ctx context.Context, servers ...func() tfprotov5.ProviderServer) (map[string]int, map[string]int, error) {
+	muxServer, err := NewMuxServer(ctx, servers...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources := make(map[string]int, len(muxServer.resourceServerIndex))
+
+	for resourceType, serverIndex := range muxServer.resourceServerIndex {
+		resources[resourceType] = serverIndex
+	}
+
+	dataSources := make(map[string]int, len(muxServer.dataSourceServerIndex))
+
+	for dataSourceType, serverIndex := range muxServer.dataSourceServerIndex {
+		dataSources[dataSourceType] = serverIndex
+	}
+
+	return resources, dataSources, nil
+}
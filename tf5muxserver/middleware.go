@@ -0,0 +1,68 @@
+package tf5muxserver
+
+import (
+	"context"
+	"fmt"
+)
+
+// RPCHandler handles a downstream server call of the given RPC, such as
+// "ReadResource". The request is the RPC request type, such as
+// *tfprotov5.ReadResourceRequest, and the response must be the matching RPC
+// response type, such as *tfprotov5.ReadResourceResponse.
+type RPCHandler func(ctx context.Context, rpc string, req interface{}) (interface{}, error)
+
+// Middleware wraps an RPCHandler to implement cross-cutting behaviors, such
+// as logging, metrics, or retries, around downstream server calls.
+// Middleware must call the next RPCHandler with a request of the same type
+// it was given, or return a response of the matching RPC response type
+// without calling the next RPCHandler.
+type Middleware func(next RPCHandler) RPCHandler
+
+// callServer calls the downstream server method with the request, wrapped by
+// the middleware configured via WithMiddleware. If the
+// WithDynamicValueRoundTripValidation option is enabled, DynamicValues are
+// validated closest to the server. If the WithStrictResponseFields option is
+// enabled, responses are checked closest to the server.
+func callServer[Req any, Resp any](ctx context.Context, s muxServer, rpc string, req *Req, call func(context.Context, *Req) (*Resp, error)) (*Resp, error) {
+	middleware := s.options.middleware
+
+	if s.options.dynamicValueRoundTripValidation {
+		middleware = append(middleware[:len(middleware):len(middleware)], s.dynamicValueRoundTripMiddleware)
+	}
+
+	if s.options.strictResponseFields {
+		middleware = append(middleware[:len(middleware):len(middleware)], strictResponseFieldsMiddleware(knownResponseFields))
+	}
+
+	if len(middleware) == 0 {
+		return call(ctx, req)
+	}
+
+	handler := RPCHandler(func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+		typedReq, ok := req.(*Req)
+
+		if !ok {
+			return nil, fmt.Errorf("middleware passed unexpected %s request type %T, expected %T", rpc, req, typedReq)
+		}
+
+		return call(ctx, typedReq)
+	})
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	resp, err := handler(ctx, rpc, req)
+
+	if resp == nil {
+		return nil, err
+	}
+
+	typedResp, ok := resp.(*Resp)
+
+	if !ok {
+		return nil, fmt.Errorf("middleware returned unexpected %s response type %T, expected %T", rpc, resp, typedResp)
+	}
+
+	return typedResp, err
+}
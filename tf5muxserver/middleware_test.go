@@ -0,0 +1,176 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var calls []string
+
+	recordingMiddleware := func(name string) tf5muxserver.Middleware {
+		return func(next tf5muxserver.RPCHandler) tf5muxserver.RPCHandler {
+			return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+				mu.Lock()
+				calls = append(calls, name+" "+rpc)
+				mu.Unlock()
+
+				return next(ctx, rpc, req)
+			}
+		}
+	}
+
+	servers := []func() tfprotov5.ProviderServer{
+		(&tf5testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource": {},
+			},
+		}).ProviderServer,
+		(&tf5testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov5.Schema{
+				"test_data_source": {},
+			},
+		}).ProviderServer,
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+		ctx,
+		[]tf5muxserver.ServerOption{
+			tf5muxserver.WithMiddleware(recordingMiddleware("outer")),
+			tf5muxserver.WithMiddleware(recordingMiddleware("inner")),
+		},
+		servers...,
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName: "test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !servers[0]().(*tf5testserver.TestServer).ReadResourceCalled["test_resource"] {
+		t.Errorf("expected test_resource ReadResource to be called on server1")
+	}
+
+	_, err = muxServer.ProviderServer().ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedCalls := []string{
+		"outer ReadResource",
+		"inner ReadResource",
+		"outer ConfigureProvider",
+		"inner ConfigureProvider",
+		"outer ConfigureProvider",
+		"inner ConfigureProvider",
+	}
+
+	if diff := cmp.Diff(calls, expectedCalls); diff != "" {
+		t.Errorf("unexpected middleware calls difference: %s", diff)
+	}
+}
+
+func TestWithMiddlewareTypes(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		middleware       tf5muxserver.Middleware
+		expectedResponse *tfprotov5.ReadResourceResponse
+		expectedError    string
+	}{
+		"short-circuit-response": {
+			middleware: func(next tf5muxserver.RPCHandler) tf5muxserver.RPCHandler {
+				return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+					return &tfprotov5.ReadResourceResponse{
+						Private: []byte("middleware"),
+					}, nil
+				}
+			},
+			expectedResponse: &tfprotov5.ReadResourceResponse{
+				Private: []byte("middleware"),
+			},
+		},
+		"unexpected-request-type": {
+			middleware: func(next tf5muxserver.RPCHandler) tf5muxserver.RPCHandler {
+				return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+					return next(ctx, rpc, &tfprotov5.ReadDataSourceRequest{})
+				}
+			},
+			expectedError: "middleware passed unexpected ReadResource request type *tfprotov5.ReadDataSourceRequest, expected *tfprotov5.ReadResourceRequest",
+		},
+		"unexpected-response-type": {
+			middleware: func(next tf5muxserver.RPCHandler) tf5muxserver.RPCHandler {
+				return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+					return &tfprotov5.ReadDataSourceResponse{}, nil
+				}
+			},
+			expectedError: "middleware returned unexpected ReadResource response type *tfprotov5.ReadDataSourceResponse, expected *tfprotov5.ReadResourceResponse",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+				ctx,
+				[]tf5muxserver.ServerOption{tf5muxserver.WithMiddleware(testCase.middleware)},
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			got, err := muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+			}
+
+			if err == nil && testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			if diff := cmp.Diff(got, testCase.expectedResponse); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package tf5muxserver
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
@@ -23,16 +26,100 @@ type muxServer struct {
 	// Underlying servers for requests that should be handled by all servers
 	servers []tfprotov5.ProviderServer
 
+	// Server functions and the index of the server implementing each type,
+	// used to create new servers if server reuse is disabled
+	serverFuncs           []func() tfprotov5.ProviderServer
+	dataSourceServerIndex map[string]int
+	resourceServerIndex   map[string]int
+
+	// Guards the routing for data source and resource types, which may be
+	// changed via Reroute()
+	routingMu *sync.RWMutex
+
+	// Provider schemas as declared by each server, indexed the same as
+	// servers. Servers which did not declare a provider schema are nil.
+	serverProviderSchemas []*tfprotov5.Schema
+
+	// Provider meta schemas as declared by each server, indexed the same as
+	// servers. Servers which did not declare a provider meta schema are nil.
+	serverProviderMetaSchemas []*tfprotov5.Schema
+
+	// Type name prefixes of each server, indexed the same as servers, as
+	// configured via WithNamespace(). Servers without a prefix are empty.
+	serverNamespaces []string
+
 	// Schemas are cached during server creation
 	dataSourceSchemas  map[string]*tfprotov5.Schema
 	providerMetaSchema *tfprotov5.Schema
 	providerSchema     *tfprotov5.Schema
 	resourceSchemas    map[string]*tfprotov5.Schema
+	serverCapabilities *tfprotov5.ServerCapabilities
+
+	// Warnings about servers excluded via WithBestEffortSchema()
+	schemaDiagnostics []*tfprotov5.Diagnostic
+
+	// Optional behaviors, configured via ServerOption
+	options serverOptions
+
+	// Type name routing results, exposed via Stats()
+	routingStats *routingStats
+
+	// Limits downstream server calls made by fan-out operations
+	fanOutLimiter fanOutLimiter
+
+	// ReadDataSource responses, if enabled via WithDataSourceCache()
+	dataSourceCache *dataSourceCache
+
+	// Schema declarations which conflicted across servers, if resolved via
+	// WithConflictResolution()
+	conflicts []*ConflictError
+
+	// RPC timeouts keyed by type name, if read from servers via
+	// WithTimeoutHints()
+	typeTimeouts map[string]time.Duration
+
+	// Downstream server call latencies, if enabled via WithLatencyMetrics()
+	// and exposed via Metrics()
+	latencyMetrics *latencyMetrics
 }
 
 // ProviderServer is a function compatible with tf6server.Serve.
+//
+// By default, the servers created by NewMuxServer are reused. If the
+// WithServerReuse option is disabled, each call creates new servers.
+//
+// ProviderServer is safe for concurrent use, such as when Terraform opens
+// multiple connections. The muxed server state shared between calls, such as
+// routing statistics, latency metrics, the data source cache, and the routing
+// changed via Reroute or ReplaceServer, is synchronized. If server reuse is
+// disabled, the server functions may be called concurrently.
 func (s muxServer) ProviderServer() tfprotov5.ProviderServer {
-	return s
+	if !s.options.serverReuseDisabled {
+		return s
+	}
+
+	result := s
+	result.dataSources = make(map[string]tfprotov5.ProviderServer, len(s.dataSources))
+	result.resources = make(map[string]tfprotov5.ProviderServer, len(s.resources))
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	result.servers = make([]tfprotov5.ProviderServer, 0, len(s.serverFuncs))
+
+	for _, serverFunc := range s.serverFuncs {
+		result.servers = append(result.servers, serverFunc())
+	}
+
+	for dataSourceType, serverIndex := range s.dataSourceServerIndex {
+		result.dataSources[dataSourceType] = result.servers[serverIndex]
+	}
+
+	for resourceType, serverIndex := range s.resourceServerIndex {
+		result.resources[resourceType] = result.servers[serverIndex]
+	}
+
+	return result
 }
 
 // NewMuxServer returns a muxed server that will route gRPC requests between
@@ -44,75 +131,556 @@ func (s muxServer) ProviderServer() tfprotov5.ProviderServer {
 //   - Only one provider implements each managed resource
 //   - Only one provider implements each data source
 //
+// The PlanDestroy server capability is only advertised if every provider
+// which implements a managed resource supports it, as the protocol only
+// supports the capability across all resources.
+//
 // The various schemas are cached and used to respond to the GetProviderSchema
 // method of the muxed server.
 func NewMuxServer(ctx context.Context, servers ...func() tfprotov5.ProviderServer) (muxServer, error) {
+	return NewMuxServerWithOptions(ctx, nil, servers...)
+}
+
+// NewMuxServerWithOptions returns a muxed server in the same manner as
+// NewMuxServer, with optional behaviors configured by the given options.
+func NewMuxServerWithOptions(ctx context.Context, opts []ServerOption, servers ...func() tfprotov5.ProviderServer) (muxServer, error) {
 	ctx = logging.InitContext(ctx)
+	result := newMuxServer(opts)
+	serverInstances := make([]tfprotov5.ProviderServer, 0, len(servers))
+
+	for _, serverFunc := range servers {
+		serverInstances = append(serverInstances, serverFunc())
+	}
+
+	schemaCacheHashValue, schemaResponses, restored := result.restoreSchemaCache(ctx, servers, serverInstances)
+
+	if restored {
+		// Overlapping feature server schemas are always conflicts, which
+		// prevent writing the schema cache file, so restored schemas have
+		// none.
+		return result, result.finalize(ctx, len(servers), nil)
+	}
+
+	featureServerSchemas, err := result.loadSchemas(ctx, servers, serverInstances, schemaResponses)
+
+	if err != nil {
+		return result, err
+	}
+
+	// Schemas are only cached if they are the same on every creation, so
+	// not after resolving conflicts or excluding servers.
+	cacheable := schemaCacheHashValue != "" && len(result.conflicts) == 0 && len(result.schemaDiagnostics) == 0
+
+	if err := result.finalize(ctx, len(servers), featureServerSchemas); err != nil {
+		return result, err
+	}
+
+	if cacheable {
+		cache, err := newSchemaCache(result, schemaCacheHashValue)
+
+		if err == nil {
+			err = writeSchemaCacheFile(result.options.schemaCacheFile, cache)
+		}
+
+		if err != nil {
+			logging.MuxWarn(ctx, "unable to write schema cache file", map[string]interface{}{logging.KeyError: err.Error()})
+		}
+	}
+
+	return result, nil
+}
+
+// newMuxServer returns a muxed server without servers, with optional
+// behaviors configured by the given options.
+func newMuxServer(opts []ServerOption) muxServer {
 	result := muxServer{
-		dataSources:       make(map[string]tfprotov5.ProviderServer),
-		dataSourceSchemas: make(map[string]*tfprotov5.Schema),
-		resources:         make(map[string]tfprotov5.ProviderServer),
-		resourceSchemas:   make(map[string]*tfprotov5.Schema),
+		dataSources:           make(map[string]tfprotov5.ProviderServer),
+		dataSourceSchemas:     make(map[string]*tfprotov5.Schema),
+		dataSourceServerIndex: make(map[string]int),
+		resources:             make(map[string]tfprotov5.ProviderServer),
+		resourceSchemas:       make(map[string]*tfprotov5.Schema),
+		resourceServerIndex:   make(map[string]int),
+		routingMu:             &sync.RWMutex{},
+		routingStats:          newRoutingStats(),
 	}
 
-	for _, serverFunc := range servers {
-		server := serverFunc()
+	for _, opt := range opts {
+		opt(&result.options)
+	}
+
+	// Copy the middleware before appending the built-in middleware, so the
+	// backing array of an option is never written.
+	middleware := make([]Middleware, len(result.options.middleware), len(result.options.middleware)+2)
+	copy(middleware, result.options.middleware)
+	result.options.middleware = middleware
+
+	if result.options.payloadSizeLogging {
+		result.options.middleware = append(result.options.middleware, payloadSizeLoggingMiddleware)
+	}
+
+	if result.options.latencyMetrics {
+		result.latencyMetrics = newLatencyMetrics(result.options.latencyBuckets)
+		result.options.middleware = append(result.options.middleware, result.latencyMetrics.middleware)
+	}
+
+	maxConcurrency := result.options.maxConcurrency
+
+	if maxConcurrency < 1 {
+		maxConcurrency = runtime.GOMAXPROCS(0)
+	}
+
+	result.fanOutLimiter = newFanOutLimiter(maxConcurrency)
+
+	if len(result.options.dataSourceCacheTypes) > 0 && result.options.dataSourceCacheTTL > 0 {
+		result.dataSourceCache = newDataSourceCache(result.options.dataSourceCacheTypes, result.options.dataSourceCacheTTL)
+	}
+
+	return result
+}
 
+// restoreSchemaCache restores the muxed server schemas and routing from the
+// file configured via the WithSchemaCacheFile option, returning true if they
+// were restored. The hash identifying the file is returned for writing the
+// file, or an empty string if the option is not configured, along with any
+// GetProviderSchema responses retrieved to compute the hash, keyed by server
+// index.
+func (s *muxServer) restoreSchemaCache(ctx context.Context, servers []func() tfprotov5.ProviderServer, serverInstances []tfprotov5.ProviderServer) (string, map[int]*tfprotov5.GetProviderSchemaResponse, bool) {
+	if s.options.schemaCacheFile == "" {
+		return "", nil, false
+	}
+
+	var contentHashes []string
+	var schemaResponses map[int]*tfprotov5.GetProviderSchemaResponse
+	var err error
+
+	if s.options.schemaCacheContentHash {
+		contentHashes, schemaResponses, err = schemaContentHashes(ctx, serverInstances)
+	}
+
+	if err != nil {
+		logging.MuxWarn(ctx, "unable to use schema cache file, retrieving schemas from servers", map[string]interface{}{logging.KeyError: err.Error()})
+
+		return "", schemaResponses, false
+	}
+
+	hash := schemaCacheHash(s.options, serverInstances, contentHashes)
+	cache, err := readSchemaCacheFile(s.options.schemaCacheFile, hash)
+
+	if err == nil && cache != nil {
+		err = cache.restore(s, serverInstances)
+	}
+
+	if err != nil {
+		logging.MuxWarn(ctx, "unable to use schema cache file, retrieving schemas from servers", map[string]interface{}{logging.KeyError: err.Error()})
+
+		return hash, schemaResponses, false
+	}
+
+	if cache == nil {
+		return hash, schemaResponses, false
+	}
+
+	logging.MuxDebug(ctx, "using schemas from schema cache file")
+
+	s.serverFuncs = append(s.serverFuncs, servers...)
+
+	for serverIndex := range s.servers {
+		s.serverNamespaces = append(s.serverNamespaces, s.options.namespaces[serverIndex])
+	}
+
+	return hash, schemaResponses, true
+}
+
+// loadSchemas retrieves the schemas of the servers, reusing the given
+// GetProviderSchema responses keyed by server index, and assembles the
+// muxed server schemas and routing. The schemas of servers implementing
+// FeatureProvider are returned keyed by routing index, if enabled via the
+// WithFeatureServerOverlapWarnings option.
+func (s *muxServer) loadSchemas(ctx context.Context, servers []func() tfprotov5.ProviderServer, serverInstances []tfprotov5.ProviderServer, schemaResponses map[int]*tfprotov5.GetProviderSchemaResponse) (map[int]*tfprotov5.GetProviderSchemaResponse, error) {
+	// Schemas of the servers retrieved so far, if enabled via
+	// WithConflictSchemaDump()
+	var conflictSchemaDumpServers []*conflictSchemaDumpServer
+
+	// addConflict returns the conflict as an error under
+	// ConflictResolutionError, otherwise the conflict is collected.
+	addConflict := func(conflict *ConflictError) error {
+		if s.options.conflictResolution == ConflictResolutionError {
+			if s.options.conflictSchemaDump != "" {
+				if err := writeConflictSchemaDump(s.options.conflictSchemaDump, conflictSchemaDumpServers); err != nil {
+					logging.MuxWarn(ctx, "unable to write conflict schema dump", map[string]interface{}{logging.KeyError: err.Error()})
+				}
+			}
+
+			return conflict
+		}
+
+		s.conflicts = append(s.conflicts, conflict)
+
+		return nil
+	}
+
+	// PlanDestroy is only supported if all servers with resources support it
+	var resourceServers int
+	planDestroy := true
+
+	// Schemas declared by servers implementing FeatureProvider, keyed by
+	// routing index, if WithFeatureServerOverlapWarnings() is enabled
+	featureServerSchemas := make(map[int]*tfprotov5.GetProviderSchemaResponse)
+
+	for serverIndex, server := range serverInstances {
 		ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
-		logging.MuxTrace(ctx, "calling downstream server")
 
-		resp, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+		var err error
+		resp, ok := schemaResponses[serverIndex]
+
+		if !ok {
+			resp, err = getServerSchema(ctx, server)
+		}
+
+		if err == nil && s.options.warningsAsErrors {
+			err = schemaDiagnosticsError(server, diagnosticsWithWarningsAsErrors(resp.Diagnostics))
+		}
+
+		if err == nil && s.options.strictResponseFields {
+			if fieldsErr := checkResponseFields(knownResponseFields, "GetProviderSchema", resp); fieldsErr != nil {
+				err = fmt.Errorf("server %d (%T): %w", serverIndex, server, fieldsErr)
+			}
+		}
 
 		if err != nil {
-			return result, fmt.Errorf("error retrieving schema for %T: %w", server, err)
+			if !s.options.bestEffortSchema {
+				return nil, err
+			}
+
+			logging.MuxWarn(ctx, "excluding server which failed to return its schema", map[string]interface{}{
+				logging.KeyError:            err.Error(),
+				logging.KeyTfMuxServerIndex: serverIndex,
+			})
+
+			s.schemaDiagnostics = append(s.schemaDiagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityWarning,
+				Summary:  "Provider Server Excluded",
+				Detail: fmt.Sprintf("Server %d (%T) failed to return its schema and was excluded from the provider. "+
+					"Resources and data sources implemented by the server are unavailable.\n\n%s", serverIndex, server, err),
+			})
+
+			continue
+		}
+
+		namespace := s.options.namespaces[serverIndex]
+
+		if namespace != "" {
+			resp = namespacedSchemaResponse(resp, namespace)
+		}
+
+		if s.options.startupSchemaDump {
+			logSchemaContributions(ctx, serverIndex, resp)
 		}
 
-		for _, diag := range resp.Diagnostics {
-			if diag == nil {
-				continue
+		if s.options.conflictSchemaDump != "" {
+			dumpServer, err := newConflictSchemaDumpServer(serverIndex, server, resp)
+
+			if err != nil {
+				logging.MuxWarn(ctx, "unable to encode server schemas for conflict schema dump", map[string]interface{}{
+					logging.KeyError:            err.Error(),
+					logging.KeyTfMuxServerIndex: serverIndex,
+				})
 			}
-			if diag.Severity != tfprotov5.DiagnosticSeverityError {
-				continue
+
+			if dumpServer != nil {
+				conflictSchemaDumpServers = append(conflictSchemaDumpServers, dumpServer)
 			}
-			return result, fmt.Errorf("error retrieving schema for %T:\n\n\tAttribute: %s\n\tSummary: %s\n\tDetail: %s", server, diag.Attribute, diag.Summary, diag.Detail)
 		}
 
-		if resp.Provider != nil {
-			if result.providerSchema != nil && !schemaEquals(resp.Provider, result.providerSchema) {
-				return result, fmt.Errorf("got a different provider schema across servers. Provider schemas must be identical across providers. Diff: %s", schemaDiff(resp.Provider, result.providerSchema))
+		if len(resp.ResourceSchemas) == 0 && len(resp.DataSourceSchemas) == 0 && resp.Provider == nil {
+			if s.options.emptyServerError {
+				return nil, fmt.Errorf("server %d (%T) declares no resources, data sources, or provider schema, which is usually caused by passing the wrong server function", serverIndex, server)
 			}
 
-			result.providerSchema = resp.Provider
+			logging.MuxWarn(ctx, "server declares no resources, data sources, or provider schema, which is usually caused by passing the wrong server function", map[string]interface{}{logging.KeyTfMuxServerIndex: serverIndex})
+		}
+
+		serverProviderSchema := resp.Provider
+		serverProviderMetaSchema := resp.ProviderMeta
+
+		// Servers excluded via WithBestEffortSchema are not routed, so
+		// routing uses the index of the server within s.servers.
+		routingIndex := len(s.servers)
+
+		if resp.Provider != nil {
+			switch {
+			case s.providerSchema == nil:
+				s.providerSchema = resp.Provider
+			case s.options.providerSchemaMerge && !s.options.requireSharedProviderSchema:
+				merged, err := mergeProviderSchemas("provider schema", s.providerSchema, resp.Provider)
+
+				if err != nil {
+					conflict := &ConflictError{
+						Kind:        ConflictKindProviderSchema,
+						ServerIndex: serverIndex,
+						Err:         fmt.Errorf("unable to merge provider schema from %T: %w", server, err),
+					}
+
+					if err := addConflict(conflict); err != nil {
+						return nil, err
+					}
+
+					// The server provider configuration is not handled, as
+					// its provider schema is not part of the merged schema.
+					serverProviderSchema = nil
+
+					break
+				}
+
+				s.providerSchema = merged
+			case !schemaEquals(resp.Provider, s.providerSchema):
+				conflict := &ConflictError{
+					Kind:        ConflictKindProviderSchema,
+					ServerIndex: serverIndex,
+					Err:         fmt.Errorf("got a different provider schema across servers. Provider schemas must be identical across providers. Diff: %s", schemaDiff(resp.Provider, s.providerSchema)),
+				}
+
+				if err := addConflict(conflict); err != nil {
+					return nil, err
+				}
+
+				if s.options.conflictResolution == ConflictResolutionLastServer {
+					s.providerSchema = resp.Provider
+				}
+			}
 		}
 
 		if resp.ProviderMeta != nil {
-			if result.providerMetaSchema != nil && !schemaEquals(resp.ProviderMeta, result.providerMetaSchema) {
-				return result, fmt.Errorf("got a different provider meta schema across servers. Provider metadata schemas must be identical across providers. Diff: %s", schemaDiff(resp.ProviderMeta, result.providerMetaSchema))
+			switch {
+			case s.providerMetaSchema == nil:
+				s.providerMetaSchema = resp.ProviderMeta
+			case s.options.providerMetaSchemaMerge:
+				merged, err := mergeProviderSchemas("provider meta schema", s.providerMetaSchema, resp.ProviderMeta)
+
+				if err != nil {
+					conflict := &ConflictError{
+						Kind:        ConflictKindProviderMetaSchema,
+						ServerIndex: serverIndex,
+						Err:         fmt.Errorf("unable to merge provider meta schema from %T: %w", server, err),
+					}
+
+					if err := addConflict(conflict); err != nil {
+						return nil, err
+					}
+
+					// The server provider meta configuration is not
+					// handled, as its provider meta schema is not part of
+					// the merged schema.
+					serverProviderMetaSchema = nil
+
+					break
+				}
+
+				s.providerMetaSchema = merged
+			case !schemaEquals(resp.ProviderMeta, s.providerMetaSchema):
+				conflict := &ConflictError{
+					Kind:        ConflictKindProviderMetaSchema,
+					ServerIndex: serverIndex,
+					Err:         fmt.Errorf("got a different provider meta schema across servers. Provider metadata schemas must be identical across providers. Diff: %s", schemaDiff(resp.ProviderMeta, s.providerMetaSchema)),
+				}
+
+				if err := addConflict(conflict); err != nil {
+					return nil, err
+				}
+
+				if s.options.conflictResolution == ConflictResolutionLastServer {
+					s.providerMetaSchema = resp.ProviderMeta
+				}
 			}
+		}
+
+		for _, resourceType := range sortedSchemaTypeNames(resp.ResourceSchemas) {
+			if _, ok := s.resources[resourceType]; ok {
+				conflict := &ConflictError{
+					Kind:        ConflictKindResource,
+					TypeName:    resourceType,
+					ServerIndex: serverIndex,
+					Err:         fmt.Errorf("resource %q is implemented by multiple servers; only one implementation allowed", resourceType),
+				}
+
+				if err := addConflict(conflict); err != nil {
+					return nil, err
+				}
 
-			result.providerMetaSchema = resp.ProviderMeta
+				if err := s.checkResourceSchemaVersion(resourceType, serverIndex, server, resp.ResourceSchemas[resourceType]); err != nil {
+					return nil, err
+				}
+
+				if s.options.conflictResolution != ConflictResolutionLastServer {
+					continue
+				}
+			}
+
+			s.resources[resourceType] = server
+			s.resourceServerIndex[resourceType] = routingIndex
+			s.resourceSchemas[resourceType] = resp.ResourceSchemas[resourceType]
 		}
 
-		for resourceType, schema := range resp.ResourceSchemas {
-			if _, ok := result.resources[resourceType]; ok {
-				return result, fmt.Errorf("resource %q is implemented by multiple servers; only one implementation allowed", resourceType)
+		for _, dataSourceType := range sortedSchemaTypeNames(resp.DataSourceSchemas) {
+			if _, ok := s.dataSources[dataSourceType]; ok {
+				conflict := &ConflictError{
+					Kind:        ConflictKindDataSource,
+					TypeName:    dataSourceType,
+					ServerIndex: serverIndex,
+					Err:         fmt.Errorf("data source %q is implemented by multiple servers; only one implementation allowed", dataSourceType),
+				}
+
+				if err := addConflict(conflict); err != nil {
+					return nil, err
+				}
+
+				if s.options.conflictResolution != ConflictResolutionLastServer {
+					continue
+				}
 			}
 
-			result.resources[resourceType] = server
-			result.resourceSchemas[resourceType] = schema
+			s.dataSources[dataSourceType] = server
+			s.dataSourceServerIndex[dataSourceType] = routingIndex
+			s.dataSourceSchemas[dataSourceType] = resp.DataSourceSchemas[dataSourceType]
+		}
+
+		if _, ok := server.(FeatureProvider); ok && s.options.featureServerOverlapWarnings {
+			featureServerSchemas[routingIndex] = resp
 		}
 
-		for dataSourceType, schema := range resp.DataSourceSchemas {
-			if _, ok := result.dataSources[dataSourceType]; ok {
-				return result, fmt.Errorf("data source %q is implemented by multiple servers; only one implementation allowed", dataSourceType)
+		if len(resp.ResourceSchemas) > 0 {
+			resourceServers++
+
+			if resp.ServerCapabilities == nil || !resp.ServerCapabilities.PlanDestroy {
+				planDestroy = false
 			}
+		}
 
-			result.dataSources[dataSourceType] = server
-			result.dataSourceSchemas[dataSourceType] = schema
+		s.servers = append(s.servers, server)
+		s.serverFuncs = append(s.serverFuncs, servers[serverIndex])
+		s.serverProviderSchemas = append(s.serverProviderSchemas, serverProviderSchema)
+		s.serverProviderMetaSchemas = append(s.serverProviderMetaSchemas, serverProviderMetaSchema)
+		s.serverNamespaces = append(s.serverNamespaces, namespace)
+	}
+
+	if resourceServers > 0 && planDestroy {
+		s.serverCapabilities = &tfprotov5.ServerCapabilities{
+			PlanDestroy: true,
 		}
+	}
 
-		result.servers = append(result.servers, server)
+	return featureServerSchemas, nil
+}
+
+// finalize validates the assembled muxed server schemas and routing, whether
+// retrieved from the servers or restored from the schema cache file, and
+// derives the state and warning Diagnostics which depend on them. The server
+// count is the number of servers given during creation. The schemas of
+// servers implementing FeatureProvider are keyed by routing index.
+func (s *muxServer) finalize(ctx context.Context, serverCount int, featureServerSchemas map[int]*tfprotov5.GetProviderSchemaResponse) error {
+	if s.options.requireProviderSchema && s.providerSchema == nil {
+		return &NoProviderSchemaError{
+			ServerCount: serverCount,
+		}
 	}
 
-	return result, nil
+	if s.options.requireSharedProviderSchema {
+		if err := s.checkSharedProviderSchema(); err != nil {
+			return err
+		}
+	}
+
+	if s.options.configureOrder != nil {
+		if err := s.checkConfigureOrder(); err != nil {
+			return err
+		}
+	}
+
+	if s.options.sharedConfigure {
+		if err := s.checkSharedConfigure(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.validateInvariants(); err != nil {
+		return err
+	}
+
+	if s.options.maxSchemaSize > 0 {
+		if err := s.checkSchemaSize(); err != nil {
+			return err
+		}
+	}
+
+	if s.options.duplicateSchemaNameValidation {
+		if err := s.checkDuplicateSchemaNames(); err != nil {
+			return err
+		}
+	}
+
+	if s.options.schemaContract != "" {
+		if err := s.checkSchemaContract(); err != nil {
+			return err
+		}
+	}
+
+	if len(s.options.deprecationHints) > 0 {
+		if err := s.checkDeprecationHints(); err != nil {
+			return err
+		}
+	}
+
+	if s.options.maxAttributeCount > 0 {
+		if err := s.checkAttributeCount(); err != nil {
+			return err
+		}
+	}
+
+	if s.options.timeoutHints {
+		s.typeTimeouts = s.readTimeoutHints(ctx)
+	}
+
+	if s.options.caseCollisionWarnings {
+		s.schemaDiagnostics = append(s.schemaDiagnostics, s.caseCollisionDiagnostics(ctx)...)
+	}
+
+	if s.options.featureServerOverlapWarnings {
+		s.schemaDiagnostics = append(s.schemaDiagnostics, s.featureServerOverlapDiagnostics(ctx, featureServerSchemas)...)
+	}
+
+	return nil
+}
+
+// getServerSchema returns the GetProviderSchema response of the server. An
+// error is returned if the server returns an error or error diagnostics.
+func getServerSchema(ctx context.Context, server tfprotov5.ProviderServer) (*tfprotov5.GetProviderSchemaResponse, error) {
+	logging.MuxTrace(ctx, "calling downstream server")
+
+	resp, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving schema for %T: %w", server, err)
+	}
+
+	if err := schemaDiagnosticsError(server, resp.Diagnostics); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// schemaDiagnosticsError returns an error for the first Diagnostic with
+// severity error returned by the GetProviderSchema method of the server.
+func schemaDiagnosticsError(server tfprotov5.ProviderServer, diags []*tfprotov5.Diagnostic) error {
+	for _, diag := range diags {
+		if diag == nil {
+			continue
+		}
+		if diag.Severity != tfprotov5.DiagnosticSeverityError {
+			continue
+		}
+		return fmt.Errorf("error retrieving schema for %T:\n\n\tAttribute: %s\n\tSummary: %s\n\tDetail: %s", server, diag.Attribute, diag.Summary, diag.Detail)
+	}
+
+	return nil
 }
@@ -1,10 +1,14 @@
 package tf5muxserver_test
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklogtest"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 )
@@ -64,3 +68,127 @@ func TestMuxServerApplyResourceChange(t *testing.T) {
 		t.Errorf("expected test_resource_server2 ApplyResourceChange to be called on server2")
 	}
 }
+
+func TestMuxServerApplyResourceChangeDestroyLogging(t *testing.T) {
+	t.Parallel()
+
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name:     "id",
+					Type:     tftypes.String,
+					Computed: true,
+				},
+			},
+		},
+	}
+	schemaType := schema.ValueType()
+
+	nullState, err := tfprotov5.NewDynamicValue(schemaType, tftypes.NewValue(schemaType, nil))
+
+	if err != nil {
+		t.Fatalf("unable to create null state: %s", err)
+	}
+
+	knownState, err := tfprotov5.NewDynamicValue(schemaType, tftypes.NewValue(schemaType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "test-id"),
+	}))
+
+	if err != nil {
+		t.Fatalf("unable to create known state: %s", err)
+	}
+
+	destroyEntry := map[string]interface{}{
+		"@level":           "info",
+		"@message":         "applying resource destroy",
+		"@module":          "sdk.mux",
+		"tf_mux_provider":  "*tf5testserver.TestServer",
+		"tf_resource_type": "test_resource",
+		"tf_rpc":           "ApplyResourceChange",
+	}
+
+	testCases := map[string]struct {
+		destroyLogging  bool
+		plannedState    *tfprotov5.DynamicValue
+		expectedEntries []map[string]interface{}
+	}{
+		"disabled-null-planned-state": {
+			plannedState:    &nullState,
+			expectedEntries: []map[string]interface{}{},
+		},
+		"enabled-known-planned-state": {
+			destroyLogging:  true,
+			plannedState:    &knownState,
+			expectedEntries: []map[string]interface{}{},
+		},
+		"enabled-null-planned-state": {
+			destroyLogging:  true,
+			plannedState:    &nullState,
+			expectedEntries: []map[string]interface{}{destroyEntry},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			ctx := tfsdklogtest.RootLogger(context.Background(), &output)
+
+			server := &tf5testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource": schema,
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{tf5muxserver.WithDestroyLogging(testCase.destroyLogging)}, server.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+				PlannedState: testCase.plannedState,
+				PriorState:   &knownState,
+				TypeName:     "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !server.ApplyResourceChangeCalled["test_resource"] {
+				t.Errorf("expected test_resource ApplyResourceChange to be called")
+			}
+
+			entries, err := tfsdklogtest.MultilineJSONDecode(&output)
+
+			if err != nil {
+				t.Fatalf("unable to read log entries: %s", err)
+			}
+
+			gotEntries := []map[string]interface{}{}
+
+			for _, entry := range entries {
+				if entry["@message"] != "applying resource destroy" {
+					continue
+				}
+
+				delete(entry, "@caller")
+				delete(entry, "@timestamp")
+				delete(entry, "tf_mux_request_id")
+				delete(entry, "tf_mux_version")
+
+				gotEntries = append(gotEntries, entry)
+			}
+
+			if diff := cmp.Diff(gotEntries, testCase.expectedEntries); diff != "" {
+				t.Errorf("unexpected log entries: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// closerServer is a server which implements io.Closer.
+type closerServer struct {
+	tfprotov5.ProviderServer
+
+	closeCalled bool
+	closeError  error
+}
+
+func (s *closerServer) Close() error {
+	s.closeCalled = true
+
+	return s.closeError
+}
+
+func TestMuxServerClose(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		closerErrors  []error
+		expectedError string
+	}{
+		"no-errors": {
+			closerErrors: []error{nil, nil},
+		},
+		"one-error": {
+			closerErrors:  []error{nil, errors.New("error in closer2")},
+			expectedError: "error closing *tf5muxserver_test.closerServer: error in closer2",
+		},
+		"multiple-errors": {
+			closerErrors:  []error{errors.New("error in closer1"), errors.New("error in closer2")},
+			expectedError: "error closing *tf5muxserver_test.closerServer: error in closer1\nerror closing *tf5muxserver_test.closerServer: error in closer2",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var closers []*closerServer
+
+			// Servers which do not implement io.Closer are skipped.
+			servers := []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{}).ProviderServer,
+			}
+
+			for _, closerError := range testCase.closerErrors {
+				closer := &closerServer{
+					ProviderServer: &tf5testserver.TestServer{},
+					closeError:     closerError,
+				}
+				closers = append(closers, closer)
+				servers = append(servers, func() tfprotov5.ProviderServer { return closer })
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServer(context.Background(), servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			err = muxServer.Close()
+
+			if testCase.expectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if testCase.expectedError != "" {
+				if err == nil {
+					t.Fatalf("expected error: %s", testCase.expectedError)
+				}
+
+				if err.Error() != testCase.expectedError {
+					t.Errorf("expected error %q, got: %q", testCase.expectedError, err)
+				}
+			}
+
+			for num, closer := range closers {
+				if !closer.closeCalled {
+					t.Errorf("Close not called on closer%d", num+1)
+				}
+			}
+		})
+	}
+}
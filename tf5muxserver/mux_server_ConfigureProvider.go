@@ -5,29 +5,73 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
 )
 
 // ConfigureProvider calls each provider's ConfigureProvider method, one at a
-// time, passing `req`. Any Diagnostic with severity error will abort the
-// process and return immediately; non-Error severity Diagnostics will be
-// combined and returned.
+// time in the order configured by the WithConfigureOrder option, passing
+// `req`. Any Diagnostic with severity error will abort the process and return
+// immediately; non-Error severity Diagnostics will be combined and returned.
+//
+// If the WithDiagnosticProvenance option is enabled, the Go type of the
+// provider is appended to the Detail of each Diagnostic. If the
+// WithDeprecationsWarning option is enabled, a warning Diagnostic listing the
+// deprecated schema elements of all providers is added. If the
+// WithProviderSchemaMerge option combines provider schemas, each provider
+// receives only the configuration declared in its own provider schema. If the
+// WithWarningsAsErrors option is enabled, warning Diagnostics are promoted to
+// error severity, which aborts the process. If the WithSharedConfigure option
+// is configured, the result of the primary provider is shared with the
+// providers configured after it via the context.
 func (s muxServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
 	rpc := "ConfigureProvider"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
+	ctx = logging.RequestIdContext(ctx)
 	var diags []*tfprotov5.Diagnostic
 
-	for _, server := range s.servers {
+	servers := s.currentServers()
+
+	for _, idx := range s.configureOrder(len(servers)) {
+		server := servers[idx]
 		ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
+
+		serverReq := req
+
+		if s.options.providerSchemaMerge && !s.options.requireSharedProviderSchema && s.providerSchema != nil {
+			config, err := projectConfig(req.Config, s.providerSchema, s.serverProviderSchemas[idx])
+
+			if err != nil {
+				return nil, fmt.Errorf("error projecting provider config for %T: %w", server, err)
+			}
+
+			serverReq = &tfprotov5.ConfigureProviderRequest{
+				Config:           config,
+				TerraformVersion: req.TerraformVersion,
+			}
+		}
+
 		logging.MuxTrace(ctx, "calling downstream server")
 
-		resp, err := server.ConfigureProvider(ctx, req)
+		resp, err := callServer(ctx, s, rpc, serverReq, server.ConfigureProvider)
 
 		if err != nil {
 			return resp, fmt.Errorf("error configuring %T: %w", server, err)
 		}
 
+		if s.options.diagnosticProvenance {
+			resp = &tfprotov5.ConfigureProviderResponse{
+				Diagnostics: diagnosticsWithProvenance(resp.Diagnostics, server),
+			}
+		}
+
+		if s.options.warningsAsErrors {
+			resp = &tfprotov5.ConfigureProviderResponse{
+				Diagnostics: diagnosticsWithWarningsAsErrors(resp.Diagnostics),
+			}
+		}
+
 		for _, diag := range resp.Diagnostics {
 			if diag == nil {
 				continue
@@ -43,7 +87,81 @@ func (s muxServer) ConfigureProvider(ctx context.Context, req *tfprotov5.Configu
 
 			return resp, err
 		}
+
+		ctx = s.sharedConfigureContext(ctx, idx, server)
+	}
+
+	if s.options.deprecationsWarning {
+		if diag := s.deprecationsDiagnostic(); diag != nil {
+			diags = append(diags, diag)
+		}
 	}
 
 	return &tfprotov5.ConfigureProviderResponse{Diagnostics: diags}, nil
 }
+
+// projectConfig returns the provider or provider meta configuration, encoded
+// with the type of the muxed schema, re-encoded with the type of the given
+// server schema, keeping only the attributes and nested blocks the server
+// declared. A nil server schema results in an empty object. Null and unknown
+// configurations remain null and unknown.
+func projectConfig(config *tfprotov5.DynamicValue, schema *tfprotov5.Schema, serverSchema *tfprotov5.Schema) (*tfprotov5.DynamicValue, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	serverType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{}}
+
+	if serverSchema != nil {
+		objectType, ok := serverSchema.ValueType().(tftypes.Object)
+
+		if !ok {
+			return nil, fmt.Errorf("unexpected schema type: %s", serverSchema.ValueType())
+		}
+
+		serverType = objectType
+	}
+
+	value, err := config.Unmarshal(schema.ValueType())
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal config: %w", err)
+	}
+
+	var serverValue tftypes.Value
+
+	switch {
+	case value.IsNull():
+		serverValue = tftypes.NewValue(serverType, nil)
+	case !value.IsKnown():
+		serverValue = tftypes.NewValue(serverType, tftypes.UnknownValue)
+	default:
+		var attributes map[string]tftypes.Value
+
+		if err := value.As(&attributes); err != nil {
+			return nil, fmt.Errorf("unable to convert config: %w", err)
+		}
+
+		serverAttributes := make(map[string]tftypes.Value, len(serverType.AttributeTypes))
+
+		for name, attributeType := range serverType.AttributeTypes {
+			attribute, ok := attributes[name]
+
+			if !ok {
+				attribute = tftypes.NewValue(attributeType, nil)
+			}
+
+			serverAttributes[name] = attribute
+		}
+
+		serverValue = tftypes.NewValue(serverType, serverAttributes)
+	}
+
+	serverConfig, err := tfprotov5.NewDynamicValue(serverType, serverValue)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to create config: %w", err)
+	}
+
+	return &serverConfig, nil
+}
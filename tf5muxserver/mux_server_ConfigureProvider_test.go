@@ -4,9 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxservertest"
 )
 
 func TestMuxServerConfigureProvider(t *testing.T) {
@@ -38,3 +41,550 @@ func TestMuxServerConfigureProvider(t *testing.T) {
 		}
 	}
 }
+
+func TestMuxServerConfigureProviderDiagnosticProvenance(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		diagnosticProvenance bool
+		expectedDiagnostics  []*tfprotov5.Diagnostic
+	}{
+		"disabled": {
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "warning summary",
+					Detail:   "warning detail",
+				},
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "other warning summary",
+				},
+			},
+		},
+		"enabled": {
+			diagnosticProvenance: true,
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "warning summary",
+					Detail:   "warning detail (via *tf5testserver.TestServer)",
+				},
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "other warning summary",
+					Detail:   "(via *tf5testserver.TestServer)",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ConfigureProviderResponse: &tfprotov5.ConfigureProviderResponse{
+						Diagnostics: []*tfprotov5.Diagnostic{
+							{
+								Severity: tfprotov5.DiagnosticSeverityWarning,
+								Summary:  "warning summary",
+								Detail:   "warning detail",
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ConfigureProviderResponse: &tfprotov5.ConfigureProviderResponse{
+						Diagnostics: []*tfprotov5.Diagnostic{
+							{
+								Severity: tfprotov5.DiagnosticSeverityWarning,
+								Summary:  "other warning summary",
+							},
+						},
+					},
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithDiagnosticProvenance(testCase.diagnosticProvenance)}, servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			got, err := muxServer.ProviderServer().ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("error calling ConfigureProvider: %s", err)
+			}
+
+			if diff := cmp.Diff(got.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMuxServerConfigureProviderDeprecationsWarning(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		deprecationsWarning bool
+		servers             []func() tfprotov5.ProviderServer
+		expectedDiagnostics []*tfprotov5.Diagnostic
+	}{
+		"disabled": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Block: &tfprotov5.SchemaBlock{
+								Deprecated: true,
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+		"enabled": {
+			deprecationsWarning: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ProviderSchema: &tfprotov5.Schema{
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:     "current",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+								{
+									Name:       "legacy",
+									Type:       tftypes.String,
+									Optional:   true,
+									Deprecated: true,
+								},
+							},
+							BlockTypes: []*tfprotov5.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+									Block: &tfprotov5.SchemaBlock{
+										Attributes: []*tfprotov5.SchemaAttribute{
+											{
+												Name:       "enabled",
+												Type:       tftypes.Bool,
+												Optional:   true,
+												Deprecated: true,
+											},
+										},
+									},
+								},
+								{
+									TypeName: "old_feature",
+									Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+									Block: &tfprotov5.SchemaBlock{
+										Deprecated: true,
+									},
+								},
+							},
+						},
+					},
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_current": {
+							Block: &tfprotov5.SchemaBlock{},
+						},
+						"test_resource_legacy": {
+							Block: &tfprotov5.SchemaBlock{
+								Deprecated: true,
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source_legacy": {
+							Block: &tfprotov5.SchemaBlock{
+								Deprecated: true,
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "Deprecated Provider Features",
+					Detail: "The provider declares the following deprecated features, which may be removed in a future version. " +
+						"Configurations using them should be migrated.\n\n" +
+						"  - provider attribute \"feature.enabled\"\n" +
+						"  - provider attribute \"legacy\"\n" +
+						"  - provider block \"old_feature\"\n" +
+						"  - resource \"test_resource_legacy\"\n" +
+						"  - data source \"test_data_source_legacy\"",
+				},
+			},
+		},
+		"enabled-no-deprecations": {
+			deprecationsWarning: true,
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithDeprecationsWarning(testCase.deprecationsWarning)}, testCase.servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			got, err := muxServer.ProviderServer().ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("error calling ConfigureProvider: %s", err)
+			}
+
+			if diff := cmp.Diff(got.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMuxServerConfigureProviderConfigProjection(t *testing.T) {
+	t.Parallel()
+
+	providerType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"account_id": tftypes.String,
+			"region":     tftypes.String,
+		},
+	}
+	server1Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"account_id": tftypes.String,
+		},
+	}
+	server2Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"region": tftypes.String,
+		},
+	}
+	server3Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{},
+	}
+
+	testCases := map[string]struct {
+		config          tftypes.Value
+		expectedConfigs []tftypes.Value
+	}{
+		"known": {
+			config: tftypes.NewValue(providerType, map[string]tftypes.Value{
+				"account_id": tftypes.NewValue(tftypes.String, "test-account"),
+				"region":     tftypes.NewValue(tftypes.String, "test-region"),
+			}),
+			expectedConfigs: []tftypes.Value{
+				tftypes.NewValue(server1Type, map[string]tftypes.Value{
+					"account_id": tftypes.NewValue(tftypes.String, "test-account"),
+				}),
+				tftypes.NewValue(server2Type, map[string]tftypes.Value{
+					"region": tftypes.NewValue(tftypes.String, "test-region"),
+				}),
+				tftypes.NewValue(server3Type, map[string]tftypes.Value{}),
+			},
+		},
+		"null": {
+			config: tftypes.NewValue(providerType, nil),
+			expectedConfigs: []tftypes.Value{
+				tftypes.NewValue(server1Type, nil),
+				tftypes.NewValue(server2Type, nil),
+				tftypes.NewValue(server3Type, nil),
+			},
+		},
+		"unknown-attribute": {
+			config: tftypes.NewValue(providerType, map[string]tftypes.Value{
+				"account_id": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"region":     tftypes.NewValue(tftypes.String, nil),
+			}),
+			expectedConfigs: []tftypes.Value{
+				tftypes.NewValue(server1Type, map[string]tftypes.Value{
+					"account_id": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				}),
+				tftypes.NewValue(server2Type, map[string]tftypes.Value{
+					"region": tftypes.NewValue(tftypes.String, nil),
+				}),
+				tftypes.NewValue(server3Type, map[string]tftypes.Value{}),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recordingServers := []*tf5muxservertest.RecordingServer{
+				tf5muxservertest.NewRecordingServer((&tf5testserver.TestServer{
+					ProviderSchema: &tfprotov5.Schema{
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer()),
+				tf5muxservertest.NewRecordingServer((&tf5testserver.TestServer{
+					ProviderSchema: &tfprotov5.Schema{
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:     "region",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer()),
+				tf5muxservertest.NewRecordingServer((&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer()),
+			}
+
+			servers := make([]func() tfprotov5.ProviderServer, 0, len(recordingServers))
+
+			for _, recordingServer := range recordingServers {
+				servers = append(servers, recordingServer.ProviderServer)
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf5muxserver.ServerOption{
+					tf5muxserver.WithProviderSchemaMerge(true),
+				},
+				servers...,
+			)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			config, err := tfprotov5.NewDynamicValue(providerType, testCase.config)
+
+			if err != nil {
+				t.Fatalf("error creating config: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{
+				Config: &config,
+			})
+
+			if err != nil {
+				t.Fatalf("error calling ConfigureProvider: %s", err)
+			}
+
+			for idx, recordingServer := range recordingServers {
+				req := recordingServer.LastConfigureProviderRequest()
+
+				if req == nil || req.Config == nil {
+					t.Fatalf("expected server %d ConfigureProvider request config", idx)
+				}
+
+				expectedConfig := testCase.expectedConfigs[idx]
+				got, err := req.Config.Unmarshal(expectedConfig.Type())
+
+				if err != nil {
+					t.Fatalf("error unmarshaling server %d config: %s", idx, err)
+				}
+
+				if !got.Equal(expectedConfig) {
+					t.Errorf("expected server %d config %s, got %s", idx, expectedConfig, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMuxServerConfigureProviderWarningsAsErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		warningsAsErrors    bool
+		expectedDiagnostics []*tfprotov5.Diagnostic
+		expectedCalled      bool
+	}{
+		"disabled": {
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "warning summary",
+					Detail:   "warning detail",
+				},
+			},
+			expectedCalled: true,
+		},
+		"enabled": {
+			warningsAsErrors: true,
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "warning summary",
+					Detail:   "warning detail",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server1 := &tf5testserver.TestServer{
+				ConfigureProviderResponse: &tfprotov5.ConfigureProviderResponse{
+					Diagnostics: []*tfprotov5.Diagnostic{
+						{
+							Severity: tfprotov5.DiagnosticSeverityWarning,
+							Summary:  "warning summary",
+							Detail:   "warning detail",
+						},
+					},
+				},
+			}
+			server2 := &tf5testserver.TestServer{}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithWarningsAsErrors(testCase.warningsAsErrors)}, server1.ProviderServer, server2.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			got, err := muxServer.ProviderServer().ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("error calling ConfigureProvider: %s", err)
+			}
+
+			if diff := cmp.Diff(got.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+
+			if server2.ConfigureProviderCalled != testCase.expectedCalled {
+				t.Errorf("expected second server ConfigureProvider called to be %t, got %t", testCase.expectedCalled, server2.ConfigureProviderCalled)
+			}
+		})
+	}
+}
+
+func TestMuxServerConfigureProviderConfigureOrder(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options           []tf5muxserver.ServerOption
+		expectedError     bool
+		expectedSummaries []string
+	}{
+		"default": {
+			expectedSummaries: []string{"server0", "server1", "server2"},
+		},
+		"order": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithConfigureOrder([]int{2, 0, 1}),
+			},
+			expectedSummaries: []string{"server2", "server0", "server1"},
+		},
+		"order-duplicate": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithConfigureOrder([]int{2, 0, 0}),
+			},
+			expectedError: true,
+		},
+		"order-missing": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithConfigureOrder([]int{2, 0}),
+			},
+			expectedError: true,
+		},
+		"order-out-of-range": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithConfigureOrder([]int{3, 0, 1}),
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var servers []func() tfprotov5.ProviderServer
+
+			for _, summary := range []string{"server0", "server1", "server2"} {
+				servers = append(servers, (&tf5testserver.TestServer{
+					ConfigureProviderResponse: &tfprotov5.ConfigureProviderResponse{
+						Diagnostics: []*tfprotov5.Diagnostic{
+							{
+								Severity: tfprotov5.DiagnosticSeverityWarning,
+								Summary:  summary,
+							},
+						},
+					},
+				}).ProviderServer)
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, servers...)
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error setting up muxer: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error setting up muxer")
+			}
+
+			got, err := muxServer.ProviderServer().ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("error calling ConfigureProvider: %s", err)
+			}
+
+			var gotSummaries []string
+
+			for _, diag := range got.Diagnostics {
+				gotSummaries = append(gotSummaries, diag.Summary)
+			}
+
+			if diff := cmp.Diff(gotSummaries, testCase.expectedSummaries); diff != "" {
+				t.Errorf("unexpected configure order difference: %s", diff)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package tf5muxserver
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
@@ -11,18 +10,86 @@ import (
 // ImportResourceState calls the ImportResourceState method, passing `req`, on
 // the provider that returned the resource specified by req.TypeName in its
 // schema.
+//
+// If no provider returned the resource, the request is routed to the first
+// provider implementing FeatureProvider which advertises FeatureImport.
+//
+// If the WithUnimplementedRPCDiagnostics option is enabled and the server
+// does not implement the RPC, an error Diagnostic is returned instead of the
+// server error.
+//
+// An error Diagnostic is added for each response ImportedResources type name
+// which is not implemented by any server. A warning Diagnostic, or an error
+// Diagnostic if the WithCrossServerImportErrors option is enabled, is added
+// for each type name implemented by a server other than the server handling
+// the import.
+//
+// If the WithReadOnly option is enabled, an error Diagnostic is returned
+// without calling any server.
 func (s muxServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
 	rpc := "ImportResourceState"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	server, ok := s.resources[req.TypeName]
+	ctx = logging.RequestIdContext(ctx)
 
-	if !ok {
-		return nil, fmt.Errorf("%q isn't supported by any servers", req.TypeName)
+	if err := checkRequest(rpc, req, func(req *tfprotov5.ImportResourceStateRequest) string { return req.TypeName }); err != nil {
+		return nil, err
+	}
+
+	if s.options.readOnly {
+		logging.MuxDebug(ctx, "rejecting request in read-only mode")
+
+		return &tfprotov5.ImportResourceStateResponse{
+			Diagnostics: []*tfprotov5.Diagnostic{
+				readOnlyDiagnostic(rpc, req.TypeName),
+			},
+		}, nil
+	}
+
+	server, err := s.routeRequest(ctx, rpc, req.TypeName, req, s.importResourceServer)
+
+	if err != nil {
+		return nil, err
 	}
 
 	ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
+	ctx, cancel := s.rpcTimeoutContext(ctx, rpc, req.TypeName)
+	defer cancel()
+
 	logging.MuxTrace(ctx, "calling downstream server")
 
-	return server.ImportResourceState(ctx, req)
+	resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.resourceNamespace(req.TypeName), server.ImportResourceState))
+
+	if s.options.unimplementedRPCDiagnostics && isUnimplementedError(err) {
+		return &tfprotov5.ImportResourceStateResponse{
+			Diagnostics: []*tfprotov5.Diagnostic{
+				unimplementedRPCDiagnostic(rpc, req.TypeName, server, err),
+			},
+		}, nil
+	}
+
+	if err == nil && resp != nil {
+		if diags := s.importedResourcesDiagnostics(req.TypeName, server, resp.ImportedResources); len(diags) > 0 {
+			checked := *resp
+			checked.Diagnostics = append(append([]*tfprotov5.Diagnostic{}, resp.Diagnostics...), diags...)
+			resp = &checked
+		}
+	}
+
+	return resp, rpcTimeoutError(ctx, rpc, req.TypeName, err)
+}
+
+// importResourceServer returns the server implementing the resource type, or
+// otherwise the server advertising FeatureImport via the FeatureProvider
+// interface. The feature server is only consulted for type names which no
+// server declared as a resource or data source type, so it never shadows the
+// server owning the schema of a type.
+func (s muxServer) importResourceServer(ctx context.Context, typeName string, req interface{}) (tfprotov5.ProviderServer, bool, error) {
+	server, ok, err := s.resourceServer(ctx, typeName, req)
+
+	if _, isDataSource := s.dataSourceSchemas[typeName]; err == nil && !ok && !isDataSource {
+		server, ok = s.featureServer(ctx, FeatureImport)
+	}
+
+	return server, ok, err
 }
@@ -2,13 +2,47 @@ package tf5muxserver_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// partialServer is a server which does not implement the
+// ImportResourceState RPC.
+type partialServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (s partialServer) ImportResourceState(_ context.Context, _ *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportResourceState not implemented")
+}
+
+// multipleImportServer is a server which returns an imported resource for
+// each of its type names from the ImportResourceState RPC.
+type multipleImportServer struct {
+	*tf5testserver.TestServer
+
+	importedTypeNames []string
+}
+
+func (s multipleImportServer) ImportResourceState(_ context.Context, _ *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	resp := &tfprotov5.ImportResourceStateResponse{}
+
+	for _, typeName := range s.importedTypeNames {
+		resp.ImportedResources = append(resp.ImportedResources, &tfprotov5.ImportedResource{
+			TypeName: typeName,
+		})
+	}
+
+	return resp, nil
+}
+
 func TestMuxServerImportResourceState(t *testing.T) {
 	t.Parallel()
 
@@ -64,3 +98,181 @@ func TestMuxServerImportResourceState(t *testing.T) {
 		t.Errorf("expected test_resource_server2 ImportResourceState to be called on server2")
 	}
 }
+
+func TestMuxServerImportResourceStateUnimplementedRPCDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled          bool
+		expectedError    string
+		expectedResponse *tfprotov5.ImportResourceStateResponse
+	}{
+		"disabled": {
+			enabled:       false,
+			expectedError: "method ImportResourceState not implemented",
+		},
+		"enabled": {
+			enabled: true,
+			expectedResponse: &tfprotov5.ImportResourceStateResponse{
+				Diagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Unsupported Provider Operation",
+						Detail: "The ImportResourceState operation for \"test_resource\" is not supported by the provider, because the server tf5muxserver_test.partialServer handling it does not implement the operation. " +
+							"This is always an issue in the provider implementation and should be reported to the provider developers.\n\n" +
+							"Error: rpc error: code = Unimplemented desc = method ImportResourceState not implemented",
+					},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			servers := []func() tfprotov5.ProviderServer{
+				func() tfprotov5.ProviderServer {
+					return partialServer{
+						ProviderServer: &tf5testserver.TestServer{
+							ResourceSchemas: map[string]*tfprotov5.Schema{
+								"test_resource": {},
+							},
+						},
+					}
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{tf5muxserver.WithUnimplementedRPCDiagnostics(testCase.enabled)}, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				if testCase.expectedError == "" || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			if diff := cmp.Diff(resp, testCase.expectedResponse); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMuxServerImportResourceStateImportedResources(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options             []tf5muxserver.ServerOption
+		importedTypeNames   []string
+		expectedDiagnostics []*tfprotov5.Diagnostic
+	}{
+		"same-server": {
+			importedTypeNames: []string{"test_resource_server1", "test_resource_server1_other"},
+		},
+		"cross-server": {
+			importedTypeNames: []string{"test_resource_server1", "test_resource_server2"},
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "Imported Resource Type Implemented by Another Server",
+					Detail: "The import of \"test_resource_server1\" by tf5muxserver_test.multipleImportServer returned a resource of type \"test_resource_server2\", which is implemented by *tf5testserver.TestServer. " +
+						"Later operations on the imported resource are handled by that server, which may not support the imported state. " +
+						"This is always an issue in the provider implementation and should be reported to the provider developers.",
+				},
+			},
+		},
+		"cross-server-WithCrossServerImportErrors": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithCrossServerImportErrors(true),
+			},
+			importedTypeNames: []string{"test_resource_server1", "test_resource_server2"},
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Imported Resource Type Implemented by Another Server",
+					Detail: "The import of \"test_resource_server1\" by tf5muxserver_test.multipleImportServer returned a resource of type \"test_resource_server2\", which is implemented by *tf5testserver.TestServer. " +
+						"Later operations on the imported resource are handled by that server, which may not support the imported state. " +
+						"This is always an issue in the provider implementation and should be reported to the provider developers.",
+				},
+			},
+		},
+		"unsupported": {
+			importedTypeNames: []string{"test_resource_server1", "test_resource_unsupported"},
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Unsupported Imported Resource Type",
+					Detail: "The import of \"test_resource_server1\" by tf5muxserver_test.multipleImportServer returned a resource of type \"test_resource_unsupported\", which is not implemented by any server of the provider, so it cannot be managed. " +
+						"This is always an issue in the provider implementation and should be reported to the provider developers.",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			servers := []func() tfprotov5.ProviderServer{
+				func() tfprotov5.ProviderServer {
+					return multipleImportServer{
+						TestServer: &tf5testserver.TestServer{
+							ResourceSchemas: map[string]*tfprotov5.Schema{
+								"test_resource_server1":       {},
+								"test_resource_server1_other": {},
+							},
+						},
+						importedTypeNames: testCase.importedTypeNames,
+					}
+				},
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server2": {},
+					},
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, testCase.options, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+				TypeName: "test_resource_server1",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(resp.ImportedResources) != len(testCase.importedTypeNames) {
+				t.Errorf("expected %d imported resources, got %d", len(testCase.importedTypeNames), len(resp.ImportedResources))
+			}
+
+			if diff := cmp.Diff(resp.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
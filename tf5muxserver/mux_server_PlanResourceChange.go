@@ -2,7 +2,6 @@ package tf5muxserver
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
@@ -11,18 +10,71 @@ import (
 // PlanResourceChange calls the PlanResourceChange method, passing `req`, on
 // the provider that returned the resource specified by req.TypeName in its
 // schema.
+//
+// If the resource type is enabled via the WithPlanResourceChangeShortCircuit
+// option and req.PriorState equals req.ProposedNewState, the proposed new
+// state is returned as the planned state without calling the provider.
+//
+// If the resource type is enabled via the WithStateNormalization option and
+// the response PlannedState is semantically equal to req.ProposedNewState, the
+// proposed new state is returned as the planned state instead.
+//
+// If the resource type is configured via the WithDeprecationHint option, a
+// warning Diagnostic pointing to the replacement resource type is added to
+// the response.
 func (s muxServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
 	rpc := "PlanResourceChange"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	server, ok := s.resources[req.TypeName]
+	ctx = logging.RequestIdContext(ctx)
 
-	if !ok {
-		return nil, fmt.Errorf("%q isn't supported by any servers", req.TypeName)
+	if err := checkRequest(rpc, req, func(req *tfprotov5.PlanResourceChangeRequest) string { return req.TypeName }); err != nil {
+		return nil, err
+	}
+
+	server, err := s.routeRequest(ctx, rpc, req.TypeName, req, s.resourceServer)
+
+	if err != nil {
+		return nil, err
 	}
 
 	ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
+	ctx, cancel := s.rpcTimeoutContext(ctx, rpc, req.TypeName)
+	defer cancel()
+
+	if _, ok := s.options.planShortCircuitTypes[req.TypeName]; ok && req.PriorState != nil {
+		equal, err := dynamicValueEquals(s.resourceSchemas[req.TypeName].ValueType(), req.PriorState, req.ProposedNewState)
+
+		if err != nil {
+			logging.MuxDebug(ctx, "unable to compare prior state and proposed new state, calling downstream server", map[string]interface{}{logging.KeyError: err.Error()})
+		}
+
+		if equal {
+			logging.MuxTrace(ctx, "prior state equals proposed new state, skipping downstream server")
+
+			return &tfprotov5.PlanResourceChangeResponse{
+				PlannedState:   req.ProposedNewState,
+				PlannedPrivate: req.PriorPrivate,
+				Diagnostics:    s.deprecationHintDiagnostics(req.TypeName, nil),
+			}, nil
+		}
+	}
+
 	logging.MuxTrace(ctx, "calling downstream server")
 
-	return server.PlanResourceChange(ctx, req)
+	resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.resourceNamespace(req.TypeName), providerMetaCall(s.resourceProviderMetaProjection(req.TypeName), server.PlanResourceChange)))
+
+	if _, ok := s.options.stateNormalizationTypes[req.TypeName]; ok && err == nil && resp != nil {
+		normalized := *resp
+		normalized.PlannedState = s.normalizedState(ctx, req.TypeName, "response PlannedState", resp.PlannedState, req.ProposedNewState)
+		resp = &normalized
+	}
+
+	if _, ok := s.options.deprecationHints[req.TypeName]; ok && err == nil && resp != nil {
+		hinted := *resp
+		hinted.Diagnostics = s.deprecationHintDiagnostics(req.TypeName, resp.Diagnostics)
+		resp = &hinted
+	}
+
+	return resp, rpcTimeoutError(ctx, rpc, req.TypeName, err)
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 )
@@ -64,3 +66,110 @@ func TestMuxServerPlanResourceChange(t *testing.T) {
 		t.Errorf("expected test_resource_server2 PlanResourceChange to be called on server2")
 	}
 }
+
+func TestMuxServerPlanResourceChangeShortCircuit(t *testing.T) {
+	t.Parallel()
+
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name:     "name",
+					Type:     tftypes.String,
+					Required: true,
+				},
+			},
+		},
+	}
+	schemaType := schema.ValueType()
+
+	newState := func(name string) *tfprotov5.DynamicValue {
+		state, err := tfprotov5.NewDynamicValue(schemaType, tftypes.NewValue(schemaType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, name),
+		}))
+
+		if err != nil {
+			t.Fatalf("unable to create DynamicValue: %s", err)
+		}
+
+		return &state
+	}
+
+	testCases := map[string]struct {
+		shortCircuitTypes  []string
+		priorState         *tfprotov5.DynamicValue
+		proposedNewState   *tfprotov5.DynamicValue
+		expectServerCalled bool
+		expectedResp       *tfprotov5.PlanResourceChangeResponse
+	}{
+		"equal-states": {
+			shortCircuitTypes:  []string{"test_resource"},
+			priorState:         newState("test1"),
+			proposedNewState:   newState("test1"),
+			expectServerCalled: false,
+			expectedResp: &tfprotov5.PlanResourceChangeResponse{
+				PlannedState:   newState("test1"),
+				PlannedPrivate: []byte("test-private"),
+			},
+		},
+		"equal-states-type-not-enabled": {
+			shortCircuitTypes:  []string{"test_other_resource"},
+			priorState:         newState("test1"),
+			proposedNewState:   newState("test1"),
+			expectServerCalled: true,
+		},
+		"no-prior-state": {
+			shortCircuitTypes:  []string{"test_resource"},
+			proposedNewState:   newState("test1"),
+			expectServerCalled: true,
+		},
+		"unequal-states": {
+			shortCircuitTypes:  []string{"test_resource"},
+			priorState:         newState("test1"),
+			proposedNewState:   newState("test2"),
+			expectServerCalled: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			testServer := &tf5testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource": schema,
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{
+				tf5muxserver.WithPlanResourceChangeShortCircuit(testCase.shortCircuitTypes),
+			}, testServer.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+				TypeName:         "test_resource",
+				PriorState:       testCase.priorState,
+				ProposedNewState: testCase.proposedNewState,
+				PriorPrivate:     []byte("test-private"),
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if testServer.PlanResourceChangeCalled["test_resource"] != testCase.expectServerCalled {
+				t.Errorf("expected PlanResourceChange called %t, got %t", testCase.expectServerCalled, testServer.PlanResourceChangeCalled["test_resource"])
+			}
+
+			if diff := cmp.Diff(resp, testCase.expectedResp); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+		})
+	}
+}
@@ -343,7 +343,17 @@ func TestMuxServerPrepareProviderConfig(t *testing.T) {
 					ProviderSchema: &configSchema,
 				}).ProviderServer,
 			},
-			expectedError: fmt.Errorf("got different PrepareProviderConfig PreparedConfig response from multiple servers, not sure which to use"),
+			expectedResponse: &tfprotov5.PrepareProviderConfigResponse{
+				Diagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Conflicting Prepared Provider Configuration",
+						Detail: "Multiple servers returned different prepared provider configurations, so the muxed server cannot choose one. " +
+							"This is always an issue in the provider implementation and should be reported to the provider developers.\n\n" +
+							"The prepared provider configuration of *tf5testserver.TestServer differs from: *tf5testserver.TestServer",
+					},
+				},
+			},
 		},
 		"PreparedConfig-multiple-equal": {
 			servers: []func() tfprotov5.ProviderServer{
@@ -409,3 +419,559 @@ func TestMuxServerPrepareProviderConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestMuxServerPrepareProviderConfigProviderSchemaMerge(t *testing.T) {
+	t.Parallel()
+
+	server1Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"account_id": tftypes.String,
+		},
+	}
+	server2Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"region": tftypes.String,
+		},
+	}
+	mergedType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"account_id": tftypes.String,
+			"region":     tftypes.String,
+		},
+	}
+
+	server1Config, err := tfprotov5.NewDynamicValue(server1Type, tftypes.NewValue(server1Type, map[string]tftypes.Value{
+		"account_id": tftypes.NewValue(tftypes.String, "123456"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	server2Config, err := tfprotov5.NewDynamicValue(server2Type, tftypes.NewValue(server2Type, map[string]tftypes.Value{
+		"region": tftypes.NewValue(tftypes.String, "us-east-1"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	mergedConfig, err := tfprotov5.NewDynamicValue(mergedType, tftypes.NewValue(mergedType, map[string]tftypes.Value{
+		"account_id": tftypes.NewValue(tftypes.String, "123456"),
+		"region":     tftypes.NewValue(tftypes.String, "us-east-1"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	servers := []func() tfprotov5.ProviderServer{
+		(&tf5testserver.TestServer{
+			PrepareProviderConfigResponse: &tfprotov5.PrepareProviderConfigResponse{
+				PreparedConfig: &server1Config,
+			},
+			ProviderSchema: &tfprotov5.Schema{
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:     "account_id",
+							Type:     tftypes.String,
+							Required: true,
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+		(&tf5testserver.TestServer{}).ProviderServer,
+		(&tf5testserver.TestServer{
+			PrepareProviderConfigResponse: &tfprotov5.PrepareProviderConfigResponse{
+				Diagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityWarning,
+						Summary:  "test warning summary",
+						Detail:   "test warning details",
+					},
+				},
+				PreparedConfig: &server2Config,
+			},
+			ProviderSchema: &tfprotov5.Schema{
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:     "region",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithProviderSchemaMerge(true)}, servers...)
+
+	if err != nil {
+		t.Fatalf("error setting up muxer: %s", err)
+	}
+
+	got, err := muxServer.ProviderServer().PrepareProviderConfig(context.Background(), &tfprotov5.PrepareProviderConfigRequest{
+		Config: &mergedConfig,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedResponse := &tfprotov5.PrepareProviderConfigResponse{
+		Diagnostics: []*tfprotov5.Diagnostic{
+			{
+				Severity: tfprotov5.DiagnosticSeverityWarning,
+				Summary:  "test warning summary",
+				Detail:   "test warning details",
+			},
+		},
+		PreparedConfig: &mergedConfig,
+	}
+
+	if !cmp.Equal(got, expectedResponse) {
+		t.Errorf("unexpected response: %s", cmp.Diff(got, expectedResponse))
+	}
+
+	if !servers[0]().(*tf5testserver.TestServer).PrepareProviderConfigCalled {
+		t.Errorf("expected PrepareProviderConfig to be called on server1")
+	}
+
+	if servers[1]().(*tf5testserver.TestServer).PrepareProviderConfigCalled {
+		t.Errorf("unexpected PrepareProviderConfig called on server2")
+	}
+
+	if !servers[2]().(*tf5testserver.TestServer).PrepareProviderConfigCalled {
+		t.Errorf("expected PrepareProviderConfig to be called on server3")
+	}
+}
+
+// configDecodingServer is a server which decodes the provider configuration
+// with its own provider schema, as real servers do, and returns it as the
+// PreparedConfig.
+type configDecodingServer struct {
+	*tf5testserver.TestServer
+}
+
+func (s configDecodingServer) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	s.PrepareProviderConfigCalled = true
+
+	if _, err := req.Config.Unmarshal(s.ProviderSchema.ValueType()); err != nil {
+		return nil, fmt.Errorf("unable to decode config: %w", err)
+	}
+
+	return &tfprotov5.PrepareProviderConfigResponse{
+		PreparedConfig: req.Config,
+	}, nil
+}
+
+func TestMuxServerPrepareProviderConfigProviderSchemaMergeDecoding(t *testing.T) {
+	t.Parallel()
+
+	mergedType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"account_id": tftypes.String,
+			"region":     tftypes.String,
+		},
+	}
+
+	mergedConfig, err := tfprotov5.NewDynamicValue(mergedType, tftypes.NewValue(mergedType, map[string]tftypes.Value{
+		"account_id": tftypes.NewValue(tftypes.String, "123456"),
+		"region":     tftypes.NewValue(tftypes.String, "us-east-1"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	testServers := []*tf5testserver.TestServer{
+		{
+			ProviderSchema: &tfprotov5.Schema{
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:     "account_id",
+							Type:     tftypes.String,
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			ProviderSchema: &tfprotov5.Schema{
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:     "region",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var servers []func() tfprotov5.ProviderServer
+
+	for _, testServer := range testServers {
+		server := configDecodingServer{
+			TestServer: testServer,
+		}
+
+		servers = append(servers, func() tfprotov5.ProviderServer {
+			return server
+		})
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithProviderSchemaMerge(true)}, servers...)
+
+	if err != nil {
+		t.Fatalf("error setting up muxer: %s", err)
+	}
+
+	got, err := muxServer.ProviderServer().PrepareProviderConfig(context.Background(), &tfprotov5.PrepareProviderConfigRequest{
+		Config: &mergedConfig,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedResponse := &tfprotov5.PrepareProviderConfigResponse{
+		PreparedConfig: &mergedConfig,
+	}
+
+	if !cmp.Equal(got, expectedResponse) {
+		t.Errorf("unexpected response: %s", cmp.Diff(got, expectedResponse))
+	}
+
+	for num, testServer := range testServers {
+		if !testServer.PrepareProviderConfigCalled {
+			t.Errorf("expected PrepareProviderConfig to be called on server%d", num+1)
+		}
+	}
+}
+
+func TestMuxServerPrepareProviderConfigSchemaOwnership(t *testing.T) {
+	t.Parallel()
+
+	config, err := tfprotov5.NewDynamicValue(tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"hello": tftypes.String,
+		},
+	}, tftypes.NewValue(tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"hello": tftypes.String,
+		},
+	}, map[string]tftypes.Value{
+		"hello": tftypes.NewValue(tftypes.String, "world"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	configSchema := tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name: "hello",
+					Type: tftypes.String,
+				},
+			},
+		},
+	}
+
+	metaSchema := tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name: "module_id",
+					Type: tftypes.String,
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		options            []tf5muxserver.ServerOption
+		providerSchema     *tfprotov5.Schema
+		providerMetaSchema *tfprotov5.Schema
+		expectedError      error
+		expectedResponse   *tfprotov5.PrepareProviderConfigResponse
+	}{
+		"neither": {
+			expectedError: fmt.Errorf("unable to compare PrepareProviderConfig PreparedConfig responses: no server declared a provider schema or provider meta schema"),
+		},
+		"neither-provider-schema-merge": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithProviderSchemaMerge(true),
+			},
+			expectedError: fmt.Errorf("unable to compare PrepareProviderConfig PreparedConfig responses: no server declared a provider schema or provider meta schema"),
+		},
+		"provider": {
+			providerSchema: &configSchema,
+			expectedResponse: &tfprotov5.PrepareProviderConfigResponse{
+				PreparedConfig: &config,
+			},
+		},
+		"provider-and-provider-meta": {
+			providerSchema:     &configSchema,
+			providerMetaSchema: &metaSchema,
+			expectedResponse: &tfprotov5.PrepareProviderConfigResponse{
+				PreparedConfig: &config,
+			},
+		},
+		"provider-meta": {
+			providerMetaSchema: &metaSchema,
+			expectedError:      fmt.Errorf("unable to compare PrepareProviderConfig PreparedConfig responses: no server declared a provider schema, only a provider meta schema, which does not apply to provider configuration"),
+		},
+		"provider-meta-provider-schema-merge": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithProviderSchemaMerge(true),
+			},
+			providerMetaSchema: &metaSchema,
+			expectedError:      fmt.Errorf("unable to compare PrepareProviderConfig PreparedConfig responses: no server declared a provider schema, only a provider meta schema, which does not apply to provider configuration"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					PrepareProviderConfigResponse: &tfprotov5.PrepareProviderConfigResponse{
+						PreparedConfig: &config,
+					},
+					ProviderSchema: testCase.providerSchema,
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					PrepareProviderConfigResponse: &tfprotov5.PrepareProviderConfigResponse{
+						PreparedConfig: &config,
+					},
+					ProviderMetaSchema: testCase.providerMetaSchema,
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			got, err := muxServer.ProviderServer().PrepareProviderConfig(context.Background(), &tfprotov5.PrepareProviderConfigRequest{
+				Config: &config,
+			})
+
+			if err != nil {
+				if testCase.expectedError == nil {
+					t.Fatalf("wanted no error, got error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError.Error()) {
+					t.Fatalf("wanted error %q, got error: %s", testCase.expectedError.Error(), err.Error())
+				}
+			}
+
+			if err == nil && testCase.expectedError != nil {
+				t.Fatalf("got no error, wanted err: %s", testCase.expectedError)
+			}
+
+			if !cmp.Equal(got, testCase.expectedResponse) {
+				t.Errorf("unexpected response: %s", cmp.Diff(got, testCase.expectedResponse))
+			}
+		})
+	}
+}
+
+func TestMuxServerPrepareProviderConfigDiagnosticProvenance(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		diagnosticProvenance bool
+		expectedDiagnostics  []*tfprotov5.Diagnostic
+	}{
+		"disabled": {
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "warning summary",
+					Detail:   "warning detail",
+				},
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "error summary",
+					Detail:   "error detail",
+				},
+			},
+		},
+		"enabled": {
+			diagnosticProvenance: true,
+			expectedDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityWarning,
+					Summary:  "warning summary",
+					Detail:   "warning detail (via *tf5testserver.TestServer)",
+				},
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "error summary",
+					Detail:   "error detail (via *tf5testserver.TestServer)",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					PrepareProviderConfigResponse: &tfprotov5.PrepareProviderConfigResponse{
+						Diagnostics: []*tfprotov5.Diagnostic{
+							{
+								Severity: tfprotov5.DiagnosticSeverityWarning,
+								Summary:  "warning summary",
+								Detail:   "warning detail",
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					PrepareProviderConfigResponse: &tfprotov5.PrepareProviderConfigResponse{
+						Diagnostics: []*tfprotov5.Diagnostic{
+							{
+								Severity: tfprotov5.DiagnosticSeverityError,
+								Summary:  "error summary",
+								Detail:   "error detail",
+							},
+						},
+					},
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithDiagnosticProvenance(testCase.diagnosticProvenance)}, servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			got, err := muxServer.ProviderServer().PrepareProviderConfig(context.Background(), &tfprotov5.PrepareProviderConfigRequest{})
+
+			if err != nil {
+				t.Fatalf("error calling PrepareProviderConfig: %s", err)
+			}
+
+			if diff := cmp.Diff(got.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMuxServerPrepareProviderConfigServerResponseUnmodified(t *testing.T) {
+	t.Parallel()
+
+	configType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"hello": tftypes.String,
+		},
+	}
+
+	config1, err := tfprotov5.NewDynamicValue(configType, tftypes.NewValue(configType, map[string]tftypes.Value{
+		"hello": tftypes.NewValue(tftypes.String, "world"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	config2, err := tfprotov5.NewDynamicValue(configType, tftypes.NewValue(configType, map[string]tftypes.Value{
+		"hello": tftypes.NewValue(tftypes.String, "goodbye"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	configSchema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name: "hello",
+					Type: tftypes.String,
+				},
+			},
+		},
+	}
+
+	warning := &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "test warning summary",
+	}
+
+	// The slice has spare capacity, so appending to it in place would not
+	// change its length, only its backing array.
+	diagnostics := make([]*tfprotov5.Diagnostic, 1, 3)
+	diagnostics[0] = warning
+
+	serverResp := &tfprotov5.PrepareProviderConfigResponse{
+		Diagnostics:    diagnostics,
+		PreparedConfig: &config1,
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(
+		context.Background(),
+		(&tf5testserver.TestServer{
+			PrepareProviderConfigResponse: serverResp,
+			ProviderSchema:                configSchema,
+		}).ProviderServer,
+		(&tf5testserver.TestServer{
+			PrepareProviderConfigResponse: &tfprotov5.PrepareProviderConfigResponse{
+				Diagnostics:    []*tfprotov5.Diagnostic{warning},
+				PreparedConfig: &config2,
+			},
+			ProviderSchema: configSchema,
+		}).ProviderServer,
+	)
+
+	if err != nil {
+		t.Fatalf("error setting up muxer: %s", err)
+	}
+
+	got, err := muxServer.ProviderServer().PrepareProviderConfig(context.Background(), &tfprotov5.PrepareProviderConfigRequest{
+		Config: &config1,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got == serverResp {
+		t.Errorf("expected a new response, got the server response")
+	}
+
+	if len(got.Diagnostics) != 3 {
+		t.Errorf("expected 3 diagnostics, got %d", len(got.Diagnostics))
+	}
+
+	if serverResp.PreparedConfig != &config1 {
+		t.Errorf("expected server response PreparedConfig to be unmodified")
+	}
+
+	if diff := cmp.Diff(diagnostics[:cap(diagnostics)], []*tfprotov5.Diagnostic{warning, nil, nil}); diff != "" {
+		t.Errorf("expected server response Diagnostics to be unmodified: %s", diff)
+	}
+}
@@ -2,7 +2,6 @@ package tf5muxserver
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
@@ -11,18 +10,47 @@ import (
 // ReadDataSource calls the ReadDataSource method, passing `req`, on the
 // provider that returned the data source specified by req.TypeName in its
 // schema.
+//
+// If the WithDataSourceCache option is enabled for the data source type, an
+// unexpired cached response for the same request Config is returned instead.
 func (s muxServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
 	rpc := "ReadDataSource"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	server, ok := s.dataSources[req.TypeName]
+	ctx = logging.RequestIdContext(ctx)
 
-	if !ok {
-		return nil, fmt.Errorf("%q isn't supported by any servers", req.TypeName)
+	if err := checkRequest(rpc, req, func(req *tfprotov5.ReadDataSourceRequest) string { return req.TypeName }); err != nil {
+		return nil, err
+	}
+
+	server, err := s.routeRequest(ctx, rpc, req.TypeName, req, s.dataSourceServer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey, cached := s.dataSourceCache.key(req)
+
+	if cached {
+		if resp, ok := s.dataSourceCache.get(cacheKey); ok {
+			logging.MuxTrace(ctx, "using cached response")
+
+			return resp, nil
+		}
 	}
 
 	ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
+	ctx, cancel := s.rpcTimeoutContext(ctx, rpc, req.TypeName)
+	defer cancel()
+
 	logging.MuxTrace(ctx, "calling downstream server")
 
-	return server.ReadDataSource(ctx, req)
+	resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.dataSourceNamespace(req.TypeName), providerMetaCall(s.dataSourceProviderMetaProjection(req.TypeName), server.ReadDataSource)))
+	err = rpcTimeoutError(ctx, rpc, req.TypeName, err)
+
+	if cached && err == nil && resp != nil && !hasErrorDiagnostics(resp.Diagnostics) {
+		s.dataSourceCache.set(cacheKey, resp)
+	}
+
+	return resp, err
 }
@@ -2,7 +2,7 @@ package tf5muxserver
 
 import (
 	"context"
-	"fmt"
+	"errors"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
@@ -10,18 +10,60 @@ import (
 
 // ReadResource calls the ReadResource method, passing `req`, on the provider
 // that returned the resource specified by req.TypeName in its schema.
+//
+// If no provider returned the resource and the WithOrphanedResourceHandler
+// option is configured, the handler is called instead.
+//
+// If the resource type is enabled via the WithStateNormalization option and
+// the response NewState is semantically equal to req.CurrentState, the
+// current state is returned as the new state instead.
+//
+// If the resource type is configured via the WithDeprecationHint option, a
+// warning Diagnostic pointing to the replacement resource type is added to
+// the response.
 func (s muxServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
 	rpc := "ReadResource"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	server, ok := s.resources[req.TypeName]
+	ctx = logging.RequestIdContext(ctx)
 
-	if !ok {
-		return nil, fmt.Errorf("%q isn't supported by any servers", req.TypeName)
+	if err := checkRequest(rpc, req, func(req *tfprotov5.ReadResourceRequest) string { return req.TypeName }); err != nil {
+		return nil, err
+	}
+
+	server, err := s.routeRequest(ctx, rpc, req.TypeName, req, s.resourceServer)
+
+	var unsupportedErr *unsupportedTypeError
+
+	if errors.As(err, &unsupportedErr) && s.options.orphanedResourceHandler != nil {
+		logging.MuxTrace(ctx, "calling orphaned resource handler")
+
+		return s.options.orphanedResourceHandler(ctx, req)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
 	ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
+	ctx, cancel := s.rpcTimeoutContext(ctx, rpc, req.TypeName)
+	defer cancel()
+
 	logging.MuxTrace(ctx, "calling downstream server")
 
-	return server.ReadResource(ctx, req)
+	resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.resourceNamespace(req.TypeName), providerMetaCall(s.resourceProviderMetaProjection(req.TypeName), server.ReadResource)))
+
+	if _, ok := s.options.stateNormalizationTypes[req.TypeName]; ok && err == nil && resp != nil {
+		normalized := *resp
+		normalized.NewState = s.normalizedState(ctx, req.TypeName, "response NewState", resp.NewState, req.CurrentState)
+		resp = &normalized
+	}
+
+	if _, ok := s.options.deprecationHints[req.TypeName]; ok && err == nil && resp != nil {
+		hinted := *resp
+		hinted.Diagnostics = s.deprecationHintDiagnostics(req.TypeName, resp.Diagnostics)
+		resp = &hinted
+	}
+
+	return resp, rpcTimeoutError(ctx, rpc, req.TypeName, err)
 }
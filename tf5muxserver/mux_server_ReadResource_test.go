@@ -2,9 +2,12 @@ package tf5muxserver_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 )
@@ -64,3 +67,216 @@ func TestMuxServerReadResource(t *testing.T) {
 		t.Errorf("expected test_resource_server2 ReadResource to be called on server2")
 	}
 }
+
+// privateServer is a server which records the request Private of
+// ReadResource and responds with its own Private.
+type privateServer struct {
+	*tf5testserver.TestServer
+
+	requestPrivate  []byte
+	responsePrivate []byte
+}
+
+func (s *privateServer) ReadResource(_ context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	s.requestPrivate = req.Private
+
+	return &tfprotov5.ReadResourceResponse{
+		NewState: req.CurrentState,
+		Private:  s.responsePrivate,
+	}, nil
+}
+
+func TestMuxServerReadResourcePrivate(t *testing.T) {
+	t.Parallel()
+
+	resourceType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"id": tftypes.String,
+		},
+	}
+
+	currentState, err := tfprotov5.NewDynamicValue(resourceType, tftypes.NewValue(resourceType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "test-id"),
+	}))
+
+	if err != nil {
+		t.Fatalf("unexpected error creating current state: %s", err)
+	}
+
+	testCases := map[string]struct {
+		options         []tf5muxserver.ServerOption
+		requestPrivate  []byte
+		responsePrivate []byte
+	}{
+		"nil": {},
+		"private": {
+			requestPrivate:  []byte(`{"request":"private"}`),
+			responsePrivate: []byte(`{"response":"private"}`),
+		},
+		"private-WithStateNormalization": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithStateNormalization([]string{"test_resource_server2"}),
+			},
+			requestPrivate:  []byte(`{"request":"private"}`),
+			responsePrivate: []byte(`{"response":"private"}`),
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server2 := &privateServer{
+				TestServer: &tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server2": {
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{
+									{
+										Name:     "id",
+										Type:     tftypes.String,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+				responsePrivate: testCase.responsePrivate,
+			}
+
+			servers := []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server1": {},
+					},
+				}).ProviderServer,
+				func() tfprotov5.ProviderServer {
+					return server2
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+				TypeName:     "test_resource_server2",
+				CurrentState: &currentState,
+				Private:      testCase.requestPrivate,
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(server2.requestPrivate, testCase.requestPrivate); diff != "" {
+				t.Errorf("unexpected request Private difference: %s", diff)
+			}
+
+			if diff := cmp.Diff(resp.Private, testCase.responsePrivate); diff != "" {
+				t.Errorf("unexpected response Private difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMuxServerReadResourceOrphanedResourceHandler(t *testing.T) {
+	t.Parallel()
+
+	resourceType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"id": tftypes.String,
+		},
+	}
+
+	testCases := map[string]struct {
+		options          []tf5muxserver.ServerOption
+		typeName         string
+		expectedError    string
+		expectedNewState tftypes.Value
+		expectedCalled   bool
+	}{
+		"no-handler": {
+			typeName:      "test_orphaned",
+			expectedError: `"test_orphaned" isn't supported by any servers`,
+		},
+		"remove-orphaned-resource": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithOrphanedResourceHandler(tf5muxserver.RemoveOrphanedResource),
+			},
+			typeName:         "test_orphaned",
+			expectedNewState: tftypes.NewValue(resourceType, nil),
+		},
+		"supported-type": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithOrphanedResourceHandler(tf5muxserver.RemoveOrphanedResource),
+			},
+			typeName:       "test_resource",
+			expectedCalled: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			testServer := &tf5testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource": {},
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, testCase.options, testServer.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+				TypeName: testCase.typeName,
+			})
+
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			if testServer.ReadResourceCalled[testCase.typeName] != testCase.expectedCalled {
+				t.Errorf("expected ReadResource called %t, got %t", testCase.expectedCalled, !testCase.expectedCalled)
+			}
+
+			if testCase.expectedCalled {
+				return
+			}
+
+			newState, err := resp.NewState.Unmarshal(resourceType)
+
+			if err != nil {
+				t.Fatalf("unable to unmarshal NewState: %s", err)
+			}
+
+			if !newState.Equal(testCase.expectedNewState) {
+				t.Errorf("expected NewState %s, got: %s", testCase.expectedNewState, newState)
+			}
+		})
+	}
+}
@@ -10,19 +10,64 @@ import (
 
 // UpgradeResourceState calls the UpgradeResourceState method, passing `req`,
 // on the provider that returned the resource specified by req.TypeName in its
-// schema.
+// schema. The raw state and version are passed through unchanged.
+//
+// If the WithUpgradeResourceStateVersionCheck option is enabled, an error
+// diagnostic is prepended to the provider response diagnostics when
+// req.Version is greater than the version of the resource schema. If the
+// WithUpgradeResourceStateDiagnostics option is configured, its diagnostics
+// are prepended to the provider response diagnostics after any version check
+// diagnostic. The provider response is otherwise returned unchanged.
 func (s muxServer) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
 	rpc := "UpgradeResourceState"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	server, ok := s.resources[req.TypeName]
+	ctx = logging.RequestIdContext(ctx)
 
-	if !ok {
-		return nil, fmt.Errorf("%q isn't supported by any servers", req.TypeName)
+	if err := checkRequest(rpc, req, func(req *tfprotov5.UpgradeResourceStateRequest) string { return req.TypeName }); err != nil {
+		return nil, err
+	}
+
+	server, err := s.routeRequest(ctx, rpc, req.TypeName, req, s.resourceServer)
+
+	if err != nil {
+		return nil, err
 	}
 
 	ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
+	ctx, cancel := s.rpcTimeoutContext(ctx, rpc, req.TypeName)
+	defer cancel()
+
+	var diags []*tfprotov5.Diagnostic
+
+	if s.options.upgradeResourceStateVersionCheck {
+		var schemaVersion int64
+
+		if schema := s.resourceSchemas[req.TypeName]; schema != nil {
+			schemaVersion = schema.Version
+		}
+
+		if req.Version > schemaVersion {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Unsupported Resource State Version",
+				Detail: fmt.Sprintf("The %s resource state version %d is greater than the resource schema version %d. "+
+					"The state was likely written by a newer version of the provider, which must be used to manage this resource.", req.TypeName, req.Version, schemaVersion),
+			})
+		}
+	}
+
 	logging.MuxTrace(ctx, "calling downstream server")
 
-	return server.UpgradeResourceState(ctx, req)
+	resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.resourceNamespace(req.TypeName), server.UpgradeResourceState))
+
+	if err == nil && s.options.upgradeResourceStateDiagnostics != nil {
+		diags = append(diags, s.options.upgradeResourceStateDiagnostics(ctx, req)...)
+	}
+
+	if err == nil {
+		resp = upgradeResourceStateResponseWithDiagnostics(resp, diags)
+	}
+
+	return resp, rpcTimeoutError(ctx, rpc, req.TypeName, err)
 }
@@ -11,18 +11,71 @@ import (
 // ValidateResourceTypeConfig calls the ValidateResourceTypeConfig method,
 // passing `req`, on the provider that returned the resource specified by
 // req.TypeName in its schema.
+//
+// If the resource type is enabled via the WithValidationFanOut option, the
+// ValidateResourceTypeConfig method of each provider is called instead and
+// the Diagnostics are combined in provider order.
 func (s muxServer) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
 	rpc := "ValidateResourceTypeConfig"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	server, ok := s.resources[req.TypeName]
+	ctx = logging.RequestIdContext(ctx)
 
-	if !ok {
-		return nil, fmt.Errorf("%q isn't supported by any servers", req.TypeName)
+	if err := checkRequest(rpc, req, func(req *tfprotov5.ValidateResourceTypeConfigRequest) string { return req.TypeName }); err != nil {
+		return nil, err
+	}
+
+	server, err := s.routeRequest(ctx, rpc, req.TypeName, req, s.resourceServer)
+
+	if err != nil {
+		return nil, err
 	}
 
 	ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
+	ctx, cancel := s.rpcTimeoutContext(ctx, rpc, req.TypeName)
+	defer cancel()
+
+	if _, ok := s.options.validationFanOutTypes[req.TypeName]; ok {
+		resp, err := s.validateResourceTypeConfigFanOut(ctx, req)
+
+		return resp, rpcTimeoutError(ctx, rpc, req.TypeName, err)
+	}
+
 	logging.MuxTrace(ctx, "calling downstream server")
 
-	return server.ValidateResourceTypeConfig(ctx, req)
+	resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.resourceNamespace(req.TypeName), server.ValidateResourceTypeConfig))
+
+	return resp, rpcTimeoutError(ctx, rpc, req.TypeName, err)
+}
+
+// validateResourceTypeConfigFanOut calls the ValidateResourceTypeConfig method
+// of each provider, one at a time, combining the Diagnostics.
+func (s muxServer) validateResourceTypeConfigFanOut(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	rpc := "ValidateResourceTypeConfig"
+	var diags []*tfprotov5.Diagnostic
+
+	for idx, server := range s.currentServers() {
+		ctx = logging.Tfprotov5ProviderServerContext(ctx, server)
+		logging.MuxTrace(ctx, "calling downstream server")
+
+		resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.serverNamespace(idx), server.ValidateResourceTypeConfig))
+
+		if err != nil {
+			return nil, fmt.Errorf("error validating %T: %w", server, err)
+		}
+
+		if resp == nil {
+			continue
+		}
+
+		for _, diag := range resp.Diagnostics {
+			if diag == nil {
+				continue
+			}
+
+			diags = append(diags, diag)
+		}
+	}
+
+	return &tfprotov5.ValidateResourceTypeConfigResponse{Diagnostics: diags}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
@@ -64,3 +65,90 @@ func TestMuxServerValidateResourceTypeConfig(t *testing.T) {
 		t.Errorf("expected test_resource_server2 ValidateResourceTypeConfig to be called on server2")
 	}
 }
+
+func TestMuxServerValidateResourceTypeConfigFanOut(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	servers := []func() tfprotov5.ProviderServer{
+		(&tf5testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource_server1": {},
+			},
+			ValidateResourceTypeConfigResponse: &tfprotov5.ValidateResourceTypeConfigResponse{
+				Diagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityWarning,
+						Summary:  "server1 warning",
+					},
+				},
+			},
+		}).ProviderServer,
+		(&tf5testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource_server2": {},
+			},
+			ValidateResourceTypeConfigResponse: &tfprotov5.ValidateResourceTypeConfigResponse{
+				Diagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "server2 error",
+					},
+				},
+			},
+		}).ProviderServer,
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{
+		tf5muxserver.WithValidationFanOut([]string{"test_resource_server2"}),
+	}, servers...)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	resp, err := muxServer.ProviderServer().ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName: "test_resource_server2",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedDiagnostics := []*tfprotov5.Diagnostic{
+		{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  "server1 warning",
+		},
+		{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "server2 error",
+		},
+	}
+
+	if diff := cmp.Diff(resp.Diagnostics, expectedDiagnostics); diff != "" {
+		t.Errorf("unexpected diagnostics difference: %s", diff)
+	}
+
+	for serverIndex, serverFunc := range servers {
+		if !serverFunc().(*tf5testserver.TestServer).ValidateResourceTypeConfigCalled["test_resource_server2"] {
+			t.Errorf("expected test_resource_server2 ValidateResourceTypeConfig to be called on server %d", serverIndex)
+		}
+	}
+
+	_, err = muxServer.ProviderServer().ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName: "test_resource_server1",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !servers[0]().(*tf5testserver.TestServer).ValidateResourceTypeConfigCalled["test_resource_server1"] {
+		t.Errorf("expected test_resource_server1 ValidateResourceTypeConfig to be called on server1")
+	}
+
+	if servers[1]().(*tf5testserver.TestServer).ValidateResourceTypeConfigCalled["test_resource_server1"] {
+		t.Errorf("unexpected test_resource_server1 ValidateResourceTypeConfig called on server2")
+	}
+}
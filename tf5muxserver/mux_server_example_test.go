@@ -3,6 +3,7 @@ package tf5muxserver_test
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
@@ -37,3 +38,39 @@ func ExampleNewMuxServer() {
 		log.Fatalln(err.Error())
 	}
 }
+
+func ExampleWithMiddleware() {
+	ctx := context.Background()
+	providers := []func() tfprotov5.ProviderServer{
+		// Example terraform-plugin-sdk ProviderServer function
+		// sdkprovider.New("version")().GRPCProvider,
+		//
+		// Example terraform-plugin-go ProviderServer function
+		// goprovider.Provider(),
+	}
+
+	// Log the duration and any error of every downstream server call.
+	loggingMiddleware := func(next tf5muxserver.RPCHandler) tf5muxserver.RPCHandler {
+		return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+			start := time.Now()
+			resp, err := next(ctx, rpc, req)
+
+			log.Printf("%s completed in %s (error: %v)", rpc, time.Since(start), err)
+
+			return resp, err
+		}
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{tf5muxserver.WithMiddleware(loggingMiddleware)}, providers...)
+
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	// Use the result to start a muxed provider
+	err = tf5server.Serve("registry.terraform.io/namespace/example", muxServer.ProviderServer)
+
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+}
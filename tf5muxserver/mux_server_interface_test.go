@@ -0,0 +1,132 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxservertest"
+)
+
+// TestMuxServerProviderServerCoverage verifies every tfprotov5.ProviderServer
+// method is delegated to the underlying server, so new upstream RPCs cannot
+// be missed.
+func TestMuxServerProviderServerCoverage(t *testing.T) {
+	t.Parallel()
+
+	// Methods which are intentionally not delegated on each call.
+	notDelegated := map[string]string{
+		"GetProviderSchema": "schemas are cached during server creation",
+	}
+
+	ctx := context.Background()
+	recordingServer := tf5muxservertest.NewRecordingServer(&tf5testserver.TestServer{
+		DataSourceSchemas: map[string]*tfprotov5.Schema{
+			"test_data_source": {},
+		},
+		ResourceSchemas: map[string]*tfprotov5.Schema{
+			"test_resource": {},
+		},
+	})
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, recordingServer.ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	providerServerType := reflect.TypeOf((*tfprotov5.ProviderServer)(nil)).Elem()
+	muxServerValue := reflect.ValueOf(muxServer.ProviderServer())
+	recordingServerValue := reflect.ValueOf(recordingServer)
+
+	for i := 0; i < providerServerType.NumMethod(); i++ {
+		method := providerServerType.Method(i)
+
+		if reason, ok := notDelegated[method.Name]; ok {
+			t.Logf("skipping %s: %s", method.Name, reason)
+			continue
+		}
+
+		t.Run(method.Name, func(t *testing.T) {
+			lastRequestMethod := recordingServerValue.MethodByName("Last" + method.Name + "Request")
+
+			if !lastRequestMethod.IsValid() {
+				t.Fatalf("RecordingServer does not record %s requests", method.Name)
+			}
+
+			req := reflect.New(method.Type.In(1).Elem())
+
+			if typeName := req.Elem().FieldByName("TypeName"); typeName.IsValid() {
+				if strings.Contains(method.Name, "Data") {
+					typeName.SetString("test_data_source")
+				} else {
+					typeName.SetString("test_resource")
+				}
+			}
+
+			results := muxServerValue.MethodByName(method.Name).Call([]reflect.Value{reflect.ValueOf(ctx), req})
+
+			if err, ok := results[1].Interface().(error); ok && err != nil {
+				t.Fatalf("unexpected error calling %s: %s", method.Name, err)
+			}
+
+			if got := lastRequestMethod.Call(nil)[0]; got.Pointer() != req.Pointer() {
+				t.Errorf("%s was not delegated to the underlying server", method.Name)
+			}
+		})
+	}
+}
+
+// TestMuxServerProviderServerStrictResponseFields verifies every
+// tfprotov5.ProviderServer method succeeds with the WithStrictResponseFields
+// option enabled, so the responses of the underlying server are understood.
+func TestMuxServerProviderServerStrictResponseFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+		ctx,
+		[]tf5muxserver.ServerOption{tf5muxserver.WithStrictResponseFields()},
+		(&tf5testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov5.Schema{
+				"test_data_source": {},
+			},
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource": {},
+			},
+		}).ProviderServer,
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	providerServerType := reflect.TypeOf((*tfprotov5.ProviderServer)(nil)).Elem()
+	muxServerValue := reflect.ValueOf(muxServer.ProviderServer())
+
+	for i := 0; i < providerServerType.NumMethod(); i++ {
+		method := providerServerType.Method(i)
+
+		t.Run(method.Name, func(t *testing.T) {
+			req := reflect.New(method.Type.In(1).Elem())
+
+			if typeName := req.Elem().FieldByName("TypeName"); typeName.IsValid() {
+				if strings.Contains(method.Name, "Data") {
+					typeName.SetString("test_data_source")
+				} else {
+					typeName.SetString("test_resource")
+				}
+			}
+
+			results := muxServerValue.MethodByName(method.Name).Call([]reflect.Value{reflect.ValueOf(ctx), req})
+
+			if err, ok := results[1].Interface().(error); ok && err != nil {
+				t.Errorf("unexpected error calling %s: %s", method.Name, err)
+			}
+		})
+	}
+}
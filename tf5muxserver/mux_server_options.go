@@ -0,0 +1,978 @@
+package tf5muxserver
+
+import (
+	"time"
+)
+
+// ServerOption is a functional option for configuring optional muxServer
+// behaviors. Options are passed to NewMuxServerWithOptions.
+type ServerOption func(*serverOptions)
+
+// serverOptions contains the optional muxServer behaviors. The zero value
+// matches the behaviors of NewMuxServer.
+type serverOptions struct {
+	// providerSchemaMerge enables combining provider schema attributes
+	// across servers, rather than requiring identical provider schemas.
+	providerSchemaMerge bool
+
+	// startupSchemaDump enables logging the type names declared by each
+	// server during creation.
+	startupSchemaDump bool
+
+	// maxConcurrency is the maximum number of downstream server calls made
+	// simultaneously by fan-out operations. Values less than 1 use
+	// runtime.GOMAXPROCS.
+	maxConcurrency int
+
+	// concurrentStopProvider enables calling StopProvider on all servers
+	// concurrently.
+	concurrentStopProvider bool
+
+	// dataSourceCacheTypes are the data source types whose ReadDataSource
+	// responses are cached for dataSourceCacheTTL.
+	dataSourceCacheTypes []string
+	dataSourceCacheTTL   time.Duration
+
+	// diagnosticProvenance enables appending the server Go type to the
+	// Detail of diagnostics aggregated from multiple servers.
+	diagnosticProvenance bool
+
+	// serverReuseDisabled enables creating new servers on each
+	// ProviderServer call, rather than reusing the servers created during
+	// muxServer creation.
+	serverReuseDisabled bool
+
+	// emptyServerError enables returning an error, rather than logging a
+	// warning, when a server declares no resources, data sources, or
+	// provider schema.
+	emptyServerError bool
+
+	// conflictResolution is the policy for schema declarations which
+	// conflict across servers.
+	conflictResolution ConflictResolution
+
+	// rpcTimeouts are the deadlines of routed RPC calls, keyed by RPC name.
+	rpcTimeouts map[string]time.Duration
+
+	// requireProviderSchema enables returning an error when no server
+	// declares a provider schema.
+	requireProviderSchema bool
+
+	// middleware wraps each downstream server call, outermost first.
+	middleware []Middleware
+
+	// orphanedResourceHandler handles ReadResource requests for resource
+	// types which are not implemented by any server.
+	orphanedResourceHandler OrphanedResourceHandler
+
+	// validationFanOutTypes are the resource types whose
+	// ValidateResourceTypeConfig requests are sent to all servers.
+	validationFanOutTypes map[string]struct{}
+
+	// schemaCacheFile is the path of the file caching schemas and routing
+	// across muxed server creations, which are identified by
+	// schemaCacheVersion.
+	schemaCacheFile    string
+	schemaCacheVersion string
+
+	// schemaCacheContentHash enables identifying schema cache files by the
+	// content of the server schemas in addition to schemaCacheVersion.
+	schemaCacheContentHash bool
+
+	// bestEffortSchema enables excluding servers which fail to return their
+	// schema, rather than returning an error.
+	bestEffortSchema bool
+
+	// planShortCircuitTypes are the resource types whose PlanResourceChange
+	// requests are answered by the muxed server when the prior state equals
+	// the proposed new state.
+	planShortCircuitTypes map[string]struct{}
+
+	// upgradeResourceStateVersionCheck enables returning an error diagnostic
+	// when the UpgradeResourceState request version exceeds the resource
+	// schema version.
+	upgradeResourceStateVersionCheck bool
+
+	// deprecationsWarning enables returning a warning listing the deprecated
+	// schema elements of all servers from ConfigureProvider.
+	deprecationsWarning bool
+
+	// payloadSizeLogging enables logging the byte sizes of downstream server
+	// call request and response payloads.
+	payloadSizeLogging bool
+
+	// caseCollisionWarnings enables warning about type names implemented by
+	// different servers which differ only by case.
+	caseCollisionWarnings bool
+
+	// warningsAsErrors enables promoting warning diagnostics from servers to
+	// error severity.
+	warningsAsErrors bool
+
+	// destroyLogging enables logging ApplyResourceChange requests which
+	// destroy the resource.
+	destroyLogging bool
+
+	// upgradeResourceStateDiagnostics returns diagnostics to prepend to
+	// UpgradeResourceState responses.
+	upgradeResourceStateDiagnostics UpgradeResourceStateDiagnosticsFunc
+
+	// dynamicValueRoundTripValidation enables validating that request and
+	// response DynamicValues survive decoding and encoding unchanged.
+	dynamicValueRoundTripValidation bool
+
+	// maxSchemaSize is the maximum estimated size, in bytes, of the muxed
+	// server schemas. Values less than 1 disable the check.
+	maxSchemaSize int
+
+	// customRouters are consulted to select the server handling requests
+	// for each type name, instead of the server which declared the type.
+	customRouters map[string]Router
+
+	// unimplementedRPCDiagnostics enables returning an error Diagnostic
+	// naming the RPC and server when a server does not implement the RPC.
+	unimplementedRPCDiagnostics bool
+
+	// pingFunc is called by Ping for each server. If nil, the
+	// GetProviderSchema method of each server is called.
+	pingFunc PingFunc
+
+	// stateNormalizationTypes are the resource types whose server returned
+	// states are replaced with the semantically equal request states.
+	stateNormalizationTypes map[string]struct{}
+
+	// maxAttributeCount is the maximum number of attributes of the muxed
+	// server schemas. Values less than 1 disable the check.
+	maxAttributeCount int
+
+	// requireSharedProviderSchema enables returning an error when any server
+	// does not declare the identical provider schema.
+	requireSharedProviderSchema bool
+
+	// crossServerImportErrors enables returning error Diagnostics, instead
+	// of warning Diagnostics, for imported resources implemented by a server
+	// other than the server handling the import.
+	crossServerImportErrors bool
+
+	// readOnly enables rejecting RPCs which modify infrastructure.
+	readOnly bool
+
+	// configureOrder are the server indexes in the order ConfigureProvider
+	// is called. If nil, servers are called in the order given.
+	configureOrder []int
+
+	// namespaces are the prefixes added to the resource and data source type
+	// names of each server, by server index.
+	namespaces map[int]string
+
+	// conflictSchemaDump is the path of the file the server schemas are
+	// written to when a conflict aborts muxed server creation.
+	conflictSchemaDump string
+
+	// providerMetaSchemaMerge enables combining provider meta schema
+	// attributes across servers.
+	providerMetaSchemaMerge bool
+
+	// duplicateSchemaNameValidation enables checking the muxed server
+	// schemas for attribute and nested block names declared more than once.
+	duplicateSchemaNameValidation bool
+
+	// schemaContract is the path of the schema contract file the muxed
+	// server schemas must match. Empty disables the check.
+	schemaContract string
+
+	// warmupFunc is called by Warmup for each server. If nil, the
+	// ConfigureProvider method of each server is called.
+	warmupFunc WarmupFunc
+
+	// deprecationHints are the replacement resource types of deprecated
+	// resource types, keyed by deprecated resource type.
+	deprecationHints map[string]string
+
+	// timeoutHints enables reading per-type RPC timeouts from servers
+	// implementing TimeoutHintProvider.
+	timeoutHints bool
+
+	// strictResponseFields enables returning an error for server responses
+	// setting fields which the muxed server does not understand.
+	strictResponseFields bool
+
+	// sharedConfigure enables sharing the ConfigureProvider result of the
+	// server at the sharedConfigurePrimary index with the other servers.
+	sharedConfigure        bool
+	sharedConfigurePrimary int
+
+	// featureServerOverlapWarnings enables warning Diagnostics for types
+	// declared by servers implementing FeatureProvider but routed to other
+	// servers.
+	featureServerOverlapWarnings bool
+
+	// latencyMetrics enables recording downstream server call latencies into
+	// histograms with the latencyBuckets upper bounds.
+	latencyMetrics bool
+	latencyBuckets []time.Duration
+}
+
+// WithProviderSchemaMerge enables combining the provider schemas of all
+// servers into a single provider schema, rather than requiring every server
+// to declare an identical provider schema. This supports splitting the
+// provider configuration between servers, where each server only declares
+// the provider configuration attributes it uses.
+//
+// When enabled:
+//
+//   - Provider schema attributes are combined across servers. An attribute
+//     may only be defined by one server.
+//   - Provider schema and block versions must match across servers.
+//   - Provider schema nested blocks are combined across servers. A nested
+//     block defined by multiple servers must be identical, including its
+//     nesting mode and item limits.
+//   - PrepareProviderConfig is only called on servers which declared a
+//     provider schema. PreparedConfig attribute values are taken from the
+//     server which defined the attribute. If no server declared a provider
+//     schema, PrepareProviderConfig is called on all servers.
+//   - Each server receives a PrepareProviderConfig and ConfigureProvider
+//     request Config containing only the attributes and nested blocks
+//     declared in its own provider schema, re-encoded using that schema's
+//     type, so the server can decode it. Servers which did not declare a
+//     provider schema receive an empty ConfigureProvider configuration.
+func WithProviderSchemaMerge(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.providerSchemaMerge = enabled
+	}
+}
+
+// WithStartupSchemaDump enables logging, at DEBUG level, the index, Go type,
+// and sorted resource and data source type names of each server as its schema
+// is retrieved during creation. Each server is logged before its type names
+// are checked for conflicts, so the logs identify the server declaring a
+// duplicate type name.
+func WithStartupSchemaDump(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.startupSchemaDump = enabled
+	}
+}
+
+// WithMaxConcurrency limits the number of downstream server calls made
+// simultaneously by fan-out operations which call every server concurrently,
+// such as Warmup. The limit is shared across all such operations of the muxed
+// server, so they cannot overwhelm servers which hold limited resources, such
+// as connection pools. A value of 1 forces every fan-out call to happen
+// sequentially, which can be helpful for debugging. Values less than 1 use
+// the default, runtime.GOMAXPROCS(0).
+//
+// Fan-out RPCs which call servers one at a time, such as ConfigureProvider,
+// are not limited. StopProvider is never limited, so it always reaches every
+// server, even while other calls hold the limit.
+func WithMaxConcurrency(n int) ServerOption {
+	return func(o *serverOptions) {
+		o.maxConcurrency = n
+	}
+}
+
+// WithConcurrentStopProvider enables calling the StopProvider method of all
+// servers concurrently, rather than one at a time, to reduce shutdown delays
+// with many servers. Unlike other fan-out operations, the calls are not
+// limited by WithMaxConcurrency.
+//
+// The StopProvider request waits until all servers return or the request
+// context is done. Errors returned by servers and non-empty response Error
+// fields are joined into the response Error field. As when stopping servers
+// one at a time, if any server did not return before the context was done,
+// an error wrapping the context error is returned instead, which includes a
+// "stop timed out" message for each such server.
+func WithConcurrentStopProvider(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.concurrentStopProvider = enabled
+	}
+}
+
+// WithDataSourceCache enables caching ReadDataSource responses of the given
+// data source types for the given TTL, for data sources which are expensive
+// and repeatedly read with identical configuration. Responses are cached by
+// type name and request Config and ProviderMeta. Only the given types are
+// cached, requests which miss the cache are routed to the server as usual,
+// and responses with error diagnostics are not cached. A TTL less than or
+// equal to zero disables caching. At most 1024 responses are cached, and the
+// cache is cleared when Reroute or ReplaceServer change routing.
+//
+// Cached responses are shared between requests and must not be modified.
+func WithDataSourceCache(types []string, ttl time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.dataSourceCacheTypes = types
+		o.dataSourceCacheTTL = ttl
+	}
+}
+
+// WithDiagnosticProvenance enables appending "(via <Go type>)", such as
+// "(via *provider.Server)", to the Detail of each diagnostic aggregated from
+// multiple servers, so practitioners and provider developers can identify the
+// server which produced it. This applies to ConfigureProvider and
+// PrepareProviderConfig. It is disabled by default so that diagnostics are
+// returned exactly as produced by each server.
+func WithDiagnosticProvenance(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.diagnosticProvenance = enabled
+	}
+}
+
+// WithServerReuse controls whether the muxed server ProviderServer method
+// reuses the servers created by calling each server function during creation,
+// which is the default, or calls each server function again to create new
+// servers.
+//
+// When enabled, the same server instances handle every request, so they must
+// be safe for concurrent use, and any state, such as the configuration from
+// ConfigureProvider, is shared. This suits servers which are expensive to
+// create. When disabled, each ProviderServer call returns a muxed server with
+// new server instances which share no state with other calls, which suits
+// servers that must not be shared across connections. New servers are
+// expected to declare the same schemas as the servers created during muxed
+// server creation, as their GetProviderSchema methods are not called.
+func WithServerReuse(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.serverReuseDisabled = !enabled
+	}
+}
+
+// WithEmptyServerError enables returning an error during muxed server
+// creation when any server declares no resources, data sources, or provider
+// schema, which is usually caused by passing the wrong server function. By
+// default, a warning is logged with the server index and Go type instead.
+func WithEmptyServerError(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.emptyServerError = enabled
+	}
+}
+
+// WithConflictResolution sets the policy for resolving resource types, data
+// source types, provider schemas, and provider meta schemas which conflict
+// across servers. By default, ConflictResolutionError returns the first
+// conflict as an error. Other policies allow creating a usable muxed server
+// while collecting every conflict, available via the muxed server Conflicts
+// method, such as for migration tooling which reports all overlaps between
+// servers in one pass.
+func WithConflictResolution(policy ConflictResolution) ServerOption {
+	return func(o *serverOptions) {
+		o.conflictResolution = policy
+	}
+}
+
+// WithRPCTimeout bounds each call of the given RPC, such as
+// "ApplyResourceChange" or "ReadResource", with a deadline derived from the
+// request context. If the deadline is exceeded, the call returns an error
+// naming the RPC and the requested type name. The deadline is applied to the
+// RPCs routed to a single server by type name. RPCs without a configured
+// timeout receive the request context unchanged. Servers must honor context
+// cancellation for the call to return at the deadline.
+func WithRPCTimeout(rpc string, d time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		if o.rpcTimeouts == nil {
+			o.rpcTimeouts = make(map[string]time.Duration)
+		}
+
+		o.rpcTimeouts[rpc] = d
+	}
+}
+
+// WithRequireProviderSchema enables returning a *NoProviderSchemaError during
+// muxed server creation when no server declares a provider schema. Otherwise,
+// the muxed server GetProviderSchema method responds without a provider
+// schema. It is disabled by default, as some providers legitimately have no
+// provider configuration.
+func WithRequireProviderSchema(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.requireProviderSchema = enabled
+	}
+}
+
+// WithMiddleware adds middleware around every downstream server call made by
+// the muxed server after creation. Fan-out RPCs, such as ConfigureProvider,
+// call the middleware once per server. Middleware given earlier, including
+// across multiple WithMiddleware options, wraps middleware given later.
+// GetProviderSchema is served from schemas cached during creation, so it is
+// not a downstream server call.
+func WithMiddleware(middleware ...Middleware) ServerOption {
+	return func(o *serverOptions) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+// WithOrphanedResourceHandler configures a handler for ReadResource requests
+// of resource types which are not implemented by any server, rather than
+// returning an error. Without a handler, such resources cannot be managed
+// after the server implementing them is removed, such as when rolling back a
+// provider split. RemoveOrphanedResource can be used to remove these
+// resources from Terraform state.
+func WithOrphanedResourceHandler(handler OrphanedResourceHandler) ServerOption {
+	return func(o *serverOptions) {
+		o.orphanedResourceHandler = handler
+	}
+}
+
+// WithValidationFanOut enables sending ValidateResourceTypeConfig requests for
+// the given resource types to every server, rather than only the server
+// implementing the resource type, for resources whose configuration validity
+// also depends on rules enforced by other servers. Diagnostics from all
+// servers are combined in server order. Servers which do not implement the
+// resource type must still respond without error. Resource types which are
+// not implemented by any server still return an error.
+func WithValidationFanOut(types []string) ServerOption {
+	return func(o *serverOptions) {
+		if o.validationFanOutTypes == nil {
+			o.validationFanOutTypes = make(map[string]struct{}, len(types))
+		}
+
+		for _, typeName := range types {
+			o.validationFanOutTypes[typeName] = struct{}{}
+		}
+	}
+}
+
+// WithSchemaCacheFile enables caching the schemas and the type name routing
+// of the muxed server in the file at the given path, to reduce startup time
+// when the provider is started repeatedly, such as in CI. If the file was
+// written for the same version, the same number, order, and Go types of
+// servers, and the same options affecting schemas and routing, such as
+// WithNamespace, WithProviderSchemaMerge, and WithConflictResolution, the
+// muxed server is created from the file without calling the
+// GetProviderSchema method of any server. Otherwise, such as when the file
+// does not exist or is invalid, schemas are retrieved from the servers as
+// usual and the file is rewritten. Failing to write the file is logged as a
+// warning rather than returned as an error.
+//
+// The version must change whenever any server schema changes, such as by
+// using the provider version, as the cache cannot otherwise detect the
+// change, unless the WithSchemaCacheContentHash option is enabled. When the
+// muxed server is created from the file, schema validations and logging
+// which require the GetProviderSchema responses, such as the
+// WithStartupSchemaDump and WithEmptyServerError options, do not apply. The
+// file is not written if conflicts were collected under the
+// WithConflictResolution option.
+func WithSchemaCacheFile(path string, version string) ServerOption {
+	return func(o *serverOptions) {
+		o.schemaCacheFile = path
+		o.schemaCacheVersion = version
+	}
+}
+
+// WithBestEffortSchema enables excluding servers whose GetProviderSchema
+// method returns an error or error diagnostics during muxed server creation,
+// rather than returning an error. Excluded servers receive no requests, so the
+// resources and data sources they implement are unavailable. Each excluded
+// server is logged as a warning and reported as a warning diagnostic in the
+// muxed server GetProviderSchema response.
+func WithBestEffortSchema(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.bestEffortSchema = enabled
+	}
+}
+
+// WithPlanResourceChangeShortCircuit enables responding to PlanResourceChange
+// requests for the given resource types without calling the server
+// implementing the resource type when the prior state equals the proposed new
+// state, for resources whose planning is expensive even without changes. The
+// planned state is the proposed new state and the planned private state is
+// the prior private state. States are compared using the resource schema
+// type, and if they cannot be compared, the request is routed to the server
+// as usual.
+//
+// This changes planning semantics, so it must only be enabled for resource
+// types which would return the proposed new state unchanged, such as resources
+// without plan modification or normalization logic. Middleware is not called
+// for requests answered by the muxed server.
+func WithPlanResourceChangeShortCircuit(types []string) ServerOption {
+	return func(o *serverOptions) {
+		if o.planShortCircuitTypes == nil {
+			o.planShortCircuitTypes = make(map[string]struct{}, len(types))
+		}
+
+		for _, typeName := range types {
+			o.planShortCircuitTypes[typeName] = struct{}{}
+		}
+	}
+}
+
+// WithUpgradeResourceStateVersionCheck enables prepending an error
+// diagnostic to the response diagnostics of UpgradeResourceState requests
+// whose state version is greater than the version of the resource schema.
+// Such states were usually written by a newer version of the provider, which
+// servers cannot upgrade from. The request is still routed to the server
+// implementing the resource type, so the response also includes the server
+// diagnostics. By default, the server must handle the version on its own. In
+// either case, the raw state and version are passed to the server unchanged.
+func WithUpgradeResourceStateVersionCheck(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.upgradeResourceStateVersionCheck = enabled
+	}
+}
+
+// WithDeprecationsWarning enables returning a single warning diagnostic from
+// ConfigureProvider listing the deprecated provider schema attributes and
+// nested blocks, managed resources, and data sources declared across all
+// servers, so practitioners can plan migrations. No warning is returned if
+// nothing is deprecated or if ConfigureProvider returns an error diagnostic.
+func WithDeprecationsWarning(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.deprecationsWarning = enabled
+	}
+}
+
+// WithPayloadSizeLogging enables logging, at DEBUG level, the total byte size
+// of the DynamicValue and raw state payloads of each downstream server call
+// request and response, along with the RPC, server Go type, and resource or
+// data source type name, to diagnose memory pressure caused by large values,
+// such as large resource states. Payload contents are not logged. Sizes are
+// logged inside any middleware configured via WithMiddleware.
+func WithPayloadSizeLogging(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.payloadSizeLogging = enabled
+	}
+}
+
+// WithCaseCollisionWarnings enables checking, during muxed server creation,
+// for resource or data source type names implemented by different servers
+// which differ only by case, such as test_Resource and test_resource. Type
+// names are case-sensitive, so both types are still served, but such names
+// are usually caused by a typo. Each pair of type names is logged as a warning
+// and reported as a warning diagnostic, including both type names and server
+// Go types, in the muxed server GetProviderSchema response.
+func WithCaseCollisionWarnings(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.caseCollisionWarnings = enabled
+	}
+}
+
+// WithWarningsAsErrors enables promoting warning diagnostics returned by
+// servers to error severity, such as to fail continuous integration pipelines
+// on deprecations. It applies to:
+//
+//   - ConfigureProvider, where the first promoted warning aborts configuring
+//     the remaining servers, as with any error diagnostic.
+//   - GetProviderSchema during muxed server creation, where a promoted
+//     warning is returned as an error, or excludes the server if the
+//     WithBestEffortSchema option is enabled.
+//
+// Warnings produced by the muxed server itself, such as those enabled by the
+// WithDeprecationsWarning option, are not promoted.
+func WithWarningsAsErrors(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.warningsAsErrors = enabled
+	}
+}
+
+// WithDestroyLogging enables logging, at INFO level, each ApplyResourceChange
+// request which destroys a resource, as signaled by a null planned state, with
+// the resource type name and the Go type of the server implementing it, so
+// operators can audit destroys flowing through the muxed server. The planned
+// state is decoded using the resource schema type.
+func WithDestroyLogging(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.destroyLogging = enabled
+	}
+}
+
+// WithUpgradeResourceStateDiagnostics configures a function returning muxed
+// server diagnostics for each UpgradeResourceState request, such as warnings
+// about the state version. The diagnostics are prepended to the diagnostics
+// returned by the server implementing the resource type, without changing its
+// upgraded state. The function is only called after the server responds
+// without an error.
+func WithUpgradeResourceStateDiagnostics(f UpgradeResourceStateDiagnosticsFunc) ServerOption {
+	return func(o *serverOptions) {
+		o.upgradeResourceStateDiagnostics = f
+	}
+}
+
+// WithDynamicValueRoundTripValidation enables a debugging mode which validates
+// each DynamicValue of downstream server call requests and responses, such as
+// to catch serialization bugs in protocol translation layers. Each
+// DynamicValue is decoded with the schema type of the provider, provider meta,
+// resource, or data source schema it belongs to, encoded again, and compared
+// to the original. Each failure is logged at ERROR level with the RPC, field,
+// and resource or data source type name. Requests and responses are not
+// changed.
+//
+// Decoding every DynamicValue is expensive, so this option should only be
+// enabled for debugging. Validation happens inside any middleware configured
+// via WithMiddleware.
+func WithDynamicValueRoundTripValidation(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.dynamicValueRoundTripValidation = enabled
+	}
+}
+
+// WithMaxSchemaSize enables returning an error during muxed server creation
+// if the estimated size of the muxed server GetProviderSchema response exceeds
+// the given number of bytes, such as the gRPC maximum message size of
+// Terraform. This reports very large muxed schemas clearly, including their
+// size, rather than Terraform failing opaquely when receiving the response.
+// The size is estimated from the protocol buffers encoding of the schemas and
+// may differ slightly from the actual message size. Values less than 1
+// disable the check, which is the default.
+func WithMaxSchemaSize(bytes int) ServerOption {
+	return func(o *serverOptions) {
+		o.maxSchemaSize = bytes
+	}
+}
+
+// WithCustomRouter configures a Router selecting the server handling each
+// request for the given resource or data source type name, instead of the
+// server which declared the type, such as to split the resources of one type
+// across servers by region. The Router is consulted before the type routing
+// created during muxed server creation or changed via Reroute.
+//
+// Each server the Router selects must implement the type, so multiple servers
+// usually declare it, which requires the WithConflictResolution option. The
+// muxed server GetProviderSchema response contains the schema kept by the
+// conflict resolution, so the schemas should be identical.
+func WithCustomRouter(typeName string, router Router) ServerOption {
+	return func(o *serverOptions) {
+		if o.customRouters == nil {
+			o.customRouters = make(map[string]Router)
+		}
+
+		o.customRouters[typeName] = router
+	}
+}
+
+// WithUnimplementedRPCDiagnostics enables translating errors from servers
+// which do not implement the ImportResourceState RPC, such as minimal servers
+// implementing only a subset of the provider server RPCs, into an error
+// Diagnostic naming the RPC, type name, and server. Servers indicate this via
+// a gRPC status error with the Unimplemented code. By default, the server
+// error is returned unchanged.
+func WithUnimplementedRPCDiagnostics(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.unimplementedRPCDiagnostics = enabled
+	}
+}
+
+// WithPingFunc configures the PingFunc called by Ping for each server to
+// verify it is healthy, such as to call a cheaper or more representative RPC
+// than the default GetProviderSchema.
+func WithPingFunc(f PingFunc) ServerOption {
+	return func(o *serverOptions) {
+		o.pingFunc = f
+	}
+}
+
+// WithStateNormalization enables replacing the states returned by the server
+// implementing the given resource types with the request states they are
+// semantically equal to, decoded using the resource schema type, to smooth
+// over encoding differences which Terraform may report as spurious changes,
+// such as a different order of set elements. This applies to the
+// PlanResourceChange PlannedState compared to the ProposedNewState, the
+// ApplyResourceChange NewState compared to the PlannedState, and the
+// ReadResource NewState compared to the CurrentState. States which are not
+// semantically equal are returned unchanged.
+//
+// This hides the exact values returned by the server, so it should only be
+// enabled for resource types known to return such encoding differences.
+func WithStateNormalization(types []string) ServerOption {
+	return func(o *serverOptions) {
+		if o.stateNormalizationTypes == nil {
+			o.stateNormalizationTypes = make(map[string]struct{}, len(types))
+		}
+
+		for _, typeName := range types {
+			o.stateNormalizationTypes[typeName] = struct{}{}
+		}
+	}
+}
+
+// WithMaxAttributeCount enables returning an error during muxed server
+// creation if the muxed server schemas declare more than the given number of
+// attributes, including the attributes within nested blocks, naming the
+// servers with the most attributes. This catches servers with malformed or
+// runaway generated schemas, which would make Terraform unusable. The counts
+// are available via AttributeCounts. Values less than 1 disable the check,
+// which is the default.
+func WithMaxAttributeCount(count int) ServerOption {
+	return func(o *serverOptions) {
+		o.maxAttributeCount = count
+	}
+}
+
+// WithRequireSharedProviderSchema enables returning an error during muxed
+// server creation unless every server declares the identical provider schema,
+// such as when all servers read the same provider configuration. Otherwise,
+// servers which declare no provider schema are allowed. As every server
+// declares the provider schema, the PrepareProviderConfig and
+// ConfigureProvider requests of the muxed server are sent to all servers. This
+// takes precedence over the WithProviderSchemaMerge option.
+func WithRequireSharedProviderSchema(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.requireSharedProviderSchema = enabled
+	}
+}
+
+// WithCrossServerImportErrors enables returning an error Diagnostic, instead
+// of a warning Diagnostic, from ImportResourceState when the server handling
+// the import returns an imported resource whose type is implemented by a
+// different server. Later operations on such imported resources are routed
+// to the other server, which may not support the imported state.
+func WithCrossServerImportErrors(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.crossServerImportErrors = enabled
+	}
+}
+
+// WithReadOnly enables rejecting the ApplyResourceChange and
+// ImportResourceState requests of the muxed server with an error Diagnostic,
+// without calling any server, such as for disaster recovery or audit
+// scenarios where infrastructure must not be modified. Other RPCs, such as
+// PlanResourceChange, ReadResource, and ReadDataSource, are unaffected.
+func WithReadOnly(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.readOnly = enabled
+	}
+}
+
+// WithConfigureOrder sets the order in which the ConfigureProvider method of
+// each server is called, as indexes of the servers in the order given to
+// NewMuxServer, excluding servers excluded via the WithBestEffortSchema
+// option. This is useful when a server must be configured before another,
+// such as when it sets up a client shared with the other server. An error is
+// returned during muxed server creation unless the indexes include every
+// server exactly once. By default, servers are configured in the order given.
+func WithConfigureOrder(order []int) ServerOption {
+	return func(o *serverOptions) {
+		if order == nil {
+			o.configureOrder = nil
+
+			return
+		}
+
+		o.configureOrder = append([]int{}, order...)
+	}
+}
+
+// WithNamespace adds the prefix to the resource and data source type names of
+// the server at the given index, in the order given to NewMuxServer, such as
+// to mux servers which implement the same type names without conflicts. The
+// muxed server GetProviderSchema response includes the prefixed type names,
+// while requests are sent to the server with the prefix removed from the type
+// name and the prefix is added to the type names of resources imported by
+// the server. Middleware observes the prefixed type names.
+func WithNamespace(serverIndex int, prefix string) ServerOption {
+	return func(o *serverOptions) {
+		if o.namespaces == nil {
+			o.namespaces = make(map[int]string)
+		}
+
+		o.namespaces[serverIndex] = prefix
+	}
+}
+
+// WithConflictSchemaDump enables writing the schemas declared by each server
+// as JSON to the file at the given path when a conflict aborts muxed server
+// creation, such as to diff the schemas offline after a failed CI run. The
+// file contains the servers retrieved before the conflict, including the
+// conflicting server, and is not written if creation succeeds or conflicts
+// are resolved via the WithConflictResolution option. Failures writing the
+// file are logged and do not change the returned error. An empty path
+// disables the file, which is the default.
+func WithConflictSchemaDump(path string) ServerOption {
+	return func(o *serverOptions) {
+		o.conflictSchemaDump = path
+	}
+}
+
+// WithProviderMetaSchemaMerge enables combining the provider meta schemas of
+// all servers into a single provider meta schema, rather than requiring every
+// server to declare an identical provider meta schema, in the same manner as
+// the WithProviderSchemaMerge option combines provider schemas. A provider
+// meta schema attribute may only be defined by one server. The request
+// ProviderMeta of the ApplyResourceChange, PlanResourceChange, ReadDataSource,
+// and ReadResource RPCs is projected to the provider meta schema of the
+// server handling the request, so the server can decode it.
+func WithProviderMetaSchemaMerge(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.providerMetaSchemaMerge = enabled
+	}
+}
+
+// WithDuplicateSchemaNameValidation enables returning an error during muxed
+// server creation if any block of the muxed server schemas declares an
+// attribute or nested block name more than once, naming the provider,
+// resource, or data source schema and the duplicated name. Terraform rejects
+// such schemas opaquely, so this catches schema bugs of the servers early.
+func WithDuplicateSchemaNameValidation(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.duplicateSchemaNameValidation = enabled
+	}
+}
+
+// WithSchemaContract enables returning an error during muxed server creation
+// if the muxed server provider, resource, and data source schemas do not
+// match the schema contract in the JSON file at the given path, such as to
+// prevent unreviewed schema changes. Differences are returned as a
+// *SchemaContractError. An empty path disables the check, which is the
+// default.
+//
+// The schema contract declares the attribute names, types, and required,
+// optional, and computed flags of each block, and the nested blocks of each
+// block. Omitted schemas are compared as empty. Attribute types use the
+// tftypes JSON type encoding. For example:
+//
+//	{
+//	  "provider": {
+//	    "attributes": {
+//	      "region": {"type": "string", "optional": true}
+//	    }
+//	  },
+//	  "resources": {
+//	    "examplecloud_thing": {
+//	      "attributes": {
+//	        "id": {"type": "string", "computed": true},
+//	        "tags": {"type": ["map", "string"], "optional": true}
+//	      },
+//	      "block_types": {
+//	        "timeouts": {
+//	          "attributes": {
+//	            "create": {"type": "string", "optional": true}
+//	          }
+//	        }
+//	      }
+//	    }
+//	  },
+//	  "data_sources": {}
+//	}
+func WithSchemaContract(path string) ServerOption {
+	return func(o *serverOptions) {
+		o.schemaContract = path
+	}
+}
+
+// WithWarmupFunc configures the WarmupFunc called by Warmup for each server
+// to prepare it to serve requests, such as to call a custom warmup hook of
+// the server rather than the default ConfigureProvider with a null provider
+// configuration.
+func WithWarmupFunc(f WarmupFunc) ServerOption {
+	return func(o *serverOptions) {
+		o.warmupFunc = f
+	}
+}
+
+// WithDeprecationHint enables adding a warning Diagnostic to the
+// PlanResourceChange and ReadResource responses of the deprecated resource
+// type, pointing practitioners to the replacement resource type, such as
+// when a resource implemented by one server is replaced by a new resource
+// type implemented by another server. An error is returned during muxed
+// server creation if either resource type isn't supported by any servers.
+// This option may be given multiple times for different deprecated resource
+// types.
+func WithDeprecationHint(oldType string, newType string) ServerOption {
+	return func(o *serverOptions) {
+		if o.deprecationHints == nil {
+			o.deprecationHints = make(map[string]string)
+		}
+
+		o.deprecationHints[oldType] = newType
+	}
+}
+
+// WithTimeoutHints enables reading per-type RPC timeouts from servers
+// implementing the TimeoutHintProvider interface, such as to allow longer
+// calls for resource types known to be slow. Hints are read during muxed
+// server creation and again for the affected servers when Reroute or
+// ReplaceServer change routing. A hinted timeout only applies to RPCs with a
+// timeout configured via WithRPCTimeout, and only extends it: RPCs without a
+// configured timeout never receive a deadline, and hints shorter than the
+// configured timeout are ignored. Hints for type names which the server does
+// not implement are ignored.
+func WithTimeoutHints() ServerOption {
+	return func(o *serverOptions) {
+		o.timeoutHints = true
+	}
+}
+
+// WithStrictResponseFields enables returning an error when a server returns
+// a response setting fields which the muxed server does not understand, and
+// therefore may not forward, rather than silently dropping them. This guards
+// against losing data after upgrading terraform-plugin-go, when servers may
+// set response fields added by newer protocol versions before the muxed
+// server supports them.
+//
+// Fields are detected via reflection, comparing the exported fields set to a
+// non-zero value in each response against the fields known to the muxed
+// server. Only the top-level fields of responses are checked. The
+// GetProviderSchema response of each server is checked during muxed server
+// creation, unless schemas are restored via WithSchemaCacheFile, and the
+// responses of all other RPCs are checked on each call, before any
+// middleware configured via WithMiddleware receives them.
+func WithStrictResponseFields() ServerOption {
+	return func(o *serverOptions) {
+		o.strictResponseFields = true
+	}
+}
+
+// WithSharedConfigure enables sharing the result of the ConfigureProvider
+// call of the primary server with the other servers, such as a configured
+// API client, so split providers do not each re-establish connections. The
+// primary server is the server at the given index, in the order given to
+// NewMuxServer, excluding servers excluded via the WithBestEffortSchema
+// option, and must implement the ConfigureResultSharer interface.
+//
+// The primary server is configured first. After it is configured without
+// error Diagnostics, its ConfigureResult is added to the context given to
+// the ConfigureProvider method of the other servers, which retrieve it via
+// SharedConfigureResult. An error is returned during muxed server creation
+// if the primary server does not implement ConfigureResultSharer or, when
+// the WithConfigureOrder option is configured, is not first in the order.
+func WithSharedConfigure(primary int) ServerOption {
+	return func(o *serverOptions) {
+		o.sharedConfigure = true
+		o.sharedConfigurePrimary = primary
+	}
+}
+
+// WithSchemaCacheContentHash enables identifying the schema cache file
+// configured via WithSchemaCacheFile by the content of the schemas of each
+// server, in addition to the version, so any server schema change
+// invalidates the file even if the version does not change. This suits
+// development, where the version may not change between builds.
+//
+// The content of each server is identified by its SchemaFingerprint, if the
+// server implements SchemaFingerprinter, which requires no
+// GetProviderSchema call. Otherwise, the GetProviderSchema response of the
+// server is hashed. When the file matches, muxed server creation still skips
+// assembling, validating, and merging the schemas; when it does not, the
+// responses already retrieved are reused rather than retrieved again.
+func WithSchemaCacheContentHash() ServerOption {
+	return func(o *serverOptions) {
+		o.schemaCacheContentHash = true
+	}
+}
+
+// WithFeatureServerOverlapWarnings enables a warning Diagnostic in the muxed
+// server GetProviderSchema response for each resource or data source type
+// declared by a server implementing FeatureProvider, but routed to another
+// server, such as when the declaration was ignored under the
+// WithConflictResolution option. Such overlaps usually indicate the feature
+// server was expected to handle the type. Feature servers are only consulted
+// for type names which no server declared, so a type declared by any server
+// is always routed to the server owning its schema.
+func WithFeatureServerOverlapWarnings(enabled bool) ServerOption {
+	return func(o *serverOptions) {
+		o.featureServerOverlapWarnings = enabled
+	}
+}
+
+// WithLatencyMetrics enables recording the latency of each downstream server
+// call into a histogram per RPC and resource or data source type name,
+// available via the Metrics method of the muxed server. This provides
+// visibility into slow servers and types without configuring external
+// metrics via WithMiddleware. Recording is safe for concurrent use and does
+// not lock after the first call of each RPC and type name.
+//
+// The buckets are the histogram bucket upper bounds. If none are given, the
+// bounds are 1ms, 5ms, 10ms, 50ms, 100ms, 500ms, 1s, 5s, 10s, and 30s.
+// Latencies are recorded inside any middleware configured via WithMiddleware.
+func WithLatencyMetrics(buckets ...time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.latencyMetrics = true
+		o.latencyBuckets = buckets
+	}
+}
@@ -0,0 +1,179 @@
+package tf5muxserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// namespacedSchemaResponse returns a copy of the GetProviderSchema response
+// with the prefix added to each resource and data source type name, as
+// configured for the server via the WithNamespace option.
+func namespacedSchemaResponse(resp *tfprotov5.GetProviderSchemaResponse, prefix string) *tfprotov5.GetProviderSchemaResponse {
+	result := *resp
+	result.ResourceSchemas = make(map[string]*tfprotov5.Schema, len(resp.ResourceSchemas))
+	result.DataSourceSchemas = make(map[string]*tfprotov5.Schema, len(resp.DataSourceSchemas))
+
+	for typeName, schema := range resp.ResourceSchemas {
+		result.ResourceSchemas[prefix+typeName] = schema
+	}
+
+	for typeName, schema := range resp.DataSourceSchemas {
+		result.DataSourceSchemas[prefix+typeName] = schema
+	}
+
+	return &result
+}
+
+// serverNamespace returns the prefix configured for the server via the
+// WithNamespace option, or an empty string.
+func (s muxServer) serverNamespace(serverIndex int) string {
+	if serverIndex < 0 || serverIndex >= len(s.serverNamespaces) {
+		return ""
+	}
+
+	return s.serverNamespaces[serverIndex]
+}
+
+// resourceNamespace returns the prefix configured via the WithNamespace
+// option for the server implementing the resource type, or an empty string.
+func (s muxServer) resourceNamespace(typeName string) string {
+	if len(s.options.namespaces) == 0 {
+		return ""
+	}
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	serverIndex, ok := s.resourceServerIndex[typeName]
+
+	if !ok {
+		return ""
+	}
+
+	return s.serverNamespace(serverIndex)
+}
+
+// dataSourceNamespace returns the prefix configured via the WithNamespace
+// option for the server implementing the data source type, or an empty
+// string.
+func (s muxServer) dataSourceNamespace(typeName string) string {
+	if len(s.options.namespaces) == 0 {
+		return ""
+	}
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	serverIndex, ok := s.dataSourceServerIndex[typeName]
+
+	if !ok {
+		return ""
+	}
+
+	return s.serverNamespace(serverIndex)
+}
+
+// namespacedCall returns the server call with the prefix removed from the
+// request type name and added to the response imported resource type names,
+// so that middleware observes the muxed server type names while the server
+// receives its own type names. The call is returned unchanged if the prefix
+// is empty.
+func namespacedCall[Req any, Resp any](prefix string, call func(context.Context, *Req) (*Resp, error)) func(context.Context, *Req) (*Resp, error) {
+	if prefix == "" {
+		return call
+	}
+
+	return func(ctx context.Context, req *Req) (*Resp, error) {
+		serverReq, ok := namespacedRequest(req, prefix).(*Req)
+
+		if !ok {
+			serverReq = req
+		}
+
+		resp, err := call(ctx, serverReq)
+
+		if muxResp, ok := namespacedResponse(resp, prefix).(*Resp); ok {
+			resp = muxResp
+		}
+
+		return resp, err
+	}
+}
+
+// namespacedRequest returns a copy of the request with the prefix removed
+// from the type name.
+func namespacedRequest(req interface{}, prefix string) interface{} {
+	switch req := req.(type) {
+	case *tfprotov5.ApplyResourceChangeRequest:
+		result := *req
+		result.TypeName = strings.TrimPrefix(req.TypeName, prefix)
+
+		return &result
+	case *tfprotov5.ImportResourceStateRequest:
+		result := *req
+		result.TypeName = strings.TrimPrefix(req.TypeName, prefix)
+
+		return &result
+	case *tfprotov5.PlanResourceChangeRequest:
+		result := *req
+		result.TypeName = strings.TrimPrefix(req.TypeName, prefix)
+
+		return &result
+	case *tfprotov5.ReadDataSourceRequest:
+		result := *req
+		result.TypeName = strings.TrimPrefix(req.TypeName, prefix)
+
+		return &result
+	case *tfprotov5.ReadResourceRequest:
+		result := *req
+		result.TypeName = strings.TrimPrefix(req.TypeName, prefix)
+
+		return &result
+	case *tfprotov5.UpgradeResourceStateRequest:
+		result := *req
+		result.TypeName = strings.TrimPrefix(req.TypeName, prefix)
+
+		return &result
+	case *tfprotov5.ValidateDataSourceConfigRequest:
+		result := *req
+		result.TypeName = strings.TrimPrefix(req.TypeName, prefix)
+
+		return &result
+	case *tfprotov5.ValidateResourceTypeConfigRequest:
+		result := *req
+		result.TypeName = strings.TrimPrefix(req.TypeName, prefix)
+
+		return &result
+	}
+
+	return req
+}
+
+// namespacedResponse returns a copy of the response with the prefix added to
+// the imported resource type names, if any.
+func namespacedResponse(resp interface{}, prefix string) interface{} {
+	importResp, ok := resp.(*tfprotov5.ImportResourceStateResponse)
+
+	if !ok || importResp == nil || len(importResp.ImportedResources) == 0 {
+		return resp
+	}
+
+	result := *importResp
+	result.ImportedResources = make([]*tfprotov5.ImportedResource, 0, len(importResp.ImportedResources))
+
+	for _, importedResource := range importResp.ImportedResources {
+		if importedResource == nil {
+			result.ImportedResources = append(result.ImportedResources, nil)
+
+			continue
+		}
+
+		namespaced := *importedResource
+		namespaced.TypeName = prefix + importedResource.TypeName
+		result.ImportedResources = append(result.ImportedResources, &namespaced)
+	}
+
+	return &result
+}
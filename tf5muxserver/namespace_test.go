@@ -0,0 +1,126 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithNamespace(t *testing.T) {
+	t.Parallel()
+
+	server1 := multipleImportServer{
+		TestServer: &tf5testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov5.Schema{
+				"test_data_source": {},
+			},
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource": {},
+			},
+		},
+		importedTypeNames: []string{"test_resource"},
+	}
+	server2 := &tf5testserver.TestServer{
+		DataSourceSchemas: map[string]*tfprotov5.Schema{
+			"test_data_source": {},
+		},
+		ResourceSchemas: map[string]*tfprotov5.Schema{
+			"test_resource": {},
+		},
+	}
+
+	options := []tf5muxserver.ServerOption{
+		tf5muxserver.WithNamespace(0, "a_"),
+		tf5muxserver.WithNamespace(1, "b_"),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), options, func() tfprotov5.ProviderServer { return server1 }, server2.ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	schemaResp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+
+	if err != nil {
+		t.Fatalf("unexpected GetProviderSchema error: %s", err)
+	}
+
+	var dataSourceTypes, resourceTypes []string
+
+	for dataSourceType := range schemaResp.DataSourceSchemas {
+		dataSourceTypes = append(dataSourceTypes, dataSourceType)
+	}
+
+	for resourceType := range schemaResp.ResourceSchemas {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+
+	sort.Strings(dataSourceTypes)
+	sort.Strings(resourceTypes)
+
+	if diff := cmp.Diff(dataSourceTypes, []string{"a_test_data_source", "b_test_data_source"}); diff != "" {
+		t.Errorf("unexpected data source types difference: %s", diff)
+	}
+
+	if diff := cmp.Diff(resourceTypes, []string{"a_test_resource", "b_test_resource"}); diff != "" {
+		t.Errorf("unexpected resource types difference: %s", diff)
+	}
+
+	_, err = muxServer.ProviderServer().ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+		TypeName: "b_test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ReadResource error: %s", err)
+	}
+
+	if server1.ReadResourceCalled["test_resource"] {
+		t.Errorf("unexpected test_resource ReadResource called on server1")
+	}
+
+	if !server2.ReadResourceCalled["test_resource"] {
+		t.Errorf("expected test_resource ReadResource to be called on server2")
+	}
+
+	_, err = muxServer.ProviderServer().ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{
+		TypeName: "a_test_data_source",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ReadDataSource error: %s", err)
+	}
+
+	if !server1.ReadDataSourceCalled["test_data_source"] {
+		t.Errorf("expected test_data_source ReadDataSource to be called on server1")
+	}
+
+	if server2.ReadDataSourceCalled["test_data_source"] {
+		t.Errorf("unexpected test_data_source ReadDataSource called on server2")
+	}
+
+	importResp, err := muxServer.ProviderServer().ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{
+		TypeName: "a_test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ImportResourceState error: %s", err)
+	}
+
+	expectedImportResp := &tfprotov5.ImportResourceStateResponse{
+		ImportedResources: []*tfprotov5.ImportedResource{
+			{
+				TypeName: "a_test_resource",
+			},
+		},
+	}
+
+	if diff := cmp.Diff(importResp, expectedImportResp); diff != "" {
+		t.Errorf("unexpected ImportResourceState response difference: %s", diff)
+	}
+}
@@ -0,0 +1,30 @@
+package tf5muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// OrphanedResourceHandler handles ReadResource requests for resource types
+// which are not implemented by any server, such as resources remaining in
+// Terraform state after the server implementing them was removed.
+type OrphanedResourceHandler func(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error)
+
+// RemoveOrphanedResource is an OrphanedResourceHandler which responds with a
+// null new state, which signals Terraform to remove the resource from state.
+func RemoveOrphanedResource(_ context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	// A null value is encoded identically for all types, so the unknown
+	// resource schema type is not required.
+	newState, err := tfprotov5.NewDynamicValue(tftypes.DynamicPseudoType, tftypes.NewValue(tftypes.DynamicPseudoType, nil))
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to create null state for %q: %w", req.TypeName, err)
+	}
+
+	return &tfprotov5.ReadResourceResponse{
+		NewState: &newState,
+	}, nil
+}
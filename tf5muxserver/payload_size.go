@@ -0,0 +1,137 @@
+package tf5muxserver
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// payloadSizeLoggingMiddleware logs, at DEBUG level, the byte sizes of the
+// request and response payloads of each downstream server call which has
+// payloads. Payload contents are not logged.
+func payloadSizeLoggingMiddleware(next RPCHandler) RPCHandler {
+	return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+		typeNameKey, typeName, size, ok := requestPayloadSize(req)
+
+		if !ok {
+			return next(ctx, rpc, req)
+		}
+
+		fields := map[string]interface{}{
+			logging.KeyTfMuxPayloadSize: size,
+		}
+
+		if typeNameKey != "" {
+			fields[typeNameKey] = typeName
+		}
+
+		logging.MuxDebug(ctx, "downstream request payload size", fields)
+
+		resp, err := next(ctx, rpc, req)
+
+		if size, ok := responsePayloadSize(resp); ok {
+			fields[logging.KeyTfMuxPayloadSize] = size
+
+			logging.MuxDebug(ctx, "downstream response payload size", fields)
+		}
+
+		return resp, err
+	}
+}
+
+// requestPayloadSize returns the logging key and value of the request type
+// name, if any, and the total byte size of the request payloads, or false if
+// neither the request nor its response has payloads.
+func requestPayloadSize(req interface{}) (string, string, int, bool) {
+	switch req := req.(type) {
+	case *tfprotov5.ApplyResourceChangeRequest:
+		return logging.KeyTfResourceType, req.TypeName, dynamicValueSize(req.PriorState, req.PlannedState, req.Config, req.ProviderMeta), true
+	case *tfprotov5.ConfigureProviderRequest:
+		return "", "", dynamicValueSize(req.Config), true
+	case *tfprotov5.PlanResourceChangeRequest:
+		return logging.KeyTfResourceType, req.TypeName, dynamicValueSize(req.PriorState, req.ProposedNewState, req.Config, req.ProviderMeta), true
+	case *tfprotov5.PrepareProviderConfigRequest:
+		return "", "", dynamicValueSize(req.Config), true
+	case *tfprotov5.ReadDataSourceRequest:
+		return logging.KeyTfDataSourceType, req.TypeName, dynamicValueSize(req.Config, req.ProviderMeta), true
+	case *tfprotov5.ReadResourceRequest:
+		return logging.KeyTfResourceType, req.TypeName, dynamicValueSize(req.CurrentState, req.ProviderMeta), true
+	case *tfprotov5.UpgradeResourceStateRequest:
+		size := 0
+
+		if req.RawState != nil {
+			size = len(req.RawState.JSON)
+		}
+
+		return logging.KeyTfResourceType, req.TypeName, size, true
+	case *tfprotov5.ValidateDataSourceConfigRequest:
+		return logging.KeyTfDataSourceType, req.TypeName, dynamicValueSize(req.Config), true
+	case *tfprotov5.ValidateResourceTypeConfigRequest:
+		return logging.KeyTfResourceType, req.TypeName, dynamicValueSize(req.Config), true
+	case *tfprotov5.ImportResourceStateRequest:
+		return logging.KeyTfResourceType, req.TypeName, 0, true
+	}
+
+	return "", "", 0, false
+}
+
+// responsePayloadSize returns the total byte size of the response payloads,
+// or false if the response has no payloads.
+func responsePayloadSize(resp interface{}) (int, bool) {
+	switch resp := resp.(type) {
+	case *tfprotov5.ApplyResourceChangeResponse:
+		if resp != nil {
+			return dynamicValueSize(resp.NewState), true
+		}
+	case *tfprotov5.ImportResourceStateResponse:
+		if resp != nil {
+			size := 0
+
+			for _, importedResource := range resp.ImportedResources {
+				if importedResource != nil {
+					size += dynamicValueSize(importedResource.State)
+				}
+			}
+
+			return size, true
+		}
+	case *tfprotov5.PlanResourceChangeResponse:
+		if resp != nil {
+			return dynamicValueSize(resp.PlannedState), true
+		}
+	case *tfprotov5.PrepareProviderConfigResponse:
+		if resp != nil {
+			return dynamicValueSize(resp.PreparedConfig), true
+		}
+	case *tfprotov5.ReadDataSourceResponse:
+		if resp != nil {
+			return dynamicValueSize(resp.State), true
+		}
+	case *tfprotov5.ReadResourceResponse:
+		if resp != nil {
+			return dynamicValueSize(resp.NewState), true
+		}
+	case *tfprotov5.UpgradeResourceStateResponse:
+		if resp != nil {
+			return dynamicValueSize(resp.UpgradedState), true
+		}
+	}
+
+	return 0, false
+}
+
+// dynamicValueSize returns the total byte size of the encoded values.
+func dynamicValueSize(values ...*tfprotov5.DynamicValue) int {
+	size := 0
+
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+
+		size += len(value.MsgPack) + len(value.JSON)
+	}
+
+	return size
+}
@@ -0,0 +1,142 @@
+package tf5muxserver_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklogtest"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// echoReadResourceServer is a server whose ReadResource method returns the
+// current state as the new state.
+type echoReadResourceServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (s echoReadResourceServer) ReadResource(_ context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	return &tfprotov5.ReadResourceResponse{
+		NewState: req.CurrentState,
+	}, nil
+}
+
+func TestWithPayloadSizeLogging(t *testing.T) {
+	t.Parallel()
+
+	stateType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"id": tftypes.String,
+		},
+	}
+
+	state, err := tfprotov5.NewDynamicValue(stateType, tftypes.NewValue(stateType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "test-id"),
+	}))
+
+	if err != nil {
+		t.Fatalf("unable to create state: %s", err)
+	}
+
+	testCases := map[string]struct {
+		enabled         bool
+		expectedEntries []map[string]interface{}
+	}{
+		"disabled": {
+			enabled:         false,
+			expectedEntries: []map[string]interface{}{},
+		},
+		"enabled": {
+			enabled: true,
+			expectedEntries: []map[string]interface{}{
+				{
+					"@level":              "debug",
+					"@message":            "downstream request payload size",
+					"@module":             "sdk.mux",
+					"tf_mux_payload_size": float64(len(state.MsgPack)),
+					"tf_mux_provider":     "tf5muxserver_test.echoReadResourceServer",
+					"tf_resource_type":    "test_resource",
+					"tf_rpc":              "ReadResource",
+				},
+				{
+					"@level":              "debug",
+					"@message":            "downstream response payload size",
+					"@module":             "sdk.mux",
+					"tf_mux_payload_size": float64(len(state.MsgPack)),
+					"tf_mux_provider":     "tf5muxserver_test.echoReadResourceServer",
+					"tf_resource_type":    "test_resource",
+					"tf_rpc":              "ReadResource",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			ctx := tfsdklogtest.RootLogger(context.Background(), &output)
+
+			servers := []func() tfprotov5.ProviderServer{
+				func() tfprotov5.ProviderServer {
+					return echoReadResourceServer{
+						ProviderServer: &tf5testserver.TestServer{
+							ResourceSchemas: map[string]*tfprotov5.Schema{
+								"test_resource": {},
+							},
+						},
+					}
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{tf5muxserver.WithPayloadSizeLogging(testCase.enabled)}, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+				CurrentState: &state,
+				TypeName:     "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			entries, err := tfsdklogtest.MultilineJSONDecode(&output)
+
+			if err != nil {
+				t.Fatalf("unable to read log entries: %s", err)
+			}
+
+			gotEntries := []map[string]interface{}{}
+
+			for _, entry := range entries {
+				if message, ok := entry["@message"].(string); !ok || !strings.HasSuffix(message, "payload size") {
+					continue
+				}
+
+				delete(entry, "@caller")
+				delete(entry, "@timestamp")
+				delete(entry, "tf_mux_request_id")
+				delete(entry, "tf_mux_version")
+
+				gotEntries = append(gotEntries, entry)
+			}
+
+			if diff := cmp.Diff(gotEntries, testCase.expectedEntries); diff != "" {
+				t.Errorf("unexpected log entries: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestMuxServerPing(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options       []tf5muxserver.ServerOption
+		failingServer bool
+		expectedError string
+	}{
+		"failing-server": {
+			failingServer: true,
+			expectedError: "error pinging server 1 (*tf5testserver.TestServer): error retrieving schema for *tf5testserver.TestServer",
+		},
+		"healthy": {},
+		"ping-func": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithPingFunc(func(_ context.Context, server tfprotov5.ProviderServer) error {
+					if len(server.(*tf5testserver.TestServer).ResourceSchemas) == 0 {
+						return errors.New("no resources")
+					}
+
+					return nil
+				}),
+			},
+			expectedError: "error pinging server 1 (*tf5testserver.TestServer): no resources",
+		},
+		"ping-func-failing-server": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithPingFunc(func(_ context.Context, _ tfprotov5.ProviderServer) error {
+					return nil
+				}),
+			},
+			failingServer: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf5testserver.TestServer{
+				{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				},
+				{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source": {},
+					},
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, servers[0].ProviderServer, servers[1].ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if testCase.failingServer {
+				servers[1].GetProviderSchemaDiagnostics = []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "test error summary",
+					},
+				}
+			}
+
+			err = muxServer.Ping(context.Background())
+
+			if err != nil {
+				if testCase.expectedError == "" || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxservertest"
+)
+
+func TestMuxServerProviderMetaSchemaMerge(t *testing.T) {
+	t.Parallel()
+
+	providerMetaType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"module_name":    tftypes.String,
+			"module_version": tftypes.String,
+		},
+	}
+	server1Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"module_name": tftypes.String,
+		},
+	}
+	server2Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"module_version": tftypes.String,
+		},
+	}
+	providerMeta := tftypes.NewValue(providerMetaType, map[string]tftypes.Value{
+		"module_name":    tftypes.NewValue(tftypes.String, "test-module"),
+		"module_version": tftypes.NewValue(tftypes.String, "1.0.0"),
+	})
+
+	testCases := map[string]struct {
+		expectedProviderMeta []tftypes.Value
+	}{
+		"projection": {
+			expectedProviderMeta: []tftypes.Value{
+				tftypes.NewValue(server1Type, map[string]tftypes.Value{
+					"module_name": tftypes.NewValue(tftypes.String, "test-module"),
+				}),
+				tftypes.NewValue(server2Type, map[string]tftypes.Value{
+					"module_version": tftypes.NewValue(tftypes.String, "1.0.0"),
+				}),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			recordingServers := []*tf5muxservertest.RecordingServer{
+				tf5muxservertest.NewRecordingServer((&tf5testserver.TestServer{
+					ProviderMetaSchema: &tfprotov5.Schema{
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:     "module_name",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server1": {},
+					},
+				}).ProviderServer()),
+				tf5muxservertest.NewRecordingServer((&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source_server2": {},
+					},
+					ProviderMetaSchema: &tfprotov5.Schema{
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:     "module_version",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer()),
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+				ctx,
+				[]tf5muxserver.ServerOption{
+					tf5muxserver.WithProviderMetaSchemaMerge(true),
+				},
+				recordingServers[0].ProviderServer,
+				recordingServers[1].ProviderServer,
+			)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			schemaResp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("error calling GetProviderSchema: %s", err)
+			}
+
+			if !schemaResp.ProviderMeta.ValueType().Equal(providerMetaType) {
+				t.Fatalf("expected provider meta schema type %s, got %s", providerMetaType, schemaResp.ProviderMeta.ValueType())
+			}
+
+			providerMetaValue, err := tfprotov5.NewDynamicValue(providerMetaType, providerMeta)
+
+			if err != nil {
+				t.Fatalf("error creating provider meta: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+				ProviderMeta: &providerMetaValue,
+				TypeName:     "test_resource_server1",
+			})
+
+			if err != nil {
+				t.Fatalf("error calling PlanResourceChange: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ReadDataSource(ctx, &tfprotov5.ReadDataSourceRequest{
+				ProviderMeta: &providerMetaValue,
+				TypeName:     "test_data_source_server2",
+			})
+
+			if err != nil {
+				t.Fatalf("error calling ReadDataSource: %s", err)
+			}
+
+			requestProviderMeta := []*tfprotov5.DynamicValue{
+				recordingServers[0].LastPlanResourceChangeRequest().ProviderMeta,
+				recordingServers[1].LastReadDataSourceRequest().ProviderMeta,
+			}
+
+			for idx, expectedProviderMeta := range testCase.expectedProviderMeta {
+				if requestProviderMeta[idx] == nil {
+					t.Fatalf("expected server %d request provider meta", idx)
+				}
+
+				got, err := requestProviderMeta[idx].Unmarshal(expectedProviderMeta.Type())
+
+				if err != nil {
+					t.Fatalf("error unmarshaling server %d provider meta: %s", idx, err)
+				}
+
+				if !got.Equal(expectedProviderMeta) {
+					t.Errorf("expected server %d provider meta %s, got %s", idx, expectedProviderMeta, got)
+				}
+			}
+		})
+	}
+}
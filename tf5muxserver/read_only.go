@@ -0,0 +1,18 @@
+package tf5muxserver
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// readOnlyDiagnostic returns an error Diagnostic describing that the RPC for
+// the type name is not allowed, as the WithReadOnly option is enabled.
+func readOnlyDiagnostic(rpc string, typeName string) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  "Provider in Read-Only Mode",
+		Detail: fmt.Sprintf("The provider is in read-only mode, so the %s operation for %q is not allowed. ", rpc, typeName) +
+			"Only operations which do not modify infrastructure, such as planning and reading, are available.",
+	}
+}
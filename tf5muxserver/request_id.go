@@ -0,0 +1,15 @@
+package tf5muxserver
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// RequestIDFromContext returns the identifier generated by the muxed server
+// for the RPC of the context, or an empty string if there is none. The same
+// identifier is logged as tf_mux_request_id on every log entry mux emits for
+// the RPC, so Middleware can use it to correlate its own logs or metrics.
+func RequestIDFromContext(ctx context.Context) string {
+	return logging.RequestIdFromContext(ctx)
+}
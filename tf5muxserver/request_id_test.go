@@ -0,0 +1,95 @@
+package tf5muxserver_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklogtest"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestRequestIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	ctx := tfsdklogtest.RootLogger(context.Background(), &output)
+
+	if got := tf5muxserver.RequestIDFromContext(ctx); got != "" {
+		t.Errorf("expected no request ID outside of an RPC, got %q", got)
+	}
+
+	var requestIDs []string
+
+	middleware := func(next tf5muxserver.RPCHandler) tf5muxserver.RPCHandler {
+		return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+			requestIDs = append(requestIDs, tf5muxserver.RequestIDFromContext(ctx))
+
+			return next(ctx, rpc, req)
+		}
+	}
+
+	servers := []func() tfprotov5.ProviderServer{
+		(&tf5testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource": {},
+			},
+		}).ProviderServer,
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{tf5muxserver.WithMiddleware(middleware)}, servers...)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+			TypeName: "test_resource",
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if len(requestIDs) != 2 {
+		t.Fatalf("expected 2 middleware calls, got %d", len(requestIDs))
+	}
+
+	if requestIDs[0] == "" || requestIDs[1] == "" {
+		t.Fatalf("expected request IDs, got %q", requestIDs)
+	}
+
+	if requestIDs[0] == requestIDs[1] {
+		t.Errorf("expected unique request IDs, got %q", requestIDs)
+	}
+
+	entries, err := tfsdklogtest.MultilineJSONDecode(&output)
+
+	if err != nil {
+		t.Fatalf("unable to read log entries: %s", err)
+	}
+
+	gotRequestIDs := []string{}
+
+	for _, entry := range entries {
+		if entry["tf_rpc"] != "ReadResource" {
+			continue
+		}
+
+		requestID, _ := entry["tf_mux_request_id"].(string)
+
+		if len(gotRequestIDs) == 0 || gotRequestIDs[len(gotRequestIDs)-1] != requestID {
+			gotRequestIDs = append(gotRequestIDs, requestID)
+		}
+	}
+
+	if diff := cmp.Diff(gotRequestIDs, requestIDs); diff != "" {
+		t.Errorf("unexpected logged request IDs difference: %s", diff)
+	}
+}
@@ -0,0 +1,58 @@
+package tf5muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// typeRouter returns the server handling requests for the type name, and
+// whether any server handles it, such as muxServer.resourceServer.
+type typeRouter func(ctx context.Context, typeName string, req interface{}) (tfprotov5.ProviderServer, bool, error)
+
+// unsupportedTypeError is returned by routeRequest when no server handles the
+// requested type name.
+type unsupportedTypeError struct {
+	typeName string
+}
+
+// Error returns a description of the error.
+func (e *unsupportedTypeError) Error() string {
+	return fmt.Sprintf("%q isn't supported by any servers", e.typeName)
+}
+
+// checkRequest returns an error if the request of the RPC is nil or its type
+// name, as returned by the typeName function, is empty, so every RPC routed
+// by type name rejects such requests consistently.
+func checkRequest[Req any](rpc string, req *Req, typeName func(*Req) string) error {
+	if req == nil {
+		return fmt.Errorf("unable to route %s: request is nil", rpc)
+	}
+
+	if typeName(req) == "" {
+		return fmt.Errorf("unable to route %s: request missing TypeName", rpc)
+	}
+
+	return nil
+}
+
+// routeRequest returns the server handling the request of the RPC for the
+// type name, as returned by the router, and records the routing result. An
+// *unsupportedTypeError is returned if no server handles the type name.
+func (s muxServer) routeRequest(ctx context.Context, rpc string, typeName string, req interface{}, router typeRouter) (tfprotov5.ProviderServer, error) {
+	server, ok, err := router(ctx, typeName, req)
+	s.routingStats.record(rpc, ok)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, &unsupportedTypeError{
+			typeName: typeName,
+		}
+	}
+
+	return server, nil
+}
@@ -0,0 +1,42 @@
+package tf5muxserver
+
+import (
+	"context"
+	"fmt"
+)
+
+// rpcTimeoutContext returns a context with the deadline configured for the
+// RPC via WithRPCTimeout, extended to the timeout hinted for the type name via
+// WithTimeoutHints if that is longer. If no timeout is configured for the
+// RPC, the context is returned unchanged, even if a timeout is hinted.
+func (s muxServer) rpcTimeoutContext(ctx context.Context, rpc string, typeName string) (context.Context, context.CancelFunc) {
+	timeout, ok := s.options.rpcTimeouts[rpc]
+
+	if !ok {
+		return ctx, func() {}
+	}
+
+	s.routingMu.RLock()
+	hint, hinted := s.typeTimeouts[typeName]
+	s.routingMu.RUnlock()
+
+	if hinted && hint > timeout {
+		timeout = hint
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// rpcTimeoutError returns an error naming the RPC and type name if the
+// context deadline was exceeded, otherwise the given error.
+func rpcTimeoutError(ctx context.Context, rpc string, typeName string, err error) error {
+	if ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return fmt.Errorf("%s for %q timed out: %w", rpc, typeName, err)
+}
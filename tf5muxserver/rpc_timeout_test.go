@@ -0,0 +1,94 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// deadlineServer is a server whose ApplyResourceChange method records
+// whether the context has a deadline and whose ReadResource method blocks
+// until the context is done.
+type deadlineServer struct {
+	tfprotov5.ProviderServer
+
+	applyResourceChangeDeadline bool
+	readResourceCanceled        bool
+}
+
+func (s *deadlineServer) ApplyResourceChange(ctx context.Context, _ *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	_, s.applyResourceChangeDeadline = ctx.Deadline()
+
+	return &tfprotov5.ApplyResourceChangeResponse{}, nil
+}
+
+func (s *deadlineServer) ReadResource(ctx context.Context, _ *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	<-ctx.Done()
+
+	s.readResourceCanceled = true
+
+	return nil, ctx.Err()
+}
+
+func TestWithRPCTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := &deadlineServer{
+		ProviderServer: &tf5testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource": {},
+			},
+		},
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+		ctx,
+		[]tf5muxserver.ServerOption{tf5muxserver.WithRPCTimeout("ReadResource", 10*time.Millisecond)},
+		func() tfprotov5.ProviderServer { return server },
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName: "test_resource",
+	})
+
+	if err == nil {
+		t.Fatalf("expected ReadResource timeout error")
+	}
+
+	expectedError := `ReadResource for "test_resource" timed out`
+
+	if !strings.Contains(err.Error(), expectedError) {
+		t.Errorf("expected error %q, got: %s", expectedError, err)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded error, got: %s", err)
+	}
+
+	if !server.readResourceCanceled {
+		t.Errorf("expected ReadResource context cancellation to propagate to server")
+	}
+
+	_, err = muxServer.ProviderServer().ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName: "test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if server.applyResourceChangeDeadline {
+		t.Errorf("unexpected ApplyResourceChange deadline without configured timeout")
+	}
+}
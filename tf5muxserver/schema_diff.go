@@ -0,0 +1,216 @@
+package tf5muxserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// SchemaDiffSide identifies which of the compared servers declared a schema.
+type SchemaDiffSide string
+
+const (
+	// SchemaDiffSideA is a schema declared only by the first server.
+	SchemaDiffSideA SchemaDiffSide = "a"
+
+	// SchemaDiffSideB is a schema declared only by the second server.
+	SchemaDiffSideB SchemaDiffSide = "b"
+
+	// SchemaDiffSideBoth is a schema declared by both servers, which
+	// conflicts when the servers are muxed.
+	SchemaDiffSideBoth SchemaDiffSide = "both"
+)
+
+// SchemaDiffReport is the comparison of the schemas of two servers, returned
+// by SchemaDiff. Type names and attributes are sorted by name.
+type SchemaDiffReport struct {
+	// DataSources are the data source types declared by either server.
+	DataSources []SchemaDiffType
+
+	// Resources are the managed resource types declared by either server.
+	Resources []SchemaDiffType
+
+	// ProviderAttributes are the provider schema attributes which are
+	// declared by only one server or differ between the servers. Attributes
+	// which are identical are omitted.
+	ProviderAttributes []SchemaDiffAttribute
+}
+
+// SchemaDiffType is a resource or data source type name and the servers
+// which declared it.
+type SchemaDiffType struct {
+	// TypeName is the resource or data source type name.
+	TypeName string
+
+	// DeclaredBy is the server, or both servers, which declared the type.
+	DeclaredBy SchemaDiffSide
+}
+
+// SchemaDiffAttribute is a provider schema attribute which differs between
+// servers.
+type SchemaDiffAttribute struct {
+	// Name is the attribute name.
+	Name string
+
+	// A is the attribute as declared by the first server, or nil.
+	A *tfprotov5.SchemaAttribute
+
+	// B is the attribute as declared by the second server, or nil.
+	B *tfprotov5.SchemaAttribute
+
+	// Diff is a human-readable difference between A and B.
+	Diff string
+}
+
+// OverlappingDataSources returns the sorted data source type names declared
+// by both servers.
+func (r *SchemaDiffReport) OverlappingDataSources() []string {
+	return overlappingSchemaDiffTypes(r.DataSources)
+}
+
+// OverlappingResources returns the sorted managed resource type names
+// declared by both servers.
+func (r *SchemaDiffReport) OverlappingResources() []string {
+	return overlappingSchemaDiffTypes(r.Resources)
+}
+
+// SchemaDiff retrieves the schemas of two servers and compares them, such as
+// to detect drift during development between two servers which are intended
+// to be muxed together. Unlike NewMuxServer, overlapping type names and
+// differing provider schemas are reported rather than returned as errors. An
+// error is returned if either server fails to return its schema.
+func SchemaDiff(ctx context.Context, a, b func() tfprotov5.ProviderServer) (*SchemaDiffReport, error) {
+	ctx = logging.InitContext(ctx)
+
+	serverA := a()
+	respA, err := getServerSchema(logging.Tfprotov5ProviderServerContext(ctx, serverA), serverA)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve schema of server a: %w", err)
+	}
+
+	serverB := b()
+	respB, err := getServerSchema(logging.Tfprotov5ProviderServerContext(ctx, serverB), serverB)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve schema of server b: %w", err)
+	}
+
+	report := &SchemaDiffReport{
+		DataSources:        diffSchemaTypes(respA.DataSourceSchemas, respB.DataSourceSchemas),
+		Resources:          diffSchemaTypes(respA.ResourceSchemas, respB.ResourceSchemas),
+		ProviderAttributes: diffSchemaAttributes(respA.Provider, respB.Provider),
+	}
+
+	return report, nil
+}
+
+func diffSchemaTypes(a, b map[string]*tfprotov5.Schema) []SchemaDiffType {
+	var result []SchemaDiffType
+
+	for typeName := range a {
+		side := SchemaDiffSideA
+
+		if _, ok := b[typeName]; ok {
+			side = SchemaDiffSideBoth
+		}
+
+		result = append(result, SchemaDiffType{
+			TypeName:   typeName,
+			DeclaredBy: side,
+		})
+	}
+
+	for typeName := range b {
+		if _, ok := a[typeName]; ok {
+			continue
+		}
+
+		result = append(result, SchemaDiffType{
+			TypeName:   typeName,
+			DeclaredBy: SchemaDiffSideB,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TypeName < result[j].TypeName
+	})
+
+	return result
+}
+
+func diffSchemaAttributes(a, b *tfprotov5.Schema) []SchemaDiffAttribute {
+	var result []SchemaDiffAttribute
+
+	aAttributes := schemaAttributesByName(a)
+	bAttributes := schemaAttributesByName(b)
+
+	for name, aAttribute := range aAttributes {
+		bAttribute := bAttributes[name]
+
+		if cmp.Equal(aAttribute, bAttribute) {
+			continue
+		}
+
+		result = append(result, SchemaDiffAttribute{
+			Name: name,
+			A:    aAttribute,
+			B:    bAttribute,
+			Diff: cmp.Diff(aAttribute, bAttribute),
+		})
+	}
+
+	for name, bAttribute := range bAttributes {
+		if _, ok := aAttributes[name]; ok {
+			continue
+		}
+
+		result = append(result, SchemaDiffAttribute{
+			Name: name,
+			B:    bAttribute,
+			Diff: cmp.Diff((*tfprotov5.SchemaAttribute)(nil), bAttribute),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func overlappingSchemaDiffTypes(types []SchemaDiffType) []string {
+	var result []string
+
+	for _, schemaDiffType := range types {
+		if schemaDiffType.DeclaredBy != SchemaDiffSideBoth {
+			continue
+		}
+
+		result = append(result, schemaDiffType.TypeName)
+	}
+
+	return result
+}
+
+func schemaAttributesByName(schema *tfprotov5.Schema) map[string]*tfprotov5.SchemaAttribute {
+	result := make(map[string]*tfprotov5.SchemaAttribute)
+
+	if schema == nil || schema.Block == nil {
+		return result
+	}
+
+	for _, attribute := range schema.Block.Attributes {
+		if attribute == nil {
+			continue
+		}
+
+		result[attribute.Name] = attribute
+	}
+
+	return result
+}
@@ -0,0 +1,242 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a                              func() tfprotov5.ProviderServer
+		b                              func() tfprotov5.ProviderServer
+		expectedReport                 *tf5muxserver.SchemaDiffReport
+		expectedOverlappingDataSources []string
+		expectedOverlappingResources   []string
+		expectedError                  bool
+	}{
+		"differences": {
+			a: (&tf5testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov5.Schema{
+					"test_data_source_a": {},
+					"test_data_source":   {},
+				},
+				ProviderSchema: &tfprotov5.Schema{
+					Block: &tfprotov5.SchemaBlock{
+						Attributes: []*tfprotov5.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+							{
+								Name:     "region",
+								Type:     tftypes.String,
+								Optional: true,
+							},
+							{
+								Name:     "token",
+								Type:     tftypes.String,
+								Optional: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource_a": {},
+					"test_resource":   {},
+				},
+			}).ProviderServer,
+			b: (&tf5testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov5.Schema{
+					"test_data_source": {},
+				},
+				ProviderSchema: &tfprotov5.Schema{
+					Block: &tfprotov5.SchemaBlock{
+						Attributes: []*tfprotov5.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+							{
+								Name:     "endpoint",
+								Type:     tftypes.String,
+								Optional: true,
+							},
+							{
+								Name:     "region",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource_b": {},
+				},
+			}).ProviderServer,
+			expectedReport: &tf5muxserver.SchemaDiffReport{
+				DataSources: []tf5muxserver.SchemaDiffType{
+					{
+						TypeName:   "test_data_source",
+						DeclaredBy: tf5muxserver.SchemaDiffSideBoth,
+					},
+					{
+						TypeName:   "test_data_source_a",
+						DeclaredBy: tf5muxserver.SchemaDiffSideA,
+					},
+				},
+				Resources: []tf5muxserver.SchemaDiffType{
+					{
+						TypeName:   "test_resource",
+						DeclaredBy: tf5muxserver.SchemaDiffSideA,
+					},
+					{
+						TypeName:   "test_resource_a",
+						DeclaredBy: tf5muxserver.SchemaDiffSideA,
+					},
+					{
+						TypeName:   "test_resource_b",
+						DeclaredBy: tf5muxserver.SchemaDiffSideB,
+					},
+				},
+				ProviderAttributes: []tf5muxserver.SchemaDiffAttribute{
+					{
+						Name: "endpoint",
+						B: &tfprotov5.SchemaAttribute{
+							Name:     "endpoint",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+					{
+						Name: "region",
+						A: &tfprotov5.SchemaAttribute{
+							Name:     "region",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+						B: &tfprotov5.SchemaAttribute{
+							Name:     "region",
+							Type:     tftypes.String,
+							Required: true,
+						},
+					},
+					{
+						Name: "token",
+						A: &tfprotov5.SchemaAttribute{
+							Name:     "token",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+			expectedOverlappingDataSources: []string{"test_data_source"},
+		},
+		"error": {
+			a: (&tf5testserver.TestServer{}).ProviderServer,
+			b: (&tf5testserver.TestServer{
+				GetProviderSchemaDiagnostics: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "test error summary",
+					},
+				},
+			}).ProviderServer,
+			expectedError: true,
+		},
+		"identical": {
+			a: (&tf5testserver.TestServer{
+				ProviderSchema: &tfprotov5.Schema{
+					Block: &tfprotov5.SchemaBlock{
+						Attributes: []*tfprotov5.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource": {},
+				},
+			}).ProviderServer,
+			b: (&tf5testserver.TestServer{
+				ProviderSchema: &tfprotov5.Schema{
+					Block: &tfprotov5.SchemaBlock{
+						Attributes: []*tfprotov5.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov5.Schema{
+					"test_resource": {},
+				},
+			}).ProviderServer,
+			expectedReport: &tf5muxserver.SchemaDiffReport{
+				Resources: []tf5muxserver.SchemaDiffType{
+					{
+						TypeName:   "test_resource",
+						DeclaredBy: tf5muxserver.SchemaDiffSideBoth,
+					},
+				},
+			},
+			expectedOverlappingResources: []string{"test_resource"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := tf5muxserver.SchemaDiff(context.Background(), testCase.a, testCase.b)
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			if diff := cmp.Diff(report, testCase.expectedReport, cmpopts.IgnoreFields(tf5muxserver.SchemaDiffAttribute{}, "Diff")); diff != "" {
+				t.Errorf("unexpected report difference: %s", diff)
+			}
+
+			for _, attribute := range report.ProviderAttributes {
+				if attribute.Diff == "" {
+					t.Errorf("expected provider attribute %q Diff", attribute.Name)
+				}
+			}
+
+			if diff := cmp.Diff(report.OverlappingDataSources(), testCase.expectedOverlappingDataSources); diff != "" {
+				t.Errorf("unexpected overlapping data sources difference: %s", diff)
+			}
+
+			if diff := cmp.Diff(report.OverlappingResources(), testCase.expectedOverlappingResources); diff != "" {
+				t.Errorf("unexpected overlapping resources difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package tf5muxserver_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklogtest"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithStartupSchemaDump(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled         bool
+		expectedEntries []map[string]interface{}
+	}{
+		"disabled": {
+			enabled:         false,
+			expectedEntries: []map[string]interface{}{},
+		},
+		"enabled": {
+			enabled: true,
+			expectedEntries: []map[string]interface{}{
+				{
+					"@level":                   "debug",
+					"@message":                 "server schema contributions",
+					"@module":                  "sdk.mux",
+					"tf_mux_data_source_types": []interface{}{"test_data_source"},
+					"tf_mux_provider":          "*tf5testserver.TestServer",
+					"tf_mux_resource_types":    []interface{}{"test_resource_a", "test_resource_b"},
+					"tf_mux_server_index":      float64(0),
+				},
+				{
+					"@level":                   "debug",
+					"@message":                 "server schema contributions",
+					"@module":                  "sdk.mux",
+					"tf_mux_data_source_types": []interface{}{},
+					"tf_mux_provider":          "*tf5testserver.TestServer",
+					"tf_mux_resource_types":    []interface{}{"test_resource_a"},
+					"tf_mux_server_index":      float64(1),
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			ctx := tfsdklogtest.RootLogger(context.Background(), &output)
+
+			servers := []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source": {},
+					},
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_b": {},
+						"test_resource_a": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_a": {},
+					},
+				}).ProviderServer,
+			}
+
+			_, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{tf5muxserver.WithStartupSchemaDump(testCase.enabled)}, servers...)
+
+			if err == nil {
+				t.Fatalf("expected duplicate resource error")
+			}
+
+			entries, err := tfsdklogtest.MultilineJSONDecode(&output)
+
+			if err != nil {
+				t.Fatalf("unable to read log entries: %s", err)
+			}
+
+			gotEntries := []map[string]interface{}{}
+
+			for _, entry := range entries {
+				if entry["@message"] != "server schema contributions" {
+					continue
+				}
+
+				delete(entry, "@caller")
+				delete(entry, "@timestamp")
+				delete(entry, "tf_mux_version")
+
+				gotEntries = append(gotEntries, entry)
+			}
+
+			if diff := cmp.Diff(gotEntries, testCase.expectedEntries); diff != "" {
+				t.Errorf("unexpected log entries: %s", diff)
+			}
+		})
+	}
+}
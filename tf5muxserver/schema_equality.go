@@ -1,31 +1,98 @@
 package tf5muxserver
 
 import (
+	"sort"
+
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 )
 
-// schemaCmpOptions ensures comparisons of SchemaAttribute and
-// SchemaNestedBlock slices are considered equal despite ordering differences.
-var schemaCmpOptions = []cmp.Option{
-	cmpopts.SortSlices(func(i, j *tfprotov5.SchemaAttribute) bool {
-		return i.Name < j.Name
-	}),
-	cmpopts.SortSlices(func(i, j *tfprotov5.SchemaNestedBlock) bool {
-		return i.TypeName < j.TypeName
-	}),
-}
-
 // schemaDiff outputs the difference between schemas while accounting for
-// inconsequential ordering differences in SchemaAttribute and
-// SchemaNestedBlock slices.
+// inconsequential differences, as described by normalizeSchema.
 func schemaDiff(i, j *tfprotov5.Schema) string {
-	return cmp.Diff(i, j, schemaCmpOptions...)
+	return cmp.Diff(normalizeSchema(i), normalizeSchema(j))
 }
 
-// schemaEquals asserts equality between schemas by normalizing inconsequential
-// ordering differences in SchemaAttribute and SchemaNestedBlock slices.
+// schemaEquals asserts equality between schemas after normalizing
+// inconsequential differences, as described by normalizeSchema.
 func schemaEquals(i, j *tfprotov5.Schema) bool {
-	return cmp.Equal(i, j, schemaCmpOptions...)
+	return cmp.Equal(normalizeSchema(i), normalizeSchema(j))
+}
+
+// normalizeSchema returns a copy of the schema with inconsequential
+// differences removed, so that cosmetically different but equivalent schemas
+// compare equal. The given schema is not modified. The normalization rules
+// are:
+//
+//   - A nil schema block is equivalent to an empty schema block, including
+//     within nested blocks.
+//   - Attributes are sorted by name and nested blocks are sorted by type
+//     name.
+//   - Nil and empty attribute and nested block slices are equivalent.
+//   - Nil attributes and nested blocks are removed.
+//
+// Unset optional fields, such as DescriptionKind, are already their zero
+// values and are compared as-is.
+func normalizeSchema(schema *tfprotov5.Schema) *tfprotov5.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	return &tfprotov5.Schema{
+		Version: schema.Version,
+		Block:   normalizeSchemaBlock(schema.Block),
+	}
+}
+
+func normalizeSchemaBlock(block *tfprotov5.SchemaBlock) *tfprotov5.SchemaBlock {
+	if block == nil {
+		return &tfprotov5.SchemaBlock{}
+	}
+
+	result := &tfprotov5.SchemaBlock{
+		Version:         block.Version,
+		Description:     block.Description,
+		DescriptionKind: block.DescriptionKind,
+		Deprecated:      block.Deprecated,
+	}
+
+	for _, attribute := range block.Attributes {
+		if attribute == nil {
+			continue
+		}
+
+		result.Attributes = append(result.Attributes, attribute)
+	}
+
+	sort.Slice(result.Attributes, func(i, j int) bool {
+		return result.Attributes[i].Name < result.Attributes[j].Name
+	})
+
+	result.BlockTypes = normalizeSchemaNestedBlocks(block.BlockTypes)
+
+	return result
+}
+
+func normalizeSchemaNestedBlocks(nestedBlocks []*tfprotov5.SchemaNestedBlock) []*tfprotov5.SchemaNestedBlock {
+	var result []*tfprotov5.SchemaNestedBlock
+
+	for _, nestedBlock := range nestedBlocks {
+		if nestedBlock == nil {
+			continue
+		}
+
+		result = append(result, &tfprotov5.SchemaNestedBlock{
+			TypeName: nestedBlock.TypeName,
+			Block:    normalizeSchemaBlock(nestedBlock.Block),
+			Nesting:  nestedBlock.Nesting,
+			MinItems: nestedBlock.MinItems,
+			MaxItems: nestedBlock.MaxItems,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TypeName < result[j].TypeName
+	})
+
+	return result
 }
@@ -0,0 +1,143 @@
+package tf5muxserver
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// mergeProviderSchemas returns a new provider or provider meta schema
+// containing the attributes and nested blocks of both schemas. The schema and
+// block versions must match, each attribute may only be defined in one of the
+// schemas, and nested blocks defined in both schemas must be identical.
+// Neither schema is modified. The kind, such as "provider schema", prefixes
+// error messages.
+func mergeProviderSchemas(kind string, i, j *tfprotov5.Schema) (*tfprotov5.Schema, error) {
+	if i.Version != j.Version {
+		return nil, fmt.Errorf("%s version %d does not match version %d from other servers", kind, j.Version, i.Version)
+	}
+
+	iBlock := i.Block
+	jBlock := j.Block
+
+	if iBlock == nil {
+		iBlock = &tfprotov5.SchemaBlock{}
+	}
+
+	if jBlock == nil {
+		jBlock = &tfprotov5.SchemaBlock{}
+	}
+
+	if iBlock.Version != jBlock.Version {
+		return nil, fmt.Errorf("%s block version %d does not match block version %d from other servers", kind, jBlock.Version, iBlock.Version)
+	}
+
+	attributes := make([]*tfprotov5.SchemaAttribute, 0, len(iBlock.Attributes)+len(jBlock.Attributes))
+	attributeNames := make(map[string]struct{}, len(iBlock.Attributes)+len(jBlock.Attributes))
+
+	for _, attribute := range iBlock.Attributes {
+		attributes = append(attributes, attribute)
+		attributeNames[attribute.Name] = struct{}{}
+	}
+
+	for _, attribute := range jBlock.Attributes {
+		if _, ok := attributeNames[attribute.Name]; ok {
+			return nil, fmt.Errorf("%s attribute %q is defined by multiple servers; only one definition allowed", kind, attribute.Name)
+		}
+
+		attributes = append(attributes, attribute)
+		attributeNames[attribute.Name] = struct{}{}
+	}
+
+	blockTypes, err := mergeProviderSchemaNestedBlocks(kind, iBlock.BlockTypes, jBlock.BlockTypes, attributeNames)
+
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &tfprotov5.Schema{
+		Version: i.Version,
+		Block: &tfprotov5.SchemaBlock{
+			Version:         iBlock.Version,
+			Attributes:      attributes,
+			BlockTypes:      blockTypes,
+			Description:     iBlock.Description,
+			DescriptionKind: iBlock.DescriptionKind,
+			Deprecated:      iBlock.Deprecated || jBlock.Deprecated,
+		},
+	}
+
+	if merged.Block.Description == "" {
+		merged.Block.Description = jBlock.Description
+		merged.Block.DescriptionKind = jBlock.DescriptionKind
+	}
+
+	return merged, nil
+}
+
+// mergeProviderSchemaNestedBlocks returns the union of the nested blocks,
+// with the nested blocks of i first. Nested blocks with the same type name
+// must be identical, including their nesting mode and item limits, and type
+// names must not match any of the attribute names. The kind prefixes error
+// messages.
+func mergeProviderSchemaNestedBlocks(kind string, i, j []*tfprotov5.SchemaNestedBlock, attributeNames map[string]struct{}) ([]*tfprotov5.SchemaNestedBlock, error) {
+	result := make([]*tfprotov5.SchemaNestedBlock, 0, len(i)+len(j))
+	nestedBlocks := make(map[string]*tfprotov5.SchemaNestedBlock, len(i)+len(j))
+
+	for _, nestedBlock := range i {
+		if nestedBlock == nil {
+			continue
+		}
+
+		result = append(result, nestedBlock)
+		nestedBlocks[nestedBlock.TypeName] = nestedBlock
+	}
+
+	for _, nestedBlock := range j {
+		if nestedBlock == nil {
+			continue
+		}
+
+		existing, ok := nestedBlocks[nestedBlock.TypeName]
+
+		if !ok {
+			result = append(result, nestedBlock)
+			nestedBlocks[nestedBlock.TypeName] = nestedBlock
+			continue
+		}
+
+		if err := providerSchemaNestedBlockConflict(kind, existing, nestedBlock); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, nestedBlock := range result {
+		if _, ok := attributeNames[nestedBlock.TypeName]; ok {
+			return nil, fmt.Errorf("%s nested block %q has the same name as an attribute from other servers", kind, nestedBlock.TypeName)
+		}
+	}
+
+	return result, nil
+}
+
+// providerSchemaNestedBlockConflict returns an error describing the
+// difference between two nested blocks with the same type name, or nil if
+// they are identical. The kind prefixes error messages.
+func providerSchemaNestedBlockConflict(kind string, i, j *tfprotov5.SchemaNestedBlock) error {
+	switch {
+	case i.Nesting != j.Nesting:
+		return fmt.Errorf("%s nested block %q nesting mode %s does not match nesting mode %s from other servers", kind, j.TypeName, j.Nesting, i.Nesting)
+	case i.MinItems != j.MinItems || i.MaxItems != j.MaxItems:
+		return fmt.Errorf("%s nested block %q items limits (min %d, max %d) do not match items limits (min %d, max %d) from other servers", kind, j.TypeName, j.MinItems, j.MaxItems, i.MinItems, i.MaxItems)
+	}
+
+	iNormalized := normalizeSchemaNestedBlocks([]*tfprotov5.SchemaNestedBlock{i})
+	jNormalized := normalizeSchemaNestedBlocks([]*tfprotov5.SchemaNestedBlock{j})
+
+	if !cmp.Equal(jNormalized, iNormalized) {
+		return fmt.Errorf("%s nested block %q is defined differently by multiple servers; nested blocks defined by multiple servers must be identical. Diff: %s", kind, j.TypeName, cmp.Diff(jNormalized, iNormalized))
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package tf5muxserver
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// schemaSizeFieldOverhead approximates the bytes of the protocol buffers
+// field tag and length prefix of each encoded field.
+const schemaSizeFieldOverhead = 3
+
+// checkSchemaSize returns an error if the estimated size of the muxed server
+// GetProviderSchema response exceeds the maximum configured via the
+// WithMaxSchemaSize option.
+func (s muxServer) checkSchemaSize() error {
+	size, err := s.schemaSize()
+
+	if err != nil {
+		return fmt.Errorf("unable to estimate schema size: %w", err)
+	}
+
+	if size > s.options.maxSchemaSize {
+		return fmt.Errorf("the muxed provider schema is approximately %d bytes, which exceeds the maximum of %d bytes. "+
+			"Terraform would be unable to receive the GetProviderSchema response. "+
+			"Reduce the size of the schemas, such as by shortening descriptions, or increase the maximum message size", size, s.options.maxSchemaSize)
+	}
+
+	return nil
+}
+
+// schemaSize returns the estimated size, in bytes, of the protocol buffers
+// encoding of the muxed server GetProviderSchema response schemas.
+func (s muxServer) schemaSize() (int, error) {
+	size := 0
+
+	for _, schema := range []*tfprotov5.Schema{s.providerSchema, s.providerMetaSchema} {
+		schemaSize, err := estimateSchemaSize(schema)
+
+		if err != nil {
+			return 0, err
+		}
+
+		size += schemaSize
+	}
+
+	for _, schemas := range []map[string]*tfprotov5.Schema{s.dataSourceSchemas, s.resourceSchemas} {
+		for typeName, schema := range schemas {
+			schemaSize, err := estimateSchemaSize(schema)
+
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", typeName, err)
+			}
+
+			size += len(typeName) + schemaSize + 2*schemaSizeFieldOverhead
+		}
+	}
+
+	return size, nil
+}
+
+func estimateSchemaSize(schema *tfprotov5.Schema) (int, error) {
+	if schema == nil {
+		return 0, nil
+	}
+
+	blockSize, err := estimateSchemaBlockSize(schema.Block)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return schemaSizeFieldOverhead + blockSize + schemaSizeFieldOverhead, nil
+}
+
+func estimateSchemaBlockSize(block *tfprotov5.SchemaBlock) (int, error) {
+	if block == nil {
+		return 0, nil
+	}
+
+	size := schemaSizeFieldOverhead + len(block.Description) + 2*schemaSizeFieldOverhead
+
+	for _, attribute := range block.Attributes {
+		if attribute == nil {
+			continue
+		}
+
+		attributeSize, err := estimateSchemaAttributeSize(attribute)
+
+		if err != nil {
+			return 0, fmt.Errorf("attribute %q: %w", attribute.Name, err)
+		}
+
+		size += attributeSize
+	}
+
+	for _, nestedBlock := range block.BlockTypes {
+		if nestedBlock == nil {
+			continue
+		}
+
+		nestedBlockSize, err := estimateSchemaBlockSize(nestedBlock.Block)
+
+		if err != nil {
+			return 0, fmt.Errorf("block %q: %w", nestedBlock.TypeName, err)
+		}
+
+		size += len(nestedBlock.TypeName) + nestedBlockSize + 4*schemaSizeFieldOverhead
+	}
+
+	return size, nil
+}
+
+func estimateSchemaAttributeSize(attribute *tfprotov5.SchemaAttribute) (int, error) {
+	size := len(attribute.Name) + len(attribute.Description) + 8*schemaSizeFieldOverhead
+
+	if attribute.Type != nil {
+		typeJSON, err := attribute.Type.MarshalJSON()
+
+		if err != nil {
+			return 0, fmt.Errorf("unable to marshal type: %w", err)
+		}
+
+		size += len(typeJSON)
+	}
+
+	return size, nil
+}
@@ -0,0 +1,90 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithMaxSchemaSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		maxSchemaSize int
+		expectedError string
+	}{
+		"disabled": {
+			maxSchemaSize: 0,
+		},
+		"exceeded": {
+			maxSchemaSize: 10000,
+			expectedError: "exceeds the maximum of 10000 bytes",
+		},
+		"not-exceeded": {
+			maxSchemaSize: 1000000,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {
+							Block: &tfprotov5.SchemaBlock{
+								Attributes: []*tfprotov5.SchemaAttribute{
+									{
+										Name:        "test_attribute",
+										Type:        tftypes.String,
+										Description: strings.Repeat("a", 6000),
+										Optional:    true,
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source": {
+							Block: &tfprotov5.SchemaBlock{
+								Description: strings.Repeat("b", 6000),
+							},
+						},
+					},
+				}).ProviderServer,
+			}
+
+			_, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), []tf5muxserver.ServerOption{tf5muxserver.WithMaxSchemaSize(testCase.maxSchemaSize)}, servers...)
+
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error to contain %q, got %q", testCase.expectedError, err)
+				}
+
+				if !strings.Contains(err.Error(), "the muxed provider schema is approximately 12") {
+					t.Errorf("expected error to contain the schema size, got %q", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error containing %q", testCase.expectedError)
+			}
+		})
+	}
+}
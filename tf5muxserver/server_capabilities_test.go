@@ -0,0 +1,122 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestMuxServerServerCapabilities(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		servers                    []func() tfprotov5.ProviderServer
+		expectedServerCapabilities *tfprotov5.ServerCapabilities
+	}{
+		"all-plan-destroy": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov5.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server2": {},
+					},
+					ServerCapabilities: &tfprotov5.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov5.ServerCapabilities{
+				PlanDestroy: true,
+			},
+		},
+		"data-source-only-server-without-plan-destroy": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov5.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source_server2": {},
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov5.ServerCapabilities{
+				PlanDestroy: true,
+			},
+		},
+		"mixed-plan-destroy": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov5.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_server2": {},
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov5.ServerCapabilities{},
+		},
+		"no-resources": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source_server1": {},
+					},
+					ServerCapabilities: &tfprotov5.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov5.ServerCapabilities{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := tf5muxserver.NewMuxServer(context.Background(), testCase.servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := muxServer.ServerCapabilities()
+
+			if diff := cmp.Diff(got, testCase.expectedServerCapabilities); diff != "" {
+				t.Errorf("unexpected server capabilities difference: %s", diff)
+			}
+
+			// The returned value is a copy.
+			got.PlanDestroy = !got.PlanDestroy
+
+			if diff := cmp.Diff(muxServer.ServerCapabilities(), testCase.expectedServerCapabilities); diff != "" {
+				t.Errorf("unexpected server capabilities difference after modification: %s", diff)
+			}
+		})
+	}
+}
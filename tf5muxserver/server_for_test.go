@@ -0,0 +1,96 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestMuxServerServerFor(t *testing.T) {
+	t.Parallel()
+
+	muxServer, err := tf5muxserver.NewMuxServer(
+		context.Background(),
+		(&tf5testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov5.Schema{
+				"test_data_source_server1": {},
+			},
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource_server1": {},
+			},
+		}).ProviderServer,
+		(&tf5testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov5.Schema{
+				"test_data_source_server2": {},
+			},
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource_server2": {},
+			},
+		}).ProviderServer,
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := map[string]struct {
+		serverFor           func(string) (int, bool)
+		typeName            string
+		expectedServerIndex int
+		expectedOk          bool
+	}{
+		"data-source-server1": {
+			serverFor:           muxServer.ServerForDataSource,
+			typeName:            "test_data_source_server1",
+			expectedServerIndex: 0,
+			expectedOk:          true,
+		},
+		"data-source-server2": {
+			serverFor:           muxServer.ServerForDataSource,
+			typeName:            "test_data_source_server2",
+			expectedServerIndex: 1,
+			expectedOk:          true,
+		},
+		"data-source-resource-type": {
+			serverFor: muxServer.ServerForDataSource,
+			typeName:  "test_resource_server1",
+		},
+		"resource-server1": {
+			serverFor:           muxServer.ServerForResource,
+			typeName:            "test_resource_server1",
+			expectedServerIndex: 0,
+			expectedOk:          true,
+		},
+		"resource-server2": {
+			serverFor:           muxServer.ServerForResource,
+			typeName:            "test_resource_server2",
+			expectedServerIndex: 1,
+			expectedOk:          true,
+		},
+		"resource-unknown": {
+			serverFor: muxServer.ServerForResource,
+			typeName:  "test_resource_unknown",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			serverIndex, ok := testCase.serverFor(testCase.typeName)
+
+			if ok != testCase.expectedOk {
+				t.Fatalf("expected ok %t, got %t", testCase.expectedOk, ok)
+			}
+
+			if serverIndex != testCase.expectedServerIndex {
+				t.Errorf("expected server index %d, got %d", testCase.expectedServerIndex, serverIndex)
+			}
+		})
+	}
+}
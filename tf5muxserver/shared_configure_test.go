@@ -0,0 +1,185 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// sharedClient is a client configured by a primary server and shared with
+// the other servers.
+type sharedClient struct {
+	endpoint string
+}
+
+// primaryServer is a server implementing ConfigureResultSharer which
+// configures a sharedClient.
+type primaryServer struct {
+	*tf5testserver.TestServer
+
+	configureProviderDiagnostics []*tfprotov5.Diagnostic
+
+	client *sharedClient
+}
+
+func (s *primaryServer) ConfigureProvider(_ context.Context, _ *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	s.client = &sharedClient{
+		endpoint: "https://example.com",
+	}
+
+	return &tfprotov5.ConfigureProviderResponse{
+		Diagnostics: s.configureProviderDiagnostics,
+	}, nil
+}
+
+func (s *primaryServer) ConfigureResult() interface{} {
+	return s.client
+}
+
+// secondaryServer is a server recording the result shared with it during
+// ConfigureProvider.
+type secondaryServer struct {
+	*tf5testserver.TestServer
+
+	configured   bool
+	sharedResult interface{}
+}
+
+func (s *secondaryServer) ConfigureProvider(ctx context.Context, _ *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	s.configured = true
+	s.sharedResult, _ = tf5muxserver.SharedConfigureResult(ctx)
+
+	return &tfprotov5.ConfigureProviderResponse{}, nil
+}
+
+func TestWithSharedConfigure(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options                      []tf5muxserver.ServerOption
+		configureProviderDiagnostics []*tfprotov5.Diagnostic
+		expectedError                string
+		expectedConfigured           bool
+		expectedShared               bool
+	}{
+		"disabled": {
+			expectedConfigured: true,
+		},
+		"shared": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithSharedConfigure(1),
+			},
+			expectedConfigured: true,
+			expectedShared:     true,
+		},
+		"shared-configure-order": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithConfigureOrder([]int{1, 0}),
+				tf5muxserver.WithSharedConfigure(1),
+			},
+			expectedConfigured: true,
+			expectedShared:     true,
+		},
+		"primary-error-diagnostic": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithSharedConfigure(1),
+			},
+			configureProviderDiagnostics: []*tfprotov5.Diagnostic{
+				{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "test error summary",
+				},
+			},
+		},
+		"primary-not-first": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithConfigureOrder([]int{0, 1}),
+				tf5muxserver.WithSharedConfigure(1),
+			},
+			expectedError: "shared configure primary server 1 must be first in the configure order, however server 0 is first",
+		},
+		"primary-not-sharer": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithSharedConfigure(0),
+			},
+			expectedError: "shared configure primary server 0 (*tf5muxserver_test.secondaryServer) does not implement ConfigureResultSharer",
+		},
+		"primary-out-of-range": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithSharedConfigure(2),
+			},
+			expectedError: "shared configure primary server index 2 is out of range for 2 servers",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			secondary := &secondaryServer{
+				TestServer: &tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_secondary": {},
+					},
+				},
+			}
+			primary := &primaryServer{
+				TestServer: &tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_primary": {},
+					},
+				},
+				configureProviderDiagnostics: testCase.configureProviderDiagnostics,
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(
+				ctx,
+				testCase.options,
+				func() tfprotov5.ProviderServer { return secondary },
+				func() tfprotov5.ProviderServer { return primary },
+			)
+
+			if err != nil {
+				if err.Error() != testCase.expectedError {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error %q", testCase.expectedError)
+			}
+
+			_, err = muxServer.ProviderServer().ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if secondary.configured != testCase.expectedConfigured {
+				t.Errorf("expected secondary server configured %t, got: %t", testCase.expectedConfigured, secondary.configured)
+			}
+
+			if !testCase.expectedShared {
+				if secondary.sharedResult != nil {
+					t.Errorf("unexpected shared result: %v", secondary.sharedResult)
+				}
+
+				return
+			}
+
+			client, ok := secondary.sharedResult.(*sharedClient)
+
+			if !ok || client != primary.client {
+				t.Errorf("expected shared result to be the primary server client, got: %v", secondary.sharedResult)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package tf5muxserver
+
+import (
+	"fmt"
+)
+
+// checkSharedProviderSchema returns an error if any server does not declare a
+// provider schema identical to the provider schema of the first server, as
+// required by the WithRequireSharedProviderSchema option.
+func (s muxServer) checkSharedProviderSchema() error {
+	for serverIndex, serverProviderSchema := range s.serverProviderSchemas {
+		if serverProviderSchema == nil {
+			return fmt.Errorf("server %d (%T) declares no provider schema, however all servers must declare the identical provider schema", serverIndex, s.servers[serverIndex])
+		}
+
+		if serverIndex == 0 {
+			continue
+		}
+
+		if !schemaEquals(serverProviderSchema, s.serverProviderSchemas[0]) {
+			return fmt.Errorf("server %d (%T) declares a provider schema different from server 0 (%T), however all servers must declare the identical provider schema. Diff: %s", serverIndex, s.servers[serverIndex], s.servers[0], schemaDiff(serverProviderSchema, s.serverProviderSchemas[0]))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestWithRequireSharedProviderSchema(t *testing.T) {
+	t.Parallel()
+
+	providerSchema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name:     "account_id",
+					Type:     tftypes.String,
+					Required: true,
+				},
+			},
+		},
+	}
+
+	servers := []*tf5testserver.TestServer{
+		{
+			ProviderSchema: providerSchema,
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource_server1": {},
+			},
+		},
+		{
+			ProviderSchema: providerSchema,
+			ResourceSchemas: map[string]*tfprotov5.Schema{
+				"test_resource_server2": {},
+			},
+		},
+	}
+
+	options := []tf5muxserver.ServerOption{
+		tf5muxserver.WithProviderSchemaMerge(true),
+		tf5muxserver.WithRequireSharedProviderSchema(true),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), options, servers[0].ProviderServer, servers[1].ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	config, err := tfprotov5.NewDynamicValue(providerSchema.ValueType(), tftypes.NewValue(providerSchema.ValueType(), map[string]tftypes.Value{
+		"account_id": tftypes.NewValue(tftypes.String, "test-account"),
+	}))
+
+	if err != nil {
+		t.Fatalf("unexpected error creating config: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().PrepareProviderConfig(context.Background(), &tfprotov5.PrepareProviderConfigRequest{
+		Config: &config,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected PrepareProviderConfig error: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{
+		Config: &config,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ConfigureProvider error: %s", err)
+	}
+
+	for serverIndex, server := range servers {
+		if !server.PrepareProviderConfigCalled {
+			t.Errorf("expected server %d PrepareProviderConfig to be called", serverIndex)
+		}
+
+		if !server.ConfigureProviderCalled {
+			t.Errorf("expected server %d ConfigureProvider to be called", serverIndex)
+		}
+	}
+}
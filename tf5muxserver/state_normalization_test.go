@@ -0,0 +1,169 @@
+package tf5muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// fixedStateServer is a server whose PlanResourceChange, ApplyResourceChange,
+// and ReadResource methods return the given state.
+type fixedStateServer struct {
+	tfprotov5.ProviderServer
+
+	state *tfprotov5.DynamicValue
+}
+
+func (s fixedStateServer) ApplyResourceChange(_ context.Context, _ *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	return &tfprotov5.ApplyResourceChangeResponse{
+		NewState: s.state,
+	}, nil
+}
+
+func (s fixedStateServer) PlanResourceChange(_ context.Context, _ *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	return &tfprotov5.PlanResourceChangeResponse{
+		PlannedState: s.state,
+	}, nil
+}
+
+func (s fixedStateServer) ReadResource(_ context.Context, _ *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	return &tfprotov5.ReadResourceResponse{
+		NewState: s.state,
+	}, nil
+}
+
+func TestWithStateNormalization(t *testing.T) {
+	t.Parallel()
+
+	schema := &tfprotov5.Schema{
+		Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name:     "tags",
+					Type:     tftypes.Set{ElementType: tftypes.String},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	newState := func(tags ...string) *tfprotov5.DynamicValue {
+		elements := make([]tftypes.Value, 0, len(tags))
+
+		for _, tag := range tags {
+			elements = append(elements, tftypes.NewValue(tftypes.String, tag))
+		}
+
+		state, err := tfprotov5.NewDynamicValue(schema.ValueType(), tftypes.NewValue(schema.ValueType(), map[string]tftypes.Value{
+			"tags": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elements),
+		}))
+
+		if err != nil {
+			t.Fatalf("unable to create state: %s", err)
+		}
+
+		return &state
+	}
+
+	inputState := newState("a", "b")
+	reorderedState := newState("b", "a")
+	differentState := newState("a", "c")
+
+	testCases := map[string]struct {
+		types         []string
+		serverState   *tfprotov5.DynamicValue
+		expectedState *tfprotov5.DynamicValue
+	}{
+		"disabled": {
+			serverState:   reorderedState,
+			expectedState: reorderedState,
+		},
+		"enabled-different": {
+			types:         []string{"test_resource"},
+			serverState:   differentState,
+			expectedState: differentState,
+		},
+		"enabled-other-type": {
+			types:         []string{"test_resource_other"},
+			serverState:   reorderedState,
+			expectedState: reorderedState,
+		},
+		"enabled-reordered": {
+			types:         []string{"test_resource"},
+			serverState:   reorderedState,
+			expectedState: inputState,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			servers := []func() tfprotov5.ProviderServer{
+				func() tfprotov5.ProviderServer {
+					return fixedStateServer{
+						ProviderServer: &tf5testserver.TestServer{
+							ResourceSchemas: map[string]*tfprotov5.Schema{
+								"test_resource": schema,
+							},
+						},
+						state: testCase.serverState,
+					}
+				},
+			}
+
+			muxServer, err := tf5muxserver.NewMuxServerWithOptions(ctx, []tf5muxserver.ServerOption{tf5muxserver.WithStateNormalization(testCase.types)}, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			planResp, err := muxServer.ProviderServer().PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+				ProposedNewState: inputState,
+				TypeName:         "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(planResp.PlannedState, testCase.expectedState); diff != "" {
+				t.Errorf("unexpected PlanResourceChange PlannedState difference: %s", diff)
+			}
+
+			applyResp, err := muxServer.ProviderServer().ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+				PlannedState: inputState,
+				TypeName:     "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(applyResp.NewState, testCase.expectedState); diff != "" {
+				t.Errorf("unexpected ApplyResourceChange NewState difference: %s", diff)
+			}
+
+			readResp, err := muxServer.ProviderServer().ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+				CurrentState: inputState,
+				TypeName:     "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(readResp.NewState, testCase.expectedState); diff != "" {
+				t.Errorf("unexpected ReadResource NewState difference: %s", diff)
+			}
+		})
+	}
+}
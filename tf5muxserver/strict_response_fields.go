@@ -0,0 +1,110 @@
+package tf5muxserver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// knownResponseFields are the fields of each RPC response type which the
+// muxed server understands and forwards, keyed by response struct type. It
+// must be updated, after reviewing how the muxed server handles the new
+// fields, whenever terraform-plugin-go adds response fields, which
+// TestKnownResponseFieldsCoverage detects.
+var knownResponseFields = map[reflect.Type][]string{
+	reflect.TypeOf(tfprotov5.ApplyResourceChangeResponse{}):        {"NewState", "Private", "Diagnostics", "UnsafeToUseLegacyTypeSystem"},
+	reflect.TypeOf(tfprotov5.ConfigureProviderResponse{}):          {"Diagnostics"},
+	reflect.TypeOf(tfprotov5.GetProviderSchemaResponse{}):          {"ServerCapabilities", "Provider", "ProviderMeta", "ResourceSchemas", "DataSourceSchemas", "Diagnostics"},
+	reflect.TypeOf(tfprotov5.ImportResourceStateResponse{}):        {"ImportedResources", "Diagnostics"},
+	reflect.TypeOf(tfprotov5.PlanResourceChangeResponse{}):         {"PlannedState", "RequiresReplace", "PlannedPrivate", "Diagnostics", "UnsafeToUseLegacyTypeSystem"},
+	reflect.TypeOf(tfprotov5.PrepareProviderConfigResponse{}):      {"PreparedConfig", "Diagnostics"},
+	reflect.TypeOf(tfprotov5.ReadDataSourceResponse{}):             {"State", "Diagnostics"},
+	reflect.TypeOf(tfprotov5.ReadResourceResponse{}):               {"NewState", "Diagnostics", "Private"},
+	reflect.TypeOf(tfprotov5.StopProviderResponse{}):               {"Error"},
+	reflect.TypeOf(tfprotov5.UpgradeResourceStateResponse{}):       {"UpgradedState", "Diagnostics"},
+	reflect.TypeOf(tfprotov5.ValidateDataSourceConfigResponse{}):   {"Diagnostics"},
+	reflect.TypeOf(tfprotov5.ValidateResourceTypeConfigResponse{}): {"Diagnostics"},
+}
+
+// unknownResponseFields returns the sorted names of the fields of the
+// response which are set to a non-zero value but are not known, as listed in
+// known for the response type. Responses of types not listed in known are
+// assumed to be fully understood, and nil responses have no unknown fields.
+func unknownResponseFields(known map[reflect.Type][]string, resp interface{}) []string {
+	value := reflect.ValueOf(resp)
+
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	value = value.Elem()
+	knownFields, ok := known[value.Type()]
+
+	if !ok {
+		return nil
+	}
+
+	var result []string
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if !field.IsExported() || value.Field(i).IsZero() || containsString(knownFields, field.Name) {
+			continue
+		}
+
+		result = append(result, field.Name)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// checkResponseFields returns an error if the response of the RPC sets
+// fields which are not known, as listed in known for the response type.
+func checkResponseFields(known map[reflect.Type][]string, rpc string, resp interface{}) error {
+	fields := unknownResponseFields(known, resp)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s response sets fields unknown to the muxed server, which would not be forwarded: %s", rpc, strings.Join(fields, ", "))
+}
+
+// strictResponseFieldsMiddleware returns Middleware returning an error in
+// place of responses setting fields which are not known, as listed in known
+// for the response type.
+func strictResponseFieldsMiddleware(known map[reflect.Type][]string) Middleware {
+	return func(next RPCHandler) RPCHandler {
+		return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+			resp, err := next(ctx, rpc, req)
+
+			if err != nil {
+				return resp, err
+			}
+
+			if err := checkResponseFields(known, rpc, resp); err != nil {
+				return nil, err
+			}
+
+			return resp, nil
+		}
+	}
+}
+
+// containsString returns true if the value is in values.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
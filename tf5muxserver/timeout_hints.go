@@ -0,0 +1,93 @@
+package tf5muxserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// TimeoutHintProvider is an optional interface which servers can implement to
+// hint the RPC timeouts of the resource and data source types they
+// implement, such as longer timeouts for resource types known to be slow.
+// Hints are only read when enabled via the WithTimeoutHints option, during
+// muxed server creation and when Reroute or ReplaceServer change routing.
+type TimeoutHintProvider interface {
+	// TimeoutHints returns the RPC timeouts of the server, keyed by resource
+	// or data source type name as declared by the server.
+	TimeoutHints() map[string]time.Duration
+}
+
+// readTimeoutHints returns the RPC timeouts hinted by servers implementing
+// TimeoutHintProvider, keyed by type name including any prefix configured
+// via the WithNamespace option. Hints for type names which are not routed to
+// the hinting server are ignored.
+func (s muxServer) readTimeoutHints(ctx context.Context) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	for serverIndex, server := range s.servers {
+		s.addTimeoutHints(ctx, result, serverIndex, server)
+	}
+
+	return result
+}
+
+// addTimeoutHints adds the RPC timeouts hinted by the server, if it
+// implements TimeoutHintProvider, to the given timeouts. The caller must hold
+// routingMu.
+func (s muxServer) addTimeoutHints(ctx context.Context, timeouts map[string]time.Duration, serverIndex int, server tfprotov5.ProviderServer) {
+	timeoutHintProvider, ok := server.(TimeoutHintProvider)
+
+	if !ok {
+		return
+	}
+
+	for typeName, timeout := range timeoutHintProvider.TimeoutHints() {
+		typeName = s.serverNamespace(serverIndex) + typeName
+
+		if !s.routesTypeTo(typeName, serverIndex) {
+			logging.MuxWarn(ctx, "ignoring timeout hint for type not implemented by server", map[string]interface{}{
+				logging.KeyTfMuxServerIndex: serverIndex,
+			})
+
+			continue
+		}
+
+		timeouts[typeName] = timeout
+	}
+}
+
+// updateTimeoutHints replaces the hinted RPC timeouts of the types routed to
+// the server after routing changes, if enabled via WithTimeoutHints. The
+// caller must hold routingMu for writing.
+func (s muxServer) updateTimeoutHints(ctx context.Context, serverIndex int, server tfprotov5.ProviderServer) {
+	if s.typeTimeouts == nil {
+		return
+	}
+
+	for typeName := range s.typeTimeouts {
+		if s.routesTypeTo(typeName, serverIndex) {
+			delete(s.typeTimeouts, typeName)
+		}
+	}
+
+	s.addTimeoutHints(ctx, s.typeTimeouts, serverIndex, server)
+}
+
+// routesTypeTo returns true if the resource or data source type is routed to
+// the server. The caller must hold routingMu.
+func (s muxServer) routesTypeTo(typeName string, serverIndex int) bool {
+	if index, ok := s.resourceServerIndex[typeName]; ok && index == serverIndex {
+		return true
+	}
+
+	if index, ok := s.dataSourceServerIndex[typeName]; ok && index == serverIndex {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,37 @@
+package tf5muxserver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// isUnimplementedError returns true if the error, or any error it wraps, is a
+// gRPC status error with the Unimplemented code, such as returned by servers
+// which implement only a subset of the provider server RPCs.
+func isUnimplementedError(err error) bool {
+	var statusErr interface {
+		GRPCStatus() *status.Status
+	}
+
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	return statusErr.GRPCStatus().Code() == codes.Unimplemented
+}
+
+// unimplementedRPCDiagnostic returns an error Diagnostic describing that the
+// server handling the type name does not implement the RPC.
+func unimplementedRPCDiagnostic(rpc string, typeName string, server tfprotov5.ProviderServer, err error) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  "Unsupported Provider Operation",
+		Detail: fmt.Sprintf("The %s operation for %q is not supported by the provider, because the server %T handling it does not implement the operation. ", rpc, typeName, server) +
+			"This is always an issue in the provider implementation and should be reported to the provider developers.\n\n" +
+			fmt.Sprintf("Error: %s", err),
+	}
+}
@@ -0,0 +1,33 @@
+package tf5muxserver
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// UpgradeResourceStateDiagnosticsFunc returns muxed server diagnostics for an
+// UpgradeResourceState request, such as warnings about the state version,
+// which are prepended to the diagnostics of the server implementing the
+// resource type.
+type UpgradeResourceStateDiagnosticsFunc func(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) []*tfprotov5.Diagnostic
+
+// upgradeResourceStateResponseWithDiagnostics returns a copy of the response
+// with the diagnostics prepended to the response diagnostics. The upgraded
+// state is preserved. A nil response results in a response containing only the
+// diagnostics.
+func upgradeResourceStateResponseWithDiagnostics(resp *tfprotov5.UpgradeResourceStateResponse, diags []*tfprotov5.Diagnostic) *tfprotov5.UpgradeResourceStateResponse {
+	if len(diags) == 0 {
+		return resp
+	}
+
+	result := &tfprotov5.UpgradeResourceStateResponse{}
+
+	if resp != nil {
+		*result = *resp
+	}
+
+	result.Diagnostics = append(append(make([]*tfprotov5.Diagnostic, 0, len(diags)+len(result.Diagnostics)), diags...), result.Diagnostics...)
+
+	return result
+}
@@ -0,0 +1,67 @@
+package tf5muxservertest
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// RoutedServer is a muxed server which reports the server implementing each
+// type name, such as the muxed servers created by tf5muxserver.NewMuxServer.
+type RoutedServer interface {
+	// ServerForDataSource returns the index of the server implementing the
+	// data source type, and whether any server implements it.
+	ServerForDataSource(typeName string) (int, bool)
+
+	// ServerForResource returns the index of the server implementing the
+	// managed resource type, and whether any server implements it.
+	ServerForResource(typeName string) (int, bool)
+}
+
+// AssertRouting fails the test if any of the expected type names is not
+// routed to the expected server index, reporting a difference of the expected
+// and actual routing. Each type name may be a managed resource type, a data
+// source type, or both, in which case both must be routed to the expected
+// server index. Type names which are not routed are reported with server
+// index -1.
+func AssertRouting(t testing.TB, muxServer RoutedServer, expected map[string]int) {
+	t.Helper()
+
+	actual := make(map[string]int, len(expected))
+	typeNames := make([]string, 0, len(expected))
+
+	for typeName := range expected {
+		typeNames = append(typeNames, typeName)
+	}
+
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		actual[typeName] = routedServerIndex(muxServer, typeName, expected[typeName])
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected routing difference (-expected +actual):\n%s", diff)
+	}
+}
+
+// routedServerIndex returns the index of the server implementing the type
+// name, preferring a server index which differs from the expected server
+// index when the type name is both a resource and data source type. The
+// server index is -1 if no server implements the type name.
+func routedServerIndex(muxServer RoutedServer, typeName string, expectedServerIndex int) int {
+	resourceServerIndex, isResource := muxServer.ServerForResource(typeName)
+	dataSourceServerIndex, isDataSource := muxServer.ServerForDataSource(typeName)
+
+	switch {
+	case isResource && resourceServerIndex != expectedServerIndex:
+		return resourceServerIndex
+	case isDataSource:
+		return dataSourceServerIndex
+	case isResource:
+		return resourceServerIndex
+	default:
+		return -1
+	}
+}
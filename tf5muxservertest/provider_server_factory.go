@@ -0,0 +1,32 @@
+package tf5muxservertest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// ProviderServerFactory returns a function compatible with the
+// ProtoV5ProviderFactories field of acceptance testing TestCase types, which
+// creates a muxed server of the given servers each time it is called. Any
+// error creating the muxed server is returned, which fails the test.
+func ProviderServerFactory(servers ...func() tfprotov5.ProviderServer) func() (tfprotov5.ProviderServer, error) {
+	return ProviderServerFactoryWithOptions(nil, servers...)
+}
+
+// ProviderServerFactoryWithOptions returns a function in the same manner as
+// ProviderServerFactory, with optional muxed server behaviors configured by
+// the given options.
+func ProviderServerFactoryWithOptions(opts []tf5muxserver.ServerOption, servers ...func() tfprotov5.ProviderServer) func() (tfprotov5.ProviderServer, error) {
+	return func() (tfprotov5.ProviderServer, error) {
+		muxServer, err := tf5muxserver.NewMuxServerWithOptions(context.Background(), opts, servers...)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to create muxed provider server: %w", err)
+		}
+
+		return muxServer.ProviderServer(), nil
+	}
+}
@@ -0,0 +1,107 @@
+package tf5muxservertest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxservertest"
+)
+
+func TestProviderServerFactory(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options       []tf5muxserver.ServerOption
+		servers       []func() tfprotov5.ProviderServer
+		expectedError string
+	}{
+		"duplicate-resource": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `unable to create muxed provider server: resource "test_resource" is implemented by multiple servers; only one implementation allowed`,
+		},
+		"options": {
+			options: []tf5muxserver.ServerOption{
+				tf5muxserver.WithRequireProviderSchema(true),
+			},
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			},
+			expectedError: "unable to create muxed provider server: none of the 1 servers declared a provider schema",
+		},
+		"success": {
+			servers: []func() tfprotov5.ProviderServer{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov5.Schema{
+						"test_data_source": {},
+					},
+				}).ProviderServer,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			factory := tf5muxservertest.ProviderServerFactoryWithOptions(testCase.options, testCase.servers...)
+
+			server, err := factory()
+
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			resp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if _, ok := resp.ResourceSchemas["test_resource"]; !ok {
+				t.Errorf("expected test_resource schema")
+			}
+
+			if _, ok := resp.DataSourceSchemas["test_data_source"]; !ok {
+				t.Errorf("expected test_data_source schema")
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package tf5muxservertest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+var _ tfprotov5.ProviderServer = &RecordingServer{}
+
+// RecordingServer is a tfprotov5.ProviderServer which records the last
+// request of each RPC before calling the same method on an underlying server.
+// It is safe for concurrent use and should always be instantiated by calling
+// NewRecordingServer().
+type RecordingServer struct {
+	mu     sync.Mutex
+	server tfprotov5.ProviderServer
+
+	lastApplyResourceChangeRequest        *tfprotov5.ApplyResourceChangeRequest
+	lastConfigureProviderRequest          *tfprotov5.ConfigureProviderRequest
+	lastGetProviderSchemaRequest          *tfprotov5.GetProviderSchemaRequest
+	lastImportResourceStateRequest        *tfprotov5.ImportResourceStateRequest
+	lastPlanResourceChangeRequest         *tfprotov5.PlanResourceChangeRequest
+	lastPrepareProviderConfigRequest      *tfprotov5.PrepareProviderConfigRequest
+	lastReadDataSourceRequest             *tfprotov5.ReadDataSourceRequest
+	lastReadResourceRequest               *tfprotov5.ReadResourceRequest
+	lastStopProviderRequest               *tfprotov5.StopProviderRequest
+	lastUpgradeResourceStateRequest       *tfprotov5.UpgradeResourceStateRequest
+	lastValidateDataSourceConfigRequest   *tfprotov5.ValidateDataSourceConfigRequest
+	lastValidateResourceTypeConfigRequest *tfprotov5.ValidateResourceTypeConfigRequest
+}
+
+// NewRecordingServer returns a RecordingServer which calls the given server
+// after recording each request.
+func NewRecordingServer(server tfprotov5.ProviderServer) *RecordingServer {
+	return &RecordingServer{
+		server: server,
+	}
+}
+
+// ProviderServer is a function compatible with tf5muxserver.NewMuxServer.
+func (s *RecordingServer) ProviderServer() tfprotov5.ProviderServer {
+	return s
+}
+
+func (s *RecordingServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	s.mu.Lock()
+	s.lastApplyResourceChangeRequest = req
+	s.mu.Unlock()
+
+	return s.server.ApplyResourceChange(ctx, req)
+}
+
+// LastApplyResourceChangeRequest returns the most recent request, if any.
+func (s *RecordingServer) LastApplyResourceChangeRequest() *tfprotov5.ApplyResourceChangeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastApplyResourceChangeRequest
+}
+
+func (s *RecordingServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	s.mu.Lock()
+	s.lastConfigureProviderRequest = req
+	s.mu.Unlock()
+
+	return s.server.ConfigureProvider(ctx, req)
+}
+
+// LastConfigureProviderRequest returns the most recent request, if any.
+func (s *RecordingServer) LastConfigureProviderRequest() *tfprotov5.ConfigureProviderRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastConfigureProviderRequest
+}
+
+func (s *RecordingServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	s.mu.Lock()
+	s.lastGetProviderSchemaRequest = req
+	s.mu.Unlock()
+
+	return s.server.GetProviderSchema(ctx, req)
+}
+
+// LastGetProviderSchemaRequest returns the most recent request, if any.
+func (s *RecordingServer) LastGetProviderSchemaRequest() *tfprotov5.GetProviderSchemaRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastGetProviderSchemaRequest
+}
+
+func (s *RecordingServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	s.mu.Lock()
+	s.lastImportResourceStateRequest = req
+	s.mu.Unlock()
+
+	return s.server.ImportResourceState(ctx, req)
+}
+
+// LastImportResourceStateRequest returns the most recent request, if any.
+func (s *RecordingServer) LastImportResourceStateRequest() *tfprotov5.ImportResourceStateRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastImportResourceStateRequest
+}
+
+func (s *RecordingServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	s.mu.Lock()
+	s.lastPlanResourceChangeRequest = req
+	s.mu.Unlock()
+
+	return s.server.PlanResourceChange(ctx, req)
+}
+
+// LastPlanResourceChangeRequest returns the most recent request, if any.
+func (s *RecordingServer) LastPlanResourceChangeRequest() *tfprotov5.PlanResourceChangeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastPlanResourceChangeRequest
+}
+
+func (s *RecordingServer) PrepareProviderConfig(ctx context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	s.mu.Lock()
+	s.lastPrepareProviderConfigRequest = req
+	s.mu.Unlock()
+
+	return s.server.PrepareProviderConfig(ctx, req)
+}
+
+// LastPrepareProviderConfigRequest returns the most recent request, if any.
+func (s *RecordingServer) LastPrepareProviderConfigRequest() *tfprotov5.PrepareProviderConfigRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastPrepareProviderConfigRequest
+}
+
+func (s *RecordingServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	s.mu.Lock()
+	s.lastReadDataSourceRequest = req
+	s.mu.Unlock()
+
+	return s.server.ReadDataSource(ctx, req)
+}
+
+// LastReadDataSourceRequest returns the most recent request, if any.
+func (s *RecordingServer) LastReadDataSourceRequest() *tfprotov5.ReadDataSourceRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastReadDataSourceRequest
+}
+
+func (s *RecordingServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	s.mu.Lock()
+	s.lastReadResourceRequest = req
+	s.mu.Unlock()
+
+	return s.server.ReadResource(ctx, req)
+}
+
+// LastReadResourceRequest returns the most recent request, if any.
+func (s *RecordingServer) LastReadResourceRequest() *tfprotov5.ReadResourceRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastReadResourceRequest
+}
+
+func (s *RecordingServer) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	s.mu.Lock()
+	s.lastStopProviderRequest = req
+	s.mu.Unlock()
+
+	return s.server.StopProvider(ctx, req)
+}
+
+// LastStopProviderRequest returns the most recent request, if any.
+func (s *RecordingServer) LastStopProviderRequest() *tfprotov5.StopProviderRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastStopProviderRequest
+}
+
+func (s *RecordingServer) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	s.mu.Lock()
+	s.lastUpgradeResourceStateRequest = req
+	s.mu.Unlock()
+
+	return s.server.UpgradeResourceState(ctx, req)
+}
+
+// LastUpgradeResourceStateRequest returns the most recent request, if any.
+func (s *RecordingServer) LastUpgradeResourceStateRequest() *tfprotov5.UpgradeResourceStateRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastUpgradeResourceStateRequest
+}
+
+func (s *RecordingServer) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
+	s.mu.Lock()
+	s.lastValidateDataSourceConfigRequest = req
+	s.mu.Unlock()
+
+	return s.server.ValidateDataSourceConfig(ctx, req)
+}
+
+// LastValidateDataSourceConfigRequest returns the most recent request, if any.
+func (s *RecordingServer) LastValidateDataSourceConfigRequest() *tfprotov5.ValidateDataSourceConfigRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastValidateDataSourceConfigRequest
+}
+
+func (s *RecordingServer) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	s.mu.Lock()
+	s.lastValidateResourceTypeConfigRequest = req
+	s.mu.Unlock()
+
+	return s.server.ValidateResourceTypeConfig(ctx, req)
+}
+
+// LastValidateResourceTypeConfigRequest returns the most recent request, if any.
+func (s *RecordingServer) LastValidateResourceTypeConfigRequest() *tfprotov5.ValidateResourceTypeConfigRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastValidateResourceTypeConfigRequest
+}
@@ -59,7 +59,7 @@ func (s v5tov6Server) GetProviderSchema(ctx context.Context, req *tfprotov6.GetP
 		return nil, err
 	}
 
-	return tfprotov5tov6.GetProviderSchemaResponse(v5Resp), nil
+	return tfprotov5tov6.GetProviderSchemaResponse(v5Resp)
 }
 
 func (s v5tov6Server) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
@@ -0,0 +1,124 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithMaxAttributeCount(t *testing.T) {
+	t.Parallel()
+
+	attributes := func(names ...string) []*tfprotov6.SchemaAttribute {
+		result := make([]*tfprotov6.SchemaAttribute, 0, len(names))
+
+		for _, name := range names {
+			result = append(result, &tfprotov6.SchemaAttribute{
+				Name:     name,
+				Type:     tftypes.String,
+				Optional: true,
+			})
+		}
+
+		return result
+	}
+
+	servers := []func() tfprotov6.ProviderServer{
+		(&tf6testserver.TestServer{
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: attributes("region"),
+				},
+			},
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource_server1": {
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: attributes("id"),
+					},
+				},
+			},
+		}).ProviderServer,
+		(&tf6testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov6.Schema{
+				"test_data_source_server2": {
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: attributes("id", "name"),
+					},
+				},
+			},
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: attributes("region"),
+				},
+			},
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource_server2": {
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: attributes("id"),
+						BlockTypes: []*tfprotov6.SchemaNestedBlock{
+							{
+								TypeName: "rule",
+								Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+								Block: &tfprotov6.SchemaBlock{
+									Attributes: attributes("action", "priority"),
+								},
+							},
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+	}
+
+	testCases := map[string]struct {
+		maxAttributeCount int
+		expectedError     string
+	}{
+		"disabled": {},
+		"exceeded": {
+			maxAttributeCount: 6,
+			expectedError: "the muxed provider schemas declare 7 attributes, which exceeds the maximum of 6 attributes. " +
+				"Largest servers: server 1 (*tf6testserver.TestServer): 5 attributes, server 0 (*tf6testserver.TestServer): 1 attributes",
+		},
+		"not-exceeded": {
+			maxAttributeCount: 7,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{tf6muxserver.WithMaxAttributeCount(testCase.maxAttributeCount)}, servers...)
+
+			if err != nil {
+				if testCase.expectedError == "" || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			expectedCounts := tf6muxserver.AttributeCounts{
+				Total:   7,
+				Servers: []int{1, 5},
+			}
+
+			if diff := cmp.Diff(muxServer.AttributeCounts(), expectedCounts); diff != "" {
+				t.Errorf("unexpected attribute counts difference: %s", diff)
+			}
+		})
+	}
+}
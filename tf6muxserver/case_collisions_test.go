@@ -0,0 +1,123 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithCaseCollisionWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled             bool
+		servers             []func() tfprotov6.ProviderServer
+		expectedDiagnostics []*tfprotov6.Diagnostic
+	}{
+		"disabled": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_Resource": {},
+					},
+				}).ProviderServer,
+			},
+		},
+		"enabled-collisions": {
+			enabled: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_Data_Source": {},
+					},
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_Resource": {},
+					},
+				}).ProviderServer,
+			},
+			expectedDiagnostics: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityWarning,
+					Summary:  "Type Names Differ Only By Case",
+					Detail: "The resource types \"test_Resource\", implemented by server 1 (*tf6testserver.TestServer), " +
+						"and \"test_resource\", implemented by server 0 (*tf6testserver.TestServer), differ only by case. " +
+						"This is usually caused by a typo. Both types are served by the provider.",
+				},
+				{
+					Severity: tfprotov6.DiagnosticSeverityWarning,
+					Summary:  "Type Names Differ Only By Case",
+					Detail: "The data source types \"test_Data_Source\", implemented by server 1 (*tf6testserver.TestServer), " +
+						"and \"test_data_source\", implemented by server 0 (*tf6testserver.TestServer), differ only by case. " +
+						"This is usually caused by a typo. Both types are served by the provider.",
+				},
+			},
+		},
+		"enabled-no-collisions": {
+			enabled: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_a": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_b": {},
+					},
+				}).ProviderServer,
+			},
+		},
+		"enabled-same-server": {
+			enabled: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+						"test_Resource": {},
+					},
+				}).ProviderServer,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{tf6muxserver.WithCaseCollisionWarnings(testCase.enabled)}, testCase.servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package tf6muxserver
+
+import (
+	"fmt"
+)
+
+// checkConfigureOrder returns an error if the server indexes of the
+// WithConfigureOrder option are not a permutation of the server indexes.
+func (s muxServer) checkConfigureOrder() error {
+	if len(s.options.configureOrder) != len(s.servers) {
+		return fmt.Errorf("configure order has %d server indexes, however there are %d servers", len(s.options.configureOrder), len(s.servers))
+	}
+
+	seen := make(map[int]struct{}, len(s.options.configureOrder))
+
+	for _, serverIndex := range s.options.configureOrder {
+		if serverIndex < 0 || serverIndex >= len(s.servers) {
+			return fmt.Errorf("configure order server index %d is out of range for %d servers", serverIndex, len(s.servers))
+		}
+
+		if _, ok := seen[serverIndex]; ok {
+			return fmt.Errorf("configure order server index %d is given multiple times", serverIndex)
+		}
+
+		seen[serverIndex] = struct{}{}
+	}
+
+	return nil
+}
+
+// configureOrder returns the server indexes in the order ConfigureProvider is
+// called, which is the order given to NewMuxServer unless the
+// WithConfigureOrder option is configured. If the WithSharedConfigure option
+// is configured, the primary server is called first.
+func (s muxServer) configureOrder(serverCount int) []int {
+	if s.options.configureOrder != nil {
+		return s.options.configureOrder
+	}
+
+	result := make([]int, 0, serverCount)
+
+	if s.options.sharedConfigure {
+		result = append(result, s.options.sharedConfigurePrimary)
+	}
+
+	for serverIndex := 0; serverIndex < serverCount; serverIndex++ {
+		if s.options.sharedConfigure && serverIndex == s.options.sharedConfigurePrimary {
+			continue
+		}
+
+		result = append(result, serverIndex)
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package tf6muxserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// conflictSchemaDump is the content of the file written by
+// WithConflictSchemaDump.
+type conflictSchemaDump struct {
+	Servers []*conflictSchemaDumpServer `json:"servers"`
+}
+
+// conflictSchemaDumpServer is the JSON representation of the schemas declared
+// by a server.
+type conflictSchemaDumpServer struct {
+	ServerIndex        int                           `json:"server_index"`
+	ServerType         string                        `json:"server_type"`
+	DataSourceSchemas  map[string]*schemaCacheSchema `json:"data_source_schemas"`
+	ProviderMetaSchema *schemaCacheSchema            `json:"provider_meta_schema,omitempty"`
+	ProviderSchema     *schemaCacheSchema            `json:"provider_schema,omitempty"`
+	ResourceSchemas    map[string]*schemaCacheSchema `json:"resource_schemas"`
+}
+
+// newConflictSchemaDumpServer returns the JSON representation of the schemas
+// in the GetProviderSchema response of the server.
+func newConflictSchemaDumpServer(serverIndex int, server tfprotov6.ProviderServer, resp *tfprotov6.GetProviderSchemaResponse) (*conflictSchemaDumpServer, error) {
+	var err error
+
+	result := &conflictSchemaDumpServer{
+		ServerIndex: serverIndex,
+		ServerType:  fmt.Sprintf("%T", server),
+	}
+
+	result.DataSourceSchemas, err = newSchemaCacheSchemas(resp.DataSourceSchemas)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.ProviderMetaSchema, err = newSchemaCacheSchema(resp.ProviderMeta)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.ProviderSchema, err = newSchemaCacheSchema(resp.Provider)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.ResourceSchemas, err = newSchemaCacheSchemas(resp.ResourceSchemas)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// writeConflictSchemaDump writes the schemas of the servers as indented JSON
+// to the file at the given path, replacing any existing file.
+func writeConflictSchemaDump(path string, servers []*conflictSchemaDumpServer) error {
+	data, err := json.MarshalIndent(conflictSchemaDump{Servers: servers}, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("unable to encode conflict schema dump: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write conflict schema dump: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,398 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// countingDataSourceServer is a server which counts ReadDataSource calls per
+// data source type.
+type countingDataSourceServer struct {
+	tfprotov6.ProviderServer
+
+	mu    sync.Mutex
+	calls map[string]int
+	diags []*tfprotov6.Diagnostic
+}
+
+func (s *countingDataSourceServer) ReadDataSource(_ context.Context, req *tfprotov6.ReadDataSourceRequest) (*tfprotov6.ReadDataSourceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.calls == nil {
+		s.calls = make(map[string]int)
+	}
+
+	s.calls[req.TypeName]++
+
+	return &tfprotov6.ReadDataSourceResponse{
+		Diagnostics: s.diags,
+		State:       req.Config,
+	}, nil
+}
+
+func (s *countingDataSourceServer) callCount(typeName string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[typeName]
+}
+
+func TestWithDataSourceCache(t *testing.T) {
+	t.Parallel()
+
+	config1, err := tfprotov6.NewDynamicValue(tftypes.String, tftypes.NewValue(tftypes.String, "config1"))
+
+	if err != nil {
+		t.Fatalf("unable to create DynamicValue: %s", err)
+	}
+
+	config2, err := tfprotov6.NewDynamicValue(tftypes.String, tftypes.NewValue(tftypes.String, "config2"))
+
+	if err != nil {
+		t.Fatalf("unable to create DynamicValue: %s", err)
+	}
+
+	testCases := map[string]struct {
+		ttl           time.Duration
+		sleep         time.Duration
+		diags         []*tfprotov6.Diagnostic
+		requests      []*tfprotov6.ReadDataSourceRequest
+		expectedCalls map[string]int
+	}{
+		"cached-type": {
+			ttl: time.Hour,
+			requests: []*tfprotov6.ReadDataSourceRequest{
+				{TypeName: "test_cached", Config: &config1},
+				{TypeName: "test_cached", Config: &config1},
+				{TypeName: "test_cached", Config: &config1},
+			},
+			expectedCalls: map[string]int{
+				"test_cached": 1,
+			},
+		},
+		"different-config": {
+			ttl: time.Hour,
+			requests: []*tfprotov6.ReadDataSourceRequest{
+				{TypeName: "test_cached", Config: &config1},
+				{TypeName: "test_cached", Config: &config2},
+				{TypeName: "test_cached", Config: &config1},
+			},
+			expectedCalls: map[string]int{
+				"test_cached": 2,
+			},
+		},
+		"different-provider-meta": {
+			ttl: time.Hour,
+			requests: []*tfprotov6.ReadDataSourceRequest{
+				{TypeName: "test_cached", Config: &config1, ProviderMeta: &config1},
+				{TypeName: "test_cached", Config: &config1, ProviderMeta: &config2},
+				{TypeName: "test_cached", Config: &config1, ProviderMeta: &config1},
+			},
+			expectedCalls: map[string]int{
+				"test_cached": 2,
+			},
+		},
+		"error-diagnostics": {
+			ttl: time.Hour,
+			diags: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityError,
+					Summary:  "test error",
+				},
+			},
+			requests: []*tfprotov6.ReadDataSourceRequest{
+				{TypeName: "test_cached", Config: &config1},
+				{TypeName: "test_cached", Config: &config1},
+			},
+			expectedCalls: map[string]int{
+				"test_cached": 2,
+			},
+		},
+		"expired": {
+			ttl:   time.Millisecond,
+			sleep: 10 * time.Millisecond,
+			requests: []*tfprotov6.ReadDataSourceRequest{
+				{TypeName: "test_cached", Config: &config1},
+				{TypeName: "test_cached", Config: &config1},
+			},
+			expectedCalls: map[string]int{
+				"test_cached": 2,
+			},
+		},
+		"uncached-type": {
+			ttl: time.Hour,
+			requests: []*tfprotov6.ReadDataSourceRequest{
+				{TypeName: "test_uncached", Config: &config1},
+				{TypeName: "test_uncached", Config: &config1},
+			},
+			expectedCalls: map[string]int{
+				"test_uncached": 2,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			server := &countingDataSourceServer{
+				ProviderServer: &tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_cached":   {},
+						"test_uncached": {},
+					},
+				},
+				diags: testCase.diags,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+				ctx,
+				[]tf6muxserver.ServerOption{tf6muxserver.WithDataSourceCache([]string{"test_cached"}, testCase.ttl)},
+				func() tfprotov6.ProviderServer { return server },
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			for _, req := range testCase.requests {
+				resp, err := muxServer.ProviderServer().ReadDataSource(ctx, req)
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if resp.State != req.Config {
+					t.Errorf("expected response State for request Config")
+				}
+
+				time.Sleep(testCase.sleep)
+			}
+
+			for typeName, expected := range testCase.expectedCalls {
+				if got := server.callCount(typeName); got != expected {
+					t.Errorf("expected %d %s ReadDataSource calls, got %d", expected, typeName, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithDataSourceCacheConcurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := &countingDataSourceServer{
+		ProviderServer: &tf6testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov6.Schema{
+				"test_cached": {},
+			},
+		},
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+		ctx,
+		[]tf6muxserver.ServerOption{tf6muxserver.WithDataSourceCache([]string{"test_cached"}, time.Hour)},
+		func() tfprotov6.ProviderServer { return server },
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	var errCount int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := muxServer.ProviderServer().ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+				TypeName: "test_cached",
+			})
+
+			if err != nil {
+				atomic.AddInt64(&errCount, 1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if errCount > 0 {
+		t.Fatalf("unexpected errors: %d", errCount)
+	}
+
+	if got := server.callCount("test_cached"); got < 1 {
+		t.Errorf("expected at least 1 ReadDataSource call, got %d", got)
+	}
+}
+
+func TestWithDataSourceCacheMaxEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := &countingDataSourceServer{
+		ProviderServer: &tf6testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov6.Schema{
+				"test_cached": {},
+			},
+		},
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+		ctx,
+		[]tf6muxserver.ServerOption{tf6muxserver.WithDataSourceCache([]string{"test_cached"}, time.Hour)},
+		func() tfprotov6.ProviderServer { return server },
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	// Reading one more distinct configuration than the cache holds evicts
+	// the first configuration.
+	configs := make([]*tfprotov6.DynamicValue, 1025)
+
+	for i := range configs {
+		config, err := tfprotov6.NewDynamicValue(tftypes.Number, tftypes.NewValue(tftypes.Number, i))
+
+		if err != nil {
+			t.Fatalf("unable to create DynamicValue: %s", err)
+		}
+
+		configs[i] = &config
+	}
+
+	for _, config := range append(configs, configs[0]) {
+		_, err := muxServer.ProviderServer().ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+			TypeName: "test_cached",
+			Config:   config,
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if got, expected := server.callCount("test_cached"), len(configs)+1; got != expected {
+		t.Errorf("expected %d ReadDataSource calls, got %d", expected, got)
+	}
+}
+
+func TestWithDataSourceCacheReplaceServer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newServer := func() *countingDataSourceServer {
+		return &countingDataSourceServer{
+			ProviderServer: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_cached": {},
+				},
+			},
+		}
+	}
+	server := newServer()
+	replacement := newServer()
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+		ctx,
+		[]tf6muxserver.ServerOption{tf6muxserver.WithDataSourceCache([]string{"test_cached"}, time.Hour)},
+		func() tfprotov6.ProviderServer { return server },
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	req := &tfprotov6.ReadDataSourceRequest{
+		TypeName: "test_cached",
+	}
+
+	if _, err := muxServer.ProviderServer().ReadDataSource(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := muxServer.ReplaceServer(0, func() tfprotov6.ProviderServer { return replacement }); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := muxServer.ProviderServer().ReadDataSource(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := replacement.callCount("test_cached"); got != 1 {
+		t.Errorf("expected 1 replacement ReadDataSource call, got %d", got)
+	}
+}
+
+func TestWithDataSourceCacheReroute(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	servers := []*countingDataSourceServer{
+		{
+			ProviderServer: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_cached": {},
+				},
+			},
+		},
+		{
+			ProviderServer: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_cached": {},
+				},
+			},
+		},
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+		ctx,
+		[]tf6muxserver.ServerOption{
+			tf6muxserver.WithConflictResolution(tf6muxserver.ConflictResolutionFirstServer),
+			tf6muxserver.WithDataSourceCache([]string{"test_cached"}, time.Hour),
+		},
+		func() tfprotov6.ProviderServer { return servers[0] },
+		func() tfprotov6.ProviderServer { return servers[1] },
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	req := &tfprotov6.ReadDataSourceRequest{
+		TypeName: "test_cached",
+	}
+
+	if _, err := muxServer.ProviderServer().ReadDataSource(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := muxServer.Reroute("test_cached", 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := muxServer.ProviderServer().ReadDataSource(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := servers[1].callCount("test_cached"); got != 1 {
+		t.Errorf("expected 1 rerouted server ReadDataSource call, got %d", got)
+	}
+}
@@ -0,0 +1,57 @@
+package tf6muxserver
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// checkDeprecationHints returns an error if a resource type configured via
+// the WithDeprecationHint option, or its replacement resource type, is not
+// implemented by any server.
+func (s muxServer) checkDeprecationHints() error {
+	oldTypes := make([]string, 0, len(s.options.deprecationHints))
+
+	for oldType := range s.options.deprecationHints {
+		oldTypes = append(oldTypes, oldType)
+	}
+
+	sort.Strings(oldTypes)
+
+	for _, oldType := range oldTypes {
+		newType := s.options.deprecationHints[oldType]
+
+		if _, ok := s.resourceSchemas[oldType]; !ok {
+			return fmt.Errorf("unable to add deprecation hint for resource %q: type isn't supported by any servers", oldType)
+		}
+
+		if _, ok := s.resourceSchemas[newType]; !ok {
+			return fmt.Errorf("unable to add deprecation hint for resource %q: replacement resource %q isn't supported by any servers", oldType, newType)
+		}
+	}
+
+	return nil
+}
+
+// deprecationHintDiagnostics returns the Diagnostics with a warning
+// Diagnostic appended, pointing to the replacement resource type configured
+// via the WithDeprecationHint option for the resource type. The given
+// Diagnostics are not modified.
+func (s muxServer) deprecationHintDiagnostics(typeName string, diags []*tfprotov6.Diagnostic) []*tfprotov6.Diagnostic {
+	newType, ok := s.options.deprecationHints[typeName]
+
+	if !ok {
+		return diags
+	}
+
+	result := make([]*tfprotov6.Diagnostic, 0, len(diags)+1)
+	result = append(result, diags...)
+
+	return append(result, &tfprotov6.Diagnostic{
+		Severity: tfprotov6.DiagnosticSeverityWarning,
+		Summary:  "Deprecated Resource Type",
+		Detail: fmt.Sprintf("The resource type %q is deprecated and replaced by the resource type %q. ", typeName, newType) +
+			"Migrate configurations to the replacement resource type, as the deprecated resource type may be removed in a future version.",
+	})
+}
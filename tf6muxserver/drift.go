@@ -0,0 +1,146 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// DriftReport is a change in the resource and data source types declared by
+// a server since muxed server creation, returned by CheckDrift. Type names
+// are sorted.
+type DriftReport struct {
+	// ServerIndex is the index of the server, in the order given to
+	// NewMuxServer, excluding servers excluded via the WithBestEffortSchema
+	// option.
+	ServerIndex int
+
+	// AddedResources are the managed resource types now declared by the
+	// server which are not part of the muxed server schemas.
+	AddedResources []string
+
+	// RemovedResources are the managed resource types routed to the server
+	// which it no longer declares.
+	RemovedResources []string
+
+	// AddedDataSources are the data source types now declared by the server
+	// which are not part of the muxed server schemas.
+	AddedDataSources []string
+
+	// RemovedDataSources are the data source types routed to the server
+	// which it no longer declares.
+	RemovedDataSources []string
+}
+
+// Diagnostic returns a warning Diagnostic describing the drift.
+func (r DriftReport) Diagnostic() *tfprotov6.Diagnostic {
+	var changes []string
+
+	for _, resourceType := range r.AddedResources {
+		changes = append(changes, fmt.Sprintf("added resource %q", resourceType))
+	}
+
+	for _, resourceType := range r.RemovedResources {
+		changes = append(changes, fmt.Sprintf("removed resource %q", resourceType))
+	}
+
+	for _, dataSourceType := range r.AddedDataSources {
+		changes = append(changes, fmt.Sprintf("added data source %q", dataSourceType))
+	}
+
+	for _, dataSourceType := range r.RemovedDataSources {
+		changes = append(changes, fmt.Sprintf("removed data source %q", dataSourceType))
+	}
+
+	return &tfprotov6.Diagnostic{
+		Severity: tfprotov6.DiagnosticSeverityWarning,
+		Summary:  "Provider Schema Drift",
+		Detail: fmt.Sprintf("Server %d changed its schema after the provider started, so requests for the following types may be routed incorrectly. ", r.ServerIndex) +
+			"This is always an issue in the provider implementation and should be reported to the provider developers.\n\n  - " + strings.Join(changes, "\n  - "),
+	}
+}
+
+// CheckDrift retrieves the schema of each server and compares its resource
+// and data source types against the routing of the muxed server, such as for
+// servers whose schemas can change after muxed server creation. A report is
+// returned, and a warning logged, for each server which declares types that
+// are not part of the muxed server schemas or no longer declares types routed
+// to it. Types which the server declares but which are routed to another
+// server, such as via the WithConflictResolution option, are not drift. An
+// error is returned if any server fails to return its schema.
+func (s muxServer) CheckDrift(ctx context.Context) ([]DriftReport, error) {
+	ctx = logging.InitContext(ctx)
+
+	var reports []DriftReport
+
+	for serverIndex, server := range s.currentServers() {
+		ctx = logging.Tfprotov6ProviderServerContext(ctx, server)
+
+		resp, err := getServerSchema(ctx, server)
+
+		if err != nil {
+			return nil, fmt.Errorf("error checking schema drift of server %d: %w", serverIndex, err)
+		}
+
+		if namespace := s.serverNamespace(serverIndex); namespace != "" {
+			resp = namespacedSchemaResponse(resp, namespace)
+		}
+
+		s.routingMu.RLock()
+		addedResources, removedResources := driftedTypeNames(serverIndex, resp.ResourceSchemas, s.resourceSchemas, s.resourceServerIndex)
+		addedDataSources, removedDataSources := driftedTypeNames(serverIndex, resp.DataSourceSchemas, s.dataSourceSchemas, s.dataSourceServerIndex)
+		s.routingMu.RUnlock()
+
+		if len(addedResources) == 0 && len(removedResources) == 0 && len(addedDataSources) == 0 && len(removedDataSources) == 0 {
+			continue
+		}
+
+		logging.MuxWarn(ctx, "server schema drifted since muxed server creation", map[string]interface{}{
+			logging.KeyTfMuxServerIndex:     serverIndex,
+			logging.KeyTfMuxResourceTypes:   append(addedResources, removedResources...),
+			logging.KeyTfMuxDataSourceTypes: append(addedDataSources, removedDataSources...),
+		})
+
+		reports = append(reports, DriftReport{
+			ServerIndex:        serverIndex,
+			AddedResources:     addedResources,
+			RemovedResources:   removedResources,
+			AddedDataSources:   addedDataSources,
+			RemovedDataSources: removedDataSources,
+		})
+	}
+
+	return reports, nil
+}
+
+// driftedTypeNames returns the sorted type names declared by the server which
+// are not muxed, and the sorted type names routed to the server which it no
+// longer declares.
+func driftedTypeNames(serverIndex int, declared map[string]*tfprotov6.Schema, muxed map[string]*tfprotov6.Schema, serverIndexes map[string]int) ([]string, []string) {
+	var added, removed []string
+
+	for typeName := range declared {
+		if _, ok := muxed[typeName]; !ok {
+			added = append(added, typeName)
+		}
+	}
+
+	for typeName, index := range serverIndexes {
+		if index != serverIndex {
+			continue
+		}
+
+		if _, ok := declared[typeName]; !ok {
+			removed = append(removed, typeName)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
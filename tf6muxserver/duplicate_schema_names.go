@@ -0,0 +1,104 @@
+package tf6muxserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// checkDuplicateSchemaNames returns an error, enabled via the
+// WithDuplicateSchemaNameValidation option, if any block of the muxed server
+// schemas declares an attribute or nested block name more than once.
+// Attributes and nested blocks of the same block share a namespace.
+func (s muxServer) checkDuplicateSchemaNames() error {
+	var problems []string
+
+	for _, name := range duplicateSchemaBlockNames("", schemaBlock(s.providerSchema)) {
+		problems = append(problems, fmt.Sprintf("provider schema declares %q more than once", name))
+	}
+
+	for _, name := range duplicateSchemaBlockNames("", schemaBlock(s.providerMetaSchema)) {
+		problems = append(problems, fmt.Sprintf("provider meta schema declares %q more than once", name))
+	}
+
+	for _, resourceType := range sortedSchemaTypeNames(s.resourceSchemas) {
+		for _, name := range duplicateSchemaBlockNames("", schemaBlock(s.resourceSchemas[resourceType])) {
+			problems = append(problems, fmt.Sprintf("resource %q schema declares %q more than once", resourceType, name))
+		}
+	}
+
+	for _, dataSourceType := range sortedSchemaTypeNames(s.dataSourceSchemas) {
+		for _, name := range duplicateSchemaBlockNames("", schemaBlock(s.dataSourceSchemas[dataSourceType])) {
+			problems = append(problems, fmt.Sprintf("data source %q schema declares %q more than once", dataSourceType, name))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errors.New("duplicate schema names, which Terraform rejects: " + strings.Join(problems, "; "))
+}
+
+// duplicateSchemaBlockNames returns the attribute and nested block names
+// declared more than once within the block, its nested blocks, or its nested
+// attributes, such as "feature.enabled".
+func duplicateSchemaBlockNames(prefix string, block *tfprotov6.SchemaBlock) []string {
+	if block == nil {
+		return nil
+	}
+
+	seen := make(map[string]int)
+	result := duplicateSchemaAttributeNames(prefix, block.Attributes, seen)
+
+	for _, nestedBlock := range block.BlockTypes {
+		if nestedBlock == nil {
+			continue
+		}
+
+		seen[nestedBlock.TypeName]++
+
+		if seen[nestedBlock.TypeName] == 2 {
+			result = append(result, prefix+nestedBlock.TypeName)
+		}
+
+		result = append(result, duplicateSchemaBlockNames(prefix+nestedBlock.TypeName+".", nestedBlock.Block)...)
+	}
+
+	return result
+}
+
+// duplicateSchemaAttributeNames returns the attribute names declared more
+// than once, counted in seen, or within nested attributes.
+func duplicateSchemaAttributeNames(prefix string, attributes []*tfprotov6.SchemaAttribute, seen map[string]int) []string {
+	var result []string
+
+	for _, attribute := range attributes {
+		if attribute == nil {
+			continue
+		}
+
+		seen[attribute.Name]++
+
+		if seen[attribute.Name] == 2 {
+			result = append(result, prefix+attribute.Name)
+		}
+
+		if attribute.NestedType != nil {
+			result = append(result, duplicateSchemaAttributeNames(prefix+attribute.Name+".", attribute.NestedType.Attributes, make(map[string]int))...)
+		}
+	}
+
+	return result
+}
+
+// schemaBlock returns the block of the schema, or nil if the schema is nil.
+func schemaBlock(schema *tfprotov6.Schema) *tfprotov6.SchemaBlock {
+	if schema == nil {
+		return nil
+	}
+
+	return schema.Block
+}
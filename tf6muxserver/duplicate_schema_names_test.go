@@ -0,0 +1,212 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithDuplicateSchemaNameValidation(t *testing.T) {
+	t.Parallel()
+
+	attribute := func(name string) *tfprotov6.SchemaAttribute {
+		return &tfprotov6.SchemaAttribute{
+			Name:     name,
+			Type:     tftypes.String,
+			Optional: true,
+		}
+	}
+
+	testCases := map[string]struct {
+		enabled       bool
+		servers       []func() tfprotov6.ProviderServer
+		expectedError string
+	}{
+		"disabled": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {
+							Block: &tfprotov6.SchemaBlock{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									attribute("id"),
+									attribute("id"),
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+		"duplicate-attribute": {
+			enabled: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {
+							Block: &tfprotov6.SchemaBlock{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									attribute("id"),
+									attribute("name"),
+									attribute("id"),
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `duplicate schema names, which Terraform rejects: resource "test_resource" schema declares "id" more than once`,
+		},
+		"duplicate-attribute-and-block": {
+			enabled: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {
+							Block: &tfprotov6.SchemaBlock{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									attribute("filter"),
+								},
+								BlockTypes: []*tfprotov6.SchemaNestedBlock{
+									{
+										TypeName: "filter",
+										Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+										Block:    &tfprotov6.SchemaBlock{},
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `duplicate schema names, which Terraform rejects: data source "test_data_source" schema declares "filter" more than once`,
+		},
+		"duplicate-nested-block-attribute": {
+			enabled: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeSingle,
+									Block: &tfprotov6.SchemaBlock{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											attribute("enabled"),
+											attribute("enabled"),
+										},
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {
+							Block: &tfprotov6.SchemaBlock{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									attribute("id"),
+									attribute("id"),
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `duplicate schema names, which Terraform rejects: provider schema declares "feature.enabled" more than once; resource "test_resource" schema declares "id" more than once`,
+		},
+		"duplicate-nested-attribute": {
+			enabled: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {
+							Block: &tfprotov6.SchemaBlock{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									{
+										Name: "setting",
+										NestedType: &tfprotov6.SchemaObject{
+											Nesting: tfprotov6.SchemaObjectNestingModeSingle,
+											Attributes: []*tfprotov6.SchemaAttribute{
+												attribute("name"),
+												attribute("name"),
+											},
+										},
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `duplicate schema names, which Terraform rejects: resource "test_resource" schema declares "setting.name" more than once`,
+		},
+		"unique": {
+			enabled: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {
+							Block: &tfprotov6.SchemaBlock{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									attribute("id"),
+									attribute("name"),
+								},
+								BlockTypes: []*tfprotov6.SchemaNestedBlock{
+									{
+										TypeName: "setting",
+										Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+										Block: &tfprotov6.SchemaBlock{
+											Attributes: []*tfprotov6.SchemaAttribute{
+												attribute("name"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tf6muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf6muxserver.ServerOption{
+					tf6muxserver.WithDuplicateSchemaNameValidation(testCase.enabled),
+				},
+				testCase.servers...,
+			)
+
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if err.Error() != testCase.expectedError {
+					t.Fatalf("expected error %q, got %q", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+		})
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
-// dynamicValueEquals performs equality checking of DynamicValue.
+// dynamicValueEquals performs equality checking of DynamicValue. Values are
+// unmarshalled with the schema type before comparison, so a JSON encoded
+// value and a MessagePack encoded value of the same data are equal.
 func dynamicValueEquals(schemaType tftypes.Type, i *tfprotov6.DynamicValue, j *tfprotov6.DynamicValue) (bool, error) {
 	if i == nil {
 		return j == nil, nil
@@ -94,6 +94,121 @@ func TestDynamicValueEquals(t *testing.T) {
 			expected:      false,
 			expectedError: fmt.Errorf("unable to unmarshal DynamicValue: missing Type"),
 		},
+		"JSON-different-value": {
+			schemaType: tftypes.Object{
+				AttributeTypes: map[string]tftypes.Type{
+					"test_string_attribute": tftypes.String,
+				},
+			},
+			dynamicValue1: func() (*tfprotov6.DynamicValue, error) {
+				return &tfprotov6.DynamicValue{
+					JSON: []byte(`{"test_string_attribute":"test-value-1"}`),
+				}, nil
+			},
+			dynamicValue2: func() (*tfprotov6.DynamicValue, error) {
+				return &tfprotov6.DynamicValue{
+					JSON: []byte(`{"test_string_attribute":"test-value-2"}`),
+				}, nil
+			},
+			expected: false,
+		},
+		"JSON-equal-value": {
+			schemaType: tftypes.Object{
+				AttributeTypes: map[string]tftypes.Type{
+					"test_number_attribute": tftypes.Number,
+					"test_string_attribute": tftypes.String,
+				},
+			},
+			dynamicValue1: func() (*tfprotov6.DynamicValue, error) {
+				return &tfprotov6.DynamicValue{
+					JSON: []byte(`{"test_number_attribute":1.0,"test_string_attribute":"test-value"}`),
+				}, nil
+			},
+			dynamicValue2: func() (*tfprotov6.DynamicValue, error) {
+				return &tfprotov6.DynamicValue{
+					JSON: []byte(`{ "test_string_attribute": "test-value", "test_number_attribute": 1 }`),
+				}, nil
+			},
+			expected: true,
+		},
+		"JSON-MsgPack-different-value": {
+			schemaType: tftypes.Object{
+				AttributeTypes: map[string]tftypes.Type{
+					"test_string_attribute": tftypes.String,
+				},
+			},
+			dynamicValue1: func() (*tfprotov6.DynamicValue, error) {
+				return &tfprotov6.DynamicValue{
+					JSON: []byte(`{"test_string_attribute":"test-value-1"}`),
+				}, nil
+			},
+			dynamicValue2: func() (*tfprotov6.DynamicValue, error) {
+				dv, err := tfprotov6.NewDynamicValue(
+					tftypes.Object{
+						AttributeTypes: map[string]tftypes.Type{
+							"test_string_attribute": tftypes.String,
+						},
+					},
+					tftypes.NewValue(
+						tftypes.Object{
+							AttributeTypes: map[string]tftypes.Type{
+								"test_string_attribute": tftypes.String,
+							},
+						},
+						map[string]tftypes.Value{
+							"test_string_attribute": tftypes.NewValue(tftypes.String, "test-value-2"),
+						},
+					),
+				)
+				return &dv, err
+			},
+			expected: false,
+		},
+		"JSON-MsgPack-equal-value": {
+			schemaType: tftypes.Object{
+				AttributeTypes: map[string]tftypes.Type{
+					"test_list_attribute": tftypes.List{
+						ElementType: tftypes.String,
+					},
+					"test_string_attribute": tftypes.String,
+				},
+			},
+			dynamicValue1: func() (*tfprotov6.DynamicValue, error) {
+				return &tfprotov6.DynamicValue{
+					JSON: []byte(`{"test_list_attribute":["a","b"],"test_string_attribute":"test-value"}`),
+				}, nil
+			},
+			dynamicValue2: func() (*tfprotov6.DynamicValue, error) {
+				objectType := tftypes.Object{
+					AttributeTypes: map[string]tftypes.Type{
+						"test_list_attribute": tftypes.List{
+							ElementType: tftypes.String,
+						},
+						"test_string_attribute": tftypes.String,
+					},
+				}
+				dv, err := tfprotov6.NewDynamicValue(
+					objectType,
+					tftypes.NewValue(
+						objectType,
+						map[string]tftypes.Value{
+							"test_list_attribute": tftypes.NewValue(
+								tftypes.List{
+									ElementType: tftypes.String,
+								},
+								[]tftypes.Value{
+									tftypes.NewValue(tftypes.String, "a"),
+									tftypes.NewValue(tftypes.String, "b"),
+								},
+							),
+							"test_string_attribute": tftypes.NewValue(tftypes.String, "test-value"),
+						},
+					),
+				)
+				return &dv, err
+			},
+			expected: true,
+		},
 		"mismatched-type": {
 			schemaType: tftypes.Object{
 				AttributeTypes: map[string]tftypes.Type{
@@ -0,0 +1,214 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// schemaDynamicValue is a DynamicValue field of a request or response and
+// the schema used to decode it.
+type schemaDynamicValue struct {
+	// field is the request or response field name, such as "PriorState".
+	field string
+
+	schema *tfprotov6.Schema
+	value  *tfprotov6.DynamicValue
+}
+
+// dynamicValueRoundTripMiddleware validates that each DynamicValue of the
+// request and response survives decoding with its schema type, encoding, and
+// decoding again unchanged, logging an error for each mismatch.
+func (s muxServer) dynamicValueRoundTripMiddleware(next RPCHandler) RPCHandler {
+	return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+		typeNameKey, typeName, values := s.requestDynamicValues(req)
+
+		validateDynamicValueRoundTrips(ctx, "request", typeNameKey, typeName, values)
+
+		resp, err := next(ctx, rpc, req)
+
+		validateDynamicValueRoundTrips(ctx, "response", typeNameKey, typeName, s.responseDynamicValues(typeName, resp))
+
+		return resp, err
+	}
+}
+
+// requestDynamicValues returns the logging key and value of the request type
+// name, if any, and the DynamicValue fields of the request with their schemas.
+func (s muxServer) requestDynamicValues(req interface{}) (string, string, []schemaDynamicValue) {
+	switch req := req.(type) {
+	case *tfprotov6.ApplyResourceChangeRequest:
+		schema := s.resourceSchemas[req.TypeName]
+
+		return logging.KeyTfResourceType, req.TypeName, []schemaDynamicValue{
+			{field: "PriorState", schema: schema, value: req.PriorState},
+			{field: "PlannedState", schema: schema, value: req.PlannedState},
+			{field: "Config", schema: schema, value: req.Config},
+			{field: "ProviderMeta", schema: s.providerMetaSchema, value: req.ProviderMeta},
+		}
+	case *tfprotov6.ConfigureProviderRequest:
+		return "", "", []schemaDynamicValue{
+			{field: "Config", schema: s.providerSchema, value: req.Config},
+		}
+	case *tfprotov6.PlanResourceChangeRequest:
+		schema := s.resourceSchemas[req.TypeName]
+
+		return logging.KeyTfResourceType, req.TypeName, []schemaDynamicValue{
+			{field: "PriorState", schema: schema, value: req.PriorState},
+			{field: "ProposedNewState", schema: schema, value: req.ProposedNewState},
+			{field: "Config", schema: schema, value: req.Config},
+			{field: "ProviderMeta", schema: s.providerMetaSchema, value: req.ProviderMeta},
+		}
+	case *tfprotov6.ValidateProviderConfigRequest:
+		return "", "", []schemaDynamicValue{
+			{field: "Config", schema: s.providerSchema, value: req.Config},
+		}
+	case *tfprotov6.ReadDataSourceRequest:
+		return logging.KeyTfDataSourceType, req.TypeName, []schemaDynamicValue{
+			{field: "Config", schema: s.dataSourceSchemas[req.TypeName], value: req.Config},
+			{field: "ProviderMeta", schema: s.providerMetaSchema, value: req.ProviderMeta},
+		}
+	case *tfprotov6.ReadResourceRequest:
+		return logging.KeyTfResourceType, req.TypeName, []schemaDynamicValue{
+			{field: "CurrentState", schema: s.resourceSchemas[req.TypeName], value: req.CurrentState},
+			{field: "ProviderMeta", schema: s.providerMetaSchema, value: req.ProviderMeta},
+		}
+	case *tfprotov6.ValidateDataResourceConfigRequest:
+		return logging.KeyTfDataSourceType, req.TypeName, []schemaDynamicValue{
+			{field: "Config", schema: s.dataSourceSchemas[req.TypeName], value: req.Config},
+		}
+	case *tfprotov6.ValidateResourceConfigRequest:
+		return logging.KeyTfResourceType, req.TypeName, []schemaDynamicValue{
+			{field: "Config", schema: s.resourceSchemas[req.TypeName], value: req.Config},
+		}
+	case *tfprotov6.ImportResourceStateRequest:
+		return logging.KeyTfResourceType, req.TypeName, nil
+	case *tfprotov6.UpgradeResourceStateRequest:
+		return logging.KeyTfResourceType, req.TypeName, nil
+	}
+
+	return "", "", nil
+}
+
+// responseDynamicValues returns the DynamicValue fields of the response with
+// their schemas, using the type name of the request.
+func (s muxServer) responseDynamicValues(typeName string, resp interface{}) []schemaDynamicValue {
+	switch resp := resp.(type) {
+	case *tfprotov6.ApplyResourceChangeResponse:
+		if resp != nil {
+			return []schemaDynamicValue{
+				{field: "NewState", schema: s.resourceSchemas[typeName], value: resp.NewState},
+			}
+		}
+	case *tfprotov6.ImportResourceStateResponse:
+		if resp != nil {
+			var values []schemaDynamicValue
+
+			for _, importedResource := range resp.ImportedResources {
+				if importedResource == nil {
+					continue
+				}
+
+				values = append(values, schemaDynamicValue{
+					field:  "ImportedResources.State",
+					schema: s.resourceSchemas[importedResource.TypeName],
+					value:  importedResource.State,
+				})
+			}
+
+			return values
+		}
+	case *tfprotov6.PlanResourceChangeResponse:
+		if resp != nil {
+			return []schemaDynamicValue{
+				{field: "PlannedState", schema: s.resourceSchemas[typeName], value: resp.PlannedState},
+			}
+		}
+	case *tfprotov6.ValidateProviderConfigResponse:
+		if resp != nil {
+			return []schemaDynamicValue{
+				{field: "PreparedConfig", schema: s.providerSchema, value: resp.PreparedConfig},
+			}
+		}
+	case *tfprotov6.ReadDataSourceResponse:
+		if resp != nil {
+			return []schemaDynamicValue{
+				{field: "State", schema: s.dataSourceSchemas[typeName], value: resp.State},
+			}
+		}
+	case *tfprotov6.ReadResourceResponse:
+		if resp != nil {
+			return []schemaDynamicValue{
+				{field: "NewState", schema: s.resourceSchemas[typeName], value: resp.NewState},
+			}
+		}
+	case *tfprotov6.UpgradeResourceStateResponse:
+		if resp != nil {
+			return []schemaDynamicValue{
+				{field: "UpgradedState", schema: s.resourceSchemas[typeName], value: resp.UpgradedState},
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateDynamicValueRoundTrips logs an error for each DynamicValue which
+// cannot be decoded with its schema type or which differs after being encoded
+// and decoded again. Nil DynamicValues are skipped.
+func validateDynamicValueRoundTrips(ctx context.Context, direction string, typeNameKey string, typeName string, values []schemaDynamicValue) {
+	for _, value := range values {
+		if value.value == nil {
+			continue
+		}
+
+		err := dynamicValueRoundTrip(value.schema, value.value)
+
+		if err == nil {
+			continue
+		}
+
+		fields := map[string]interface{}{
+			logging.KeyError:                  err.Error(),
+			logging.KeyTfMuxDynamicValueField: direction + " " + value.field,
+		}
+
+		if typeNameKey != "" {
+			fields[typeNameKey] = typeName
+		}
+
+		logging.MuxError(ctx, "DynamicValue failed round trip validation", fields)
+	}
+}
+
+// dynamicValueRoundTrip returns an error if the DynamicValue cannot be decoded
+// with the schema type or differs after being encoded and decoded again.
+func dynamicValueRoundTrip(schema *tfprotov6.Schema, value *tfprotov6.DynamicValue) error {
+	schemaType := schema.ValueType()
+
+	decoded, err := value.Unmarshal(schemaType)
+
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal DynamicValue: %w", err)
+	}
+
+	encoded, err := tfprotov6.NewDynamicValue(schemaType, decoded)
+
+	if err != nil {
+		return fmt.Errorf("unable to marshal DynamicValue: %w", err)
+	}
+
+	equal, err := dynamicValueEquals(schemaType, value, &encoded)
+
+	if err != nil {
+		return err
+	}
+
+	if !equal {
+		return fmt.Errorf("DynamicValue changed after being marshaled and unmarshaled again")
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package tf6muxserver_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklogtest"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithDynamicValueRoundTripValidation(t *testing.T) {
+	t.Parallel()
+
+	schema := &tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name:     "id",
+					Type:     tftypes.String,
+					Computed: true,
+				},
+			},
+		},
+	}
+	schemaType := schema.ValueType()
+
+	validState, err := tfprotov6.NewDynamicValue(schemaType, tftypes.NewValue(schemaType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "test-id"),
+	}))
+
+	if err != nil {
+		t.Fatalf("unable to create state: %s", err)
+	}
+
+	invalidState := tfprotov6.DynamicValue{
+		// 0xc1 is never used in MessagePack.
+		MsgPack: []byte{0xc1},
+	}
+
+	testCases := map[string]struct {
+		enabled         bool
+		currentState    *tfprotov6.DynamicValue
+		expectedEntries []map[string]interface{}
+	}{
+		"disabled-invalid": {
+			currentState:    &invalidState,
+			expectedEntries: []map[string]interface{}{},
+		},
+		"enabled-invalid": {
+			enabled:      true,
+			currentState: &invalidState,
+			expectedEntries: []map[string]interface{}{
+				{
+					"@level":                     "error",
+					"@message":                   "DynamicValue failed round trip validation",
+					"@module":                    "sdk.mux",
+					"tf_mux_dynamic_value_field": "request CurrentState",
+					"tf_mux_provider":            "*tf6testserver.TestServer",
+					"tf_resource_type":           "test_resource",
+					"tf_rpc":                     "ReadResource",
+				},
+			},
+		},
+		"enabled-valid": {
+			enabled:         true,
+			currentState:    &validState,
+			expectedEntries: []map[string]interface{}{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			ctx := tfsdklogtest.RootLogger(context.Background(), &output)
+
+			server := &tf6testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": schema,
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{tf6muxserver.WithDynamicValueRoundTripValidation(testCase.enabled)}, server.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+				CurrentState: testCase.currentState,
+				TypeName:     "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !server.ReadResourceCalled["test_resource"] {
+				t.Errorf("expected test_resource ReadResource to be called")
+			}
+
+			entries, err := tfsdklogtest.MultilineJSONDecode(&output)
+
+			if err != nil {
+				t.Fatalf("unable to read log entries: %s", err)
+			}
+
+			gotEntries := []map[string]interface{}{}
+
+			for _, entry := range entries {
+				if entry["@message"] != "DynamicValue failed round trip validation" {
+					continue
+				}
+
+				if _, ok := entry["error"]; !ok {
+					t.Errorf("expected error field in log entry: %v", entry)
+				}
+
+				delete(entry, "@caller")
+				delete(entry, "@timestamp")
+				delete(entry, "tf_mux_request_id")
+				delete(entry, "tf_mux_version")
+				delete(entry, "error")
+
+				gotEntries = append(gotEntries, entry)
+			}
+
+			if diff := cmp.Diff(gotEntries, testCase.expectedEntries); diff != "" {
+				t.Errorf("unexpected log entries: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package tf6muxserver
+
+import (
+	"context"
+)
+
+// fanOutLimiter is a semaphore bounding the number of downstream server calls
+// made simultaneously by fan-out operations, such as Warmup, which call every
+// server concurrently. It is shared across all requests to the muxServer. A
+// nil fanOutLimiter does not limit calls.
+type fanOutLimiter chan struct{}
+
+func newFanOutLimiter(maxConcurrency int) fanOutLimiter {
+	return make(fanOutLimiter, maxConcurrency)
+}
+
+// acquire blocks until a downstream server call is permitted or the context
+// is done. Each successful acquire must be followed by a release.
+func (l fanOutLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release permits another downstream server call.
+func (l fanOutLimiter) release() {
+	if l == nil {
+		return
+	}
+
+	<-l
+}
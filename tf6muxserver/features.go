@@ -0,0 +1,111 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// Feature is a group of RPCs which a server can advertise handling for type
+// names which no server declared, via the FeatureProvider interface.
+type Feature string
+
+const (
+	// FeatureImport is the ImportResourceState RPC.
+	FeatureImport Feature = "import"
+)
+
+// FeatureProvider is an optional interface which servers can implement to
+// advertise the features they handle for type names which no server
+// declared, such as a server handling all import operations. When the type
+// name of a request for a feature is not declared by any server, the request
+// is routed to the first server, in the order given to NewMuxServer, which
+// advertises the feature.
+type FeatureProvider interface {
+	// Features returns the features handled by the server.
+	Features() []Feature
+}
+
+// featureServer returns the first server which implements FeatureProvider
+// and advertises the feature.
+func (s muxServer) featureServer(ctx context.Context, feature Feature) (tfprotov6.ProviderServer, bool) {
+	for serverIndex, server := range s.currentServers() {
+		featureProvider, ok := server.(FeatureProvider)
+
+		if !ok {
+			continue
+		}
+
+		for _, serverFeature := range featureProvider.Features() {
+			if serverFeature != feature {
+				continue
+			}
+
+			logging.MuxTrace(ctx, "routing to server advertising feature", map[string]interface{}{
+				logging.KeyTfMuxServerIndex: serverIndex,
+			})
+
+			return server, true
+		}
+	}
+
+	return nil, false
+}
+
+// featureServerOverlapDiagnostics returns a warning Diagnostic for each
+// resource or data source type declared by a server implementing
+// FeatureProvider, as given by its schemas keyed by routing index, which is
+// routed to another server.
+func (s muxServer) featureServerOverlapDiagnostics(ctx context.Context, featureServerSchemas map[int]*tfprotov6.GetProviderSchemaResponse) []*tfprotov6.Diagnostic {
+	serverIndexes := make([]int, 0, len(featureServerSchemas))
+
+	for serverIndex := range featureServerSchemas {
+		serverIndexes = append(serverIndexes, serverIndex)
+	}
+
+	sort.Ints(serverIndexes)
+
+	var diags []*tfprotov6.Diagnostic
+
+	for _, serverIndex := range serverIndexes {
+		resp := featureServerSchemas[serverIndex]
+
+		diags = append(diags, s.featureServerOverlapTypeDiagnostics(ctx, "resource", logging.KeyTfResourceType, serverIndex, resp.ResourceSchemas, s.resourceServerIndex)...)
+		diags = append(diags, s.featureServerOverlapTypeDiagnostics(ctx, "data source", logging.KeyTfDataSourceType, serverIndex, resp.DataSourceSchemas, s.dataSourceServerIndex)...)
+	}
+
+	return diags
+}
+
+// featureServerOverlapTypeDiagnostics returns a warning Diagnostic for each
+// type in the schemas declared by the feature server which is routed to
+// another server.
+func (s muxServer) featureServerOverlapTypeDiagnostics(ctx context.Context, kind string, logKey string, serverIndex int, schemas map[string]*tfprotov6.Schema, serverIndexes map[string]int) []*tfprotov6.Diagnostic {
+	var diags []*tfprotov6.Diagnostic
+
+	for _, typeName := range sortedSchemaTypeNames(schemas) {
+		ownerIndex, ok := serverIndexes[typeName]
+
+		if !ok || ownerIndex == serverIndex {
+			continue
+		}
+
+		logging.MuxWarn(ctx, "feature server declares type routed to another server", map[string]interface{}{
+			logging.KeyTfMuxServerIndex: serverIndex,
+			logKey:                      typeName,
+		})
+
+		diags = append(diags, &tfprotov6.Diagnostic{
+			Severity: tfprotov6.DiagnosticSeverityWarning,
+			Summary:  "Feature Server Declares Overlapping Type",
+			Detail: fmt.Sprintf("Server %d (%T) advertises features for undeclared type names and declares the %s type %q, "+
+				"which is routed to server %d (%T). The declaration of server %d is ignored.",
+				serverIndex, s.servers[serverIndex], kind, typeName, ownerIndex, s.servers[ownerIndex], serverIndex),
+		})
+	}
+
+	return diags
+}
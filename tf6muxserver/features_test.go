@@ -0,0 +1,201 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// importFeatureServer is a server which advertises handling all import
+// operations.
+type importFeatureServer struct {
+	*tf6testserver.TestServer
+}
+
+func (s importFeatureServer) Features() []tf6muxserver.Feature {
+	return []tf6muxserver.Feature{tf6muxserver.FeatureImport}
+}
+
+func TestMuxServerImportResourceStateFeatureProvider(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		typeName       string
+		featureServer  bool
+		expectedServer int
+		expectedError  bool
+	}{
+		"declared": {
+			typeName:       "test_resource_server1",
+			featureServer:  true,
+			expectedServer: 0,
+		},
+		"undeclared": {
+			typeName:       "test_resource_undeclared",
+			featureServer:  true,
+			expectedServer: 1,
+		},
+		"declared-data-source": {
+			typeName:      "test_data_source_server1",
+			featureServer: true,
+			expectedError: true,
+		},
+		"undeclared-no-feature-server": {
+			typeName:      "test_resource_undeclared",
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf6testserver.TestServer{
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source_server1": {},
+					},
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+				},
+				{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server2": {},
+					},
+				},
+			}
+
+			server2 := servers[1].ProviderServer
+
+			if testCase.featureServer {
+				server2 = func() tfprotov6.ProviderServer {
+					return importFeatureServer{
+						TestServer: servers[1],
+					}
+				}
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServer(context.Background(), servers[0].ProviderServer, server2)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: testCase.typeName,
+			})
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			if !servers[testCase.expectedServer].ImportResourceStateCalled[testCase.typeName] {
+				t.Errorf("expected server %d ImportResourceState to be called", testCase.expectedServer)
+			}
+		})
+	}
+}
+
+func TestWithFeatureServerOverlapWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled             bool
+		expectedDiagnostics []*tfprotov6.Diagnostic
+	}{
+		"disabled": {},
+		"enabled": {
+			enabled: true,
+			expectedDiagnostics: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityWarning,
+					Summary:  "Feature Server Declares Overlapping Type",
+					Detail: "Server 1 (tf6muxserver_test.importFeatureServer) advertises features for undeclared type names and declares the resource type \"test_resource\", " +
+						"which is routed to server 0 (*tf6testserver.TestServer). The declaration of server 1 is ignored.",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf6testserver.TestServer{
+				{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				},
+				{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource":         {},
+						"test_resource_server2": {},
+					},
+				},
+			}
+
+			featureServer := func() tfprotov6.ProviderServer {
+				return importFeatureServer{
+					TestServer: servers[1],
+				}
+			}
+
+			options := []tf6muxserver.ServerOption{
+				tf6muxserver.WithConflictResolution(tf6muxserver.ConflictResolutionFirstServer),
+				tf6muxserver.WithFeatureServerOverlapWarnings(testCase.enabled),
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), options, servers[0].ProviderServer, featureServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+
+			// The type owned by the first server is never routed to the
+			// feature server.
+			_, err = muxServer.ProviderServer().ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !servers[0].ImportResourceStateCalled["test_resource"] {
+				t.Errorf("expected server 0 ImportResourceState to be called")
+			}
+
+			if servers[1].ImportResourceStateCalled["test_resource"] {
+				t.Errorf("unexpected server 1 ImportResourceState call")
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package tf6muxserver
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
+	"google.golang.org/grpc"
+)
+
+// RegisterGRPCServer registers the muxed server as the tfplugin6.Provider
+// gRPC service of the given gRPC server, such as to serve the muxed server
+// in-process over a custom net.Listener for integration tests exercising the
+// real gRPC path. The name is the provider name used in logging, as given to
+// tf6server.Serve, and the options configure the service in the same manner
+// as for tf6server.Serve, such as tf6server.WithLoggingSink. Requests are
+// served by ProviderServer, honoring the WithServerReuse option.
+//
+// Unlike tf6server.Serve, no go-plugin handshake takes place: the magic
+// cookie, protocol version negotiation, and handshake output line are not
+// used, and the go-plugin broker, controller, and stdio services are not
+// registered. Clients must connect directly to the listener and call the
+// tfplugin6.Provider service, so Terraform CLI cannot use the server unless
+// it is served via tf6server.Serve. The caller owns the gRPC server,
+// including its listener, transport credentials, and shutdown.
+func (s muxServer) RegisterGRPCServer(grpcServer *grpc.Server, name string, opts ...tf6server.ServeOpt) error {
+	plugin := &tf6server.GRPCProviderPlugin{
+		GRPCProvider: s.ProviderServer,
+		Name:         name,
+		Opts:         opts,
+	}
+
+	return plugin.GRPCServer(nil, grpcServer)
+}
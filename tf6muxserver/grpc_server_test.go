@@ -0,0 +1,124 @@
+package tf6muxserver_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxservertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// rawCodec is a gRPC codec passing encoded protocol buffers messages through
+// unchanged, as the tfplugin6 message types are internal to
+// terraform-plugin-go.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return *v.(*[]byte), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*v.(*[]byte) = data
+
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+func TestMuxServerRegisterGRPCServer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	recordingServer := tf6muxservertest.NewRecordingServer(&tf6testserver.TestServer{
+		DataSourceSchemas: map[string]*tfprotov6.Schema{
+			"test_data_source": {},
+		},
+		ResourceSchemas: map[string]*tfprotov6.Schema{
+			"test_resource": {},
+		},
+		ValidateResourceConfigResponse: &tfprotov6.ValidateResourceConfigResponse{},
+	})
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, recordingServer.ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	grpcServer := grpc.NewServer()
+
+	if err := muxServer.RegisterGRPCServer(grpcServer, "test"); err != nil {
+		t.Fatalf("unexpected error registering gRPC server: %s", err)
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(
+		ctx,
+		"bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error connecting: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	var schemaResp []byte
+
+	err = conn.Invoke(ctx, "/tfplugin6.Provider/GetProviderSchema", &[]byte{}, &schemaResp, grpc.ForceCodec(rawCodec{}))
+
+	if err != nil {
+		t.Fatalf("unexpected GetProviderSchema error: %s", err)
+	}
+
+	for _, typeName := range []string{"test_data_source", "test_resource"} {
+		if !bytes.Contains(schemaResp, []byte(typeName)) {
+			t.Errorf("expected GetProviderSchema response to contain %q", typeName)
+		}
+	}
+
+	// ValidateResourceConfig.Request field 1 is type_name.
+	validateReq := protowire.AppendTag(nil, 1, protowire.BytesType)
+	validateReq = protowire.AppendString(validateReq, "test_resource")
+
+	var validateResp []byte
+
+	err = conn.Invoke(ctx, "/tfplugin6.Provider/ValidateResourceConfig", &validateReq, &validateResp, grpc.ForceCodec(rawCodec{}))
+
+	if err != nil {
+		t.Fatalf("unexpected ValidateResourceConfig error: %s", err)
+	}
+
+	req := recordingServer.LastValidateResourceConfigRequest()
+
+	if req == nil {
+		t.Fatalf("expected ValidateResourceConfig request to be routed to server")
+	}
+
+	if req.TypeName != "test_resource" {
+		t.Errorf("expected ValidateResourceConfig TypeName %q, got: %q", "test_resource", req.TypeName)
+	}
+}
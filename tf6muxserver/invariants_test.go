@@ -0,0 +1,157 @@
+package tf6muxserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+)
+
+func TestMuxServerValidateInvariants(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		corrupt       func(s *muxServer)
+		expectedError string
+	}{
+		"valid": {
+			corrupt: func(_ *muxServer) {},
+		},
+		"data-source-schema-not-routed": {
+			corrupt: func(s *muxServer) {
+				s.dataSourceSchemas["test_data_source_orphan"] = &tfprotov6.Schema{}
+			},
+			expectedError: `muxed server invariants violated: data source "test_data_source_orphan" has a schema but is not routed`,
+		},
+		"resource-routed-without-schema": {
+			corrupt: func(s *muxServer) {
+				delete(s.resourceSchemas, "test_resource")
+			},
+			expectedError: `muxed server invariants violated: resource "test_resource" is routed but has no schema`,
+		},
+		"resource-routed-without-server-index": {
+			corrupt: func(s *muxServer) {
+				delete(s.resourceServerIndex, "test_resource")
+			},
+			expectedError: `muxed server invariants violated: resource "test_resource" is routed but has no server index`,
+		},
+		"resource-server-index-out-of-range": {
+			corrupt: func(s *muxServer) {
+				s.resourceServerIndex["test_resource"] = 2
+			},
+			expectedError: `muxed server invariants violated: resource "test_resource" has server index 2, expected 0 to 1`,
+		},
+		"server-provider-schemas-mismatch": {
+			corrupt: func(s *muxServer) {
+				s.serverProviderSchemas = s.serverProviderSchemas[:1]
+			},
+			expectedError: `muxed server invariants violated: 1 server provider schemas for 2 servers`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := NewMuxServer(
+				context.Background(),
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			testCase.corrupt(&muxServer)
+
+			err = muxServer.validateInvariants()
+
+			if err == nil {
+				if testCase.expectedError != "" {
+					t.Fatalf("expected error: %s", testCase.expectedError)
+				}
+
+				return
+			}
+
+			if err.Error() != testCase.expectedError {
+				t.Errorf("expected error %q, got %q", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestMuxServerRoutingChangeValidateInvariants(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		change        func(s muxServer) error
+		expectedError string
+	}{
+		"ReplaceServer": {
+			change: func(s muxServer) error {
+				return s.ReplaceServer(1, s.serverFuncs[1])
+			},
+			expectedError: `unable to replace server 1: muxed server invariants violated: data source "test_data_source_orphan" has a schema but is not routed`,
+		},
+		"Reroute": {
+			change: func(s muxServer) error {
+				return s.Reroute("test_resource", 1)
+			},
+			expectedError: `unable to reroute "test_resource": muxed server invariants violated: data source "test_data_source_orphan" has a schema but is not routed`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := NewMuxServerWithOptions(
+				context.Background(),
+				[]ServerOption{
+					WithConflictResolution(ConflictResolutionFirstServer),
+				},
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			muxServer.dataSourceSchemas["test_data_source_orphan"] = &tfprotov6.Schema{}
+
+			err = testCase.change(muxServer)
+
+			if err == nil {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			if err.Error() != testCase.expectedError {
+				t.Errorf("expected error %q, got %q", testCase.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package tf6muxserver
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultLatencyBuckets are the histogram bucket upper bounds used by the
+// WithLatencyMetrics option if none are given.
+var defaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// LatencyMetrics is a snapshot of the downstream server call latencies of a
+// muxed server.
+type LatencyMetrics struct {
+	// Histograms are keyed by RPC name, such as "ReadResource", then by the
+	// resource or data source type name of the request. Calls of RPCs
+	// without a type name, such as ConfigureProvider, are keyed by the empty
+	// type name.
+	Histograms map[string]map[string]LatencyHistogram
+}
+
+// LatencyHistogram is the latency distribution of the downstream server
+// calls of an RPC and type name.
+type LatencyHistogram struct {
+	// Count is the number of calls.
+	Count int64
+
+	// Sum is the total latency of the calls.
+	Sum time.Duration
+
+	// Buckets are the cumulative call counts, sorted by upper bound. Calls
+	// exceeding the largest upper bound are only included in Count.
+	Buckets []LatencyBucket
+}
+
+// LatencyBucket is a histogram bucket.
+type LatencyBucket struct {
+	// UpperBound is the inclusive upper bound of the call latencies.
+	UpperBound time.Duration
+
+	// Count is the number of calls with latencies up to UpperBound.
+	Count int64
+}
+
+// latencyMetricsKey identifies a latency histogram.
+type latencyMetricsKey struct {
+	rpc      string
+	typeName string
+}
+
+// latencyHistogram records call latencies. Counts are updated atomically,
+// so recording does not lock.
+type latencyHistogram struct {
+	count   int64
+	sum     int64
+	buckets []int64
+}
+
+// latencyMetrics records downstream server call latencies. It is safe for
+// concurrent use.
+type latencyMetrics struct {
+	bounds     []time.Duration
+	histograms sync.Map
+}
+
+func newLatencyMetrics(bounds []time.Duration) *latencyMetrics {
+	if len(bounds) == 0 {
+		bounds = defaultLatencyBuckets
+	}
+
+	sortedBounds := make([]time.Duration, len(bounds))
+	copy(sortedBounds, bounds)
+
+	sort.Slice(sortedBounds, func(i, j int) bool {
+		return sortedBounds[i] < sortedBounds[j]
+	})
+
+	return &latencyMetrics{
+		bounds: sortedBounds,
+	}
+}
+
+// record adds the latency of a call of the RPC and type name.
+func (m *latencyMetrics) record(rpc string, typeName string, latency time.Duration) {
+	if m == nil {
+		return
+	}
+
+	key := latencyMetricsKey{
+		rpc:      rpc,
+		typeName: typeName,
+	}
+
+	value, ok := m.histograms.Load(key)
+
+	if !ok {
+		value, _ = m.histograms.LoadOrStore(key, &latencyHistogram{
+			buckets: make([]int64, len(m.bounds)),
+		})
+	}
+
+	histogram := value.(*latencyHistogram)
+
+	atomic.AddInt64(&histogram.count, 1)
+	atomic.AddInt64(&histogram.sum, int64(latency))
+
+	for i, bound := range m.bounds {
+		if latency <= bound {
+			atomic.AddInt64(&histogram.buckets[i], 1)
+		}
+	}
+}
+
+// middleware records the latency of each downstream server call.
+func (m *latencyMetrics) middleware(next RPCHandler) RPCHandler {
+	return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+		_, typeName, _, _ := requestPayloadSize(req)
+		start := time.Now()
+
+		resp, err := next(ctx, rpc, req)
+
+		m.record(rpc, typeName, time.Since(start))
+
+		return resp, err
+	}
+}
+
+// snapshot returns a copy of the current histograms. Counts recorded
+// concurrently with the snapshot may be partially included.
+func (m *latencyMetrics) snapshot() LatencyMetrics {
+	result := LatencyMetrics{
+		Histograms: make(map[string]map[string]LatencyHistogram),
+	}
+
+	if m == nil {
+		return result
+	}
+
+	m.histograms.Range(func(key, value interface{}) bool {
+		k := key.(latencyMetricsKey)
+		histogram := value.(*latencyHistogram)
+
+		snapshot := LatencyHistogram{
+			Count:   atomic.LoadInt64(&histogram.count),
+			Sum:     time.Duration(atomic.LoadInt64(&histogram.sum)),
+			Buckets: make([]LatencyBucket, len(m.bounds)),
+		}
+
+		for i, bound := range m.bounds {
+			snapshot.Buckets[i] = LatencyBucket{
+				UpperBound: bound,
+				Count:      atomic.LoadInt64(&histogram.buckets[i]),
+			}
+		}
+
+		if _, ok := result.Histograms[k.rpc]; !ok {
+			result.Histograms[k.rpc] = make(map[string]LatencyHistogram)
+		}
+
+		result.Histograms[k.rpc][k.typeName] = snapshot
+
+		return true
+	})
+
+	return result
+}
+
+// Metrics returns a snapshot of the downstream server call latencies since
+// the muxed server was created, if enabled via WithLatencyMetrics(). It is
+// safe to call concurrently with other methods.
+func (s muxServer) Metrics() LatencyMetrics {
+	return s.latencyMetrics.snapshot()
+}
@@ -0,0 +1,121 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithLatencyMetrics(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options         []tf6muxserver.ServerOption
+		expectedMetrics tf6muxserver.LatencyMetrics
+	}{
+		"disabled": {
+			expectedMetrics: tf6muxserver.LatencyMetrics{
+				Histograms: map[string]map[string]tf6muxserver.LatencyHistogram{},
+			},
+		},
+		"enabled": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithLatencyMetrics(2*time.Hour, time.Hour),
+			},
+			expectedMetrics: tf6muxserver.LatencyMetrics{
+				Histograms: map[string]map[string]tf6muxserver.LatencyHistogram{
+					"ReadDataSource": {
+						"test_data_source": {
+							Count: 1,
+							Buckets: []tf6muxserver.LatencyBucket{
+								{UpperBound: time.Hour, Count: 1},
+								{UpperBound: 2 * time.Hour, Count: 1},
+							},
+						},
+					},
+					"ReadResource": {
+						"test_resource": {
+							Count: 2,
+							Buckets: []tf6muxserver.LatencyBucket{
+								{UpperBound: time.Hour, Count: 2},
+								{UpperBound: 2 * time.Hour, Count: 2},
+							},
+						},
+					},
+					"StopProvider": {
+						"": {
+							Count: 2,
+							Buckets: []tf6muxserver.LatencyBucket{
+								{UpperBound: time.Hour, Count: 2},
+								{UpperBound: 2 * time.Hour, Count: 2},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			servers := []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, testCase.options, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+				TypeName: "test_data_source",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			for i := 0; i < 2; i++ {
+				_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+					TypeName: "test_resource",
+				})
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			_, err = muxServer.ProviderServer().StopProvider(ctx, &tfprotov6.StopProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			// Latencies are not deterministic.
+			if diff := cmp.Diff(muxServer.Metrics(), testCase.expectedMetrics, cmpopts.IgnoreFields(tf6muxserver.LatencyHistogram{}, "Sum")); diff != "" {
+				t.Errorf("unexpected metrics difference: %s", diff)
+			}
+		})
+	}
+}
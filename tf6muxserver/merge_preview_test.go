@@ -0,0 +1,119 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestPreviewMerge(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		servers             []*tf6testserver.TestServer
+		expectedResources   map[string]int
+		expectedDataSources map[string]int
+		expectedError       bool
+	}{
+		"clean": {
+			servers: []*tf6testserver.TestServer{
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source_server1": {},
+					},
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+				},
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source_server2": {},
+					},
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server2a": {},
+						"test_resource_server2b": {},
+					},
+				},
+			},
+			expectedResources: map[string]int{
+				"test_resource_server1":  0,
+				"test_resource_server2a": 1,
+				"test_resource_server2b": 1,
+			},
+			expectedDataSources: map[string]int{
+				"test_data_source_server1": 0,
+				"test_data_source_server2": 1,
+			},
+		},
+		"overlapping-data-sources": {
+			servers: []*tf6testserver.TestServer{
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+				},
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		"overlapping-resources": {
+			servers: []*tf6testserver.TestServer{
+				{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				},
+				{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := make([]func() tfprotov6.ProviderServer, 0, len(testCase.servers))
+
+			for _, server := range testCase.servers {
+				servers = append(servers, server.ProviderServer)
+			}
+
+			resources, dataSources, err := tf6muxserver.PreviewMerge(context.Background(), servers...)
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			if diff := cmp.Diff(resources, testCase.expectedResources); diff != "" {
+				t.Errorf("unexpected resources difference: %s", diff)
+			}
+
+			if diff := cmp.Diff(dataSources, testCase.expectedDataSources); diff != "" {
+				t.Errorf("unexpected data sources difference: %s", diff)
+			}
+		})
+	}
+}
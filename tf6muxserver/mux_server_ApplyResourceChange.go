@@ -11,18 +11,83 @@ import (
 // ApplyResourceChange calls the ApplyResourceChange method, passing `req`, on
 // the provider that returned the resource specified by req.TypeName in its
 // schema.
+//
+// If the WithDestroyLogging option is enabled, requests which destroy the
+// resource are logged at INFO level.
+//
+// If the resource type is enabled via the WithStateNormalization option and
+// the response NewState is semantically equal to req.PlannedState, the
+// planned state is returned as the new state instead.
+//
+// If the WithReadOnly option is enabled, an error Diagnostic is returned
+// without calling any server.
 func (s muxServer) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
 	rpc := "ApplyResourceChange"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	server, ok := s.resources[req.TypeName]
+	ctx = logging.RequestIdContext(ctx)
 
-	if !ok {
-		return nil, fmt.Errorf("%q isn't supported by any servers", req.TypeName)
+	if err := checkRequest(rpc, req, func(req *tfprotov6.ApplyResourceChangeRequest) string { return req.TypeName }); err != nil {
+		return nil, err
+	}
+
+	if s.options.readOnly {
+		logging.MuxDebug(ctx, "rejecting request in read-only mode")
+
+		return &tfprotov6.ApplyResourceChangeResponse{
+			Diagnostics: []*tfprotov6.Diagnostic{
+				readOnlyDiagnostic(rpc, req.TypeName),
+			},
+		}, nil
+	}
+
+	server, err := s.routeRequest(ctx, rpc, req.TypeName, req, s.resourceServer)
+
+	if err != nil {
+		return nil, err
 	}
 
 	ctx = logging.Tfprotov6ProviderServerContext(ctx, server)
+	ctx, cancel := s.rpcTimeoutContext(ctx, rpc, req.TypeName)
+	defer cancel()
+
+	if s.options.destroyLogging {
+		destroy, err := plannedStateIsNull(s.resourceSchemas[req.TypeName], req.PlannedState)
+
+		if err != nil {
+			logging.MuxDebug(ctx, "unable to determine whether planned state is null", map[string]interface{}{logging.KeyError: err.Error()})
+		}
+
+		if destroy {
+			logging.MuxInfo(ctx, "applying resource destroy", map[string]interface{}{logging.KeyTfResourceType: req.TypeName})
+		}
+	}
+
 	logging.MuxTrace(ctx, "calling downstream server")
 
-	return server.ApplyResourceChange(ctx, req)
+	resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.resourceNamespace(req.TypeName), providerMetaCall(s.resourceProviderMetaProjection(req.TypeName), server.ApplyResourceChange)))
+
+	if _, ok := s.options.stateNormalizationTypes[req.TypeName]; ok && err == nil && resp != nil {
+		normalized := *resp
+		normalized.NewState = s.normalizedState(ctx, req.TypeName, "response NewState", resp.NewState, req.PlannedState)
+		resp = &normalized
+	}
+
+	return resp, rpcTimeoutError(ctx, rpc, req.TypeName, err)
+}
+
+// plannedStateIsNull returns true if the planned state, decoded using the
+// resource schema type, is null, which signals the resource is destroyed.
+func plannedStateIsNull(schema *tfprotov6.Schema, plannedState *tfprotov6.DynamicValue) (bool, error) {
+	if plannedState == nil {
+		return false, nil
+	}
+
+	value, err := plannedState.Unmarshal(schema.ValueType())
+
+	if err != nil {
+		return false, fmt.Errorf("unable to unmarshal planned state: %w", err)
+	}
+
+	return value.IsNull(), nil
 }
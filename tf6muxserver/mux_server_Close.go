@@ -0,0 +1,38 @@
+package tf6muxserver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Close calls the Close method of each server associated with the muxServer
+// which implements io.Closer, releasing any resources held by the servers,
+// such as gRPC clients or file handles. All errors are joined together and
+// returned, but will not prevent the rest of the servers' Close methods from
+// being called.
+//
+// Close is intended for teardown by hosts embedding the muxServer and is
+// separate from StopProvider, which is called by Terraform.
+func (s muxServer) Close() error {
+	var errs []string
+
+	for _, server := range s.currentServers() {
+		closer, ok := server.(io.Closer)
+
+		if !ok {
+			continue
+		}
+
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("error closing %T: %s", server, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(errs, "\n"))
+}
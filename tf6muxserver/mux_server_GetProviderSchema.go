@@ -10,17 +10,27 @@ import (
 // GetProviderSchema merges the schemas returned by the
 // tfprotov6.ProviderServers associated with muxServer into a single schema.
 // Resources and data sources must be returned from only one server. Provider
-// and ProviderMeta schemas must be identical between all servers.
+// and ProviderMeta schemas must be identical between all servers. The
+// PlanDestroy server capability is only set if every server with resources
+// supports it. Servers excluded by the WithBestEffortSchema option are
+// reported as warning Diagnostics.
+//
+// The schemas are retrieved from the servers once, during muxed server
+// creation, so concurrent calls share the same response content without
+// calling the servers.
 func (s muxServer) GetProviderSchema(ctx context.Context, req *tfprotov6.GetProviderSchemaRequest) (*tfprotov6.GetProviderSchemaResponse, error) {
 	rpc := "GetProviderSchema"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
+	ctx = logging.RequestIdContext(ctx)
 	logging.MuxTrace(ctx, "serving cached schema information")
 
 	return &tfprotov6.GetProviderSchemaResponse{
-		Provider:          s.providerSchema,
-		ResourceSchemas:   s.resourceSchemas,
-		DataSourceSchemas: s.dataSourceSchemas,
-		ProviderMeta:      s.providerMetaSchema,
+		Provider:           s.providerSchema,
+		ResourceSchemas:    s.resourceSchemas,
+		DataSourceSchemas:  s.dataSourceSchemas,
+		ProviderMeta:       s.providerMetaSchema,
+		ServerCapabilities: s.serverCapabilities,
+		Diagnostics:        s.schemaDiagnostics,
 	}, nil
 }
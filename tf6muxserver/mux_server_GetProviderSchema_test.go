@@ -2,6 +2,8 @@ package tf6muxserver_test
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -15,6 +17,7 @@ func TestMuxServerGetProviderSchema(t *testing.T) {
 	t.Parallel()
 
 	testCases := map[string]struct {
+		options                    []tf6muxserver.ServerOption
 		servers                    []func() tfprotov6.ProviderServer
 		expectedDataSourceSchemas  map[string]*tfprotov6.Schema
 		expectedProviderSchema     *tfprotov6.Schema
@@ -416,6 +419,192 @@ func TestMuxServerGetProviderSchema(t *testing.T) {
 				},
 			},
 		},
+		"provider-schema-merge-blocks": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "assume_role",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block: &tfprotov6.SchemaBlock{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name:     "role_arn",
+												Type:     tftypes.String,
+												Required: true,
+											},
+										},
+									},
+									MaxItems: 1,
+								},
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeSet,
+									Block:    &tfprotov6.SchemaBlock{},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeSet,
+									Block:    &tfprotov6.SchemaBlock{},
+								},
+								{
+									TypeName: "retry",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block: &tfprotov6.SchemaBlock{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name:     "max_attempts",
+												Type:     tftypes.Number,
+												Optional: true,
+											},
+										},
+									},
+									MinItems: 1,
+									MaxItems: 1,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedDataSourceSchemas: map[string]*tfprotov6.Schema{},
+			expectedProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{},
+					BlockTypes: []*tfprotov6.SchemaNestedBlock{
+						{
+							TypeName: "assume_role",
+							Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+							Block: &tfprotov6.SchemaBlock{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									{
+										Name:     "role_arn",
+										Type:     tftypes.String,
+										Required: true,
+									},
+								},
+							},
+							MaxItems: 1,
+						},
+						{
+							TypeName: "feature",
+							Nesting:  tfprotov6.SchemaNestedBlockNestingModeSet,
+							Block:    &tfprotov6.SchemaBlock{},
+						},
+						{
+							TypeName: "retry",
+							Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+							Block: &tfprotov6.SchemaBlock{
+								Attributes: []*tfprotov6.SchemaAttribute{
+									{
+										Name:     "max_attempts",
+										Type:     tftypes.Number,
+										Optional: true,
+									},
+								},
+							},
+							MinItems: 1,
+							MaxItems: 1,
+						},
+					},
+				},
+			},
+			expectedResourceSchemas: map[string]*tfprotov6.Schema{},
+		},
+		"provider-schema-merge": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Version: 1,
+						Block: &tfprotov6.SchemaBlock{
+							Version: 1,
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block:    &tfprotov6.SchemaBlock{},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Version: 1,
+						Block: &tfprotov6.SchemaBlock{
+							Version: 1,
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:      "secret",
+									Type:      tftypes.String,
+									Optional:  true,
+									Sensitive: true,
+								},
+							},
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block:    &tfprotov6.SchemaBlock{},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedDataSourceSchemas: map[string]*tfprotov6.Schema{},
+			expectedProviderSchema: &tfprotov6.Schema{
+				Version: 1,
+				Block: &tfprotov6.SchemaBlock{
+					Version: 1,
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{
+							Name:     "account_id",
+							Type:     tftypes.String,
+							Required: true,
+						},
+						{
+							Name:      "secret",
+							Type:      tftypes.String,
+							Optional:  true,
+							Sensitive: true,
+						},
+					},
+					BlockTypes: []*tfprotov6.SchemaNestedBlock{
+						{
+							TypeName: "feature",
+							Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+							Block:    &tfprotov6.SchemaBlock{},
+						},
+					},
+				},
+			},
+			expectedResourceSchemas: map[string]*tfprotov6.Schema{},
+		},
 	}
 
 	for name, testCase := range testCases {
@@ -424,7 +613,7 @@ func TestMuxServerGetProviderSchema(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			muxServer, err := tf6muxserver.NewMuxServer(context.Background(), testCase.servers...)
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, testCase.servers...)
 
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -454,3 +643,358 @@ func TestMuxServerGetProviderSchema(t *testing.T) {
 		})
 	}
 }
+
+// TestMuxServerGetProviderSchemaOwningServerSchemas verifies resource and data
+// source schemas are returned exactly as declared by the owning server, so
+// any per-type schema metadata is preserved.
+func TestMuxServerGetProviderSchemaOwningServerSchemas(t *testing.T) {
+	t.Parallel()
+
+	dataSourceSchema := &tfprotov6.Schema{
+		Version: 2,
+		Block: &tfprotov6.SchemaBlock{
+			Deprecated:  true,
+			Description: "data source description",
+		},
+	}
+	resourceSchema := &tfprotov6.Schema{
+		Version: 3,
+		Block: &tfprotov6.SchemaBlock{
+			Deprecated:      true,
+			Description:     "resource description",
+			DescriptionKind: tfprotov6.StringKindMarkdown,
+		},
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(
+		context.Background(),
+		(&tf6testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov6.Schema{
+				"test_data_source": dataSourceSchema,
+			},
+		}).ProviderServer,
+		(&tf6testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource": resourceSchema,
+			},
+		}).ProviderServer,
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if resp.DataSourceSchemas["test_data_source"] != dataSourceSchema {
+		t.Errorf("expected data source schema from owning server, got: %v", resp.DataSourceSchemas["test_data_source"])
+	}
+
+	if resp.ResourceSchemas["test_resource"] != resourceSchema {
+		t.Errorf("expected resource schema from owning server, got: %v", resp.ResourceSchemas["test_resource"])
+	}
+}
+
+// TestMuxServerGetProviderSchemaAttributeFlags verifies schema attribute
+// flags declared by servers are unchanged in the muxed schema, including
+// when provider schemas are merged.
+func TestMuxServerGetProviderSchemaAttributeFlags(t *testing.T) {
+	t.Parallel()
+
+	newAttribute := func(name string) *tfprotov6.SchemaAttribute {
+		return &tfprotov6.SchemaAttribute{
+			Name:            name,
+			Type:            tftypes.String,
+			Description:     name + " description",
+			DescriptionKind: tfprotov6.StringKindMarkdown,
+			Optional:        true,
+			Computed:        true,
+			Sensitive:       true,
+			Deprecated:      true,
+		}
+	}
+
+	servers := []func() tfprotov6.ProviderServer{
+		(&tf6testserver.TestServer{
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{
+						newAttribute("provider_attribute1"),
+					},
+				},
+			},
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource": {
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							newAttribute("resource_attribute"),
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+		(&tf6testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov6.Schema{
+				"test_data_source": {
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							newAttribute("data_source_attribute"),
+						},
+					},
+				},
+			},
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{
+						newAttribute("provider_attribute2"),
+					},
+				},
+			},
+		}).ProviderServer,
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{tf6muxserver.WithProviderSchemaMerge(true)}, servers...)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedProviderAttributes := []*tfprotov6.SchemaAttribute{
+		newAttribute("provider_attribute1"),
+		newAttribute("provider_attribute2"),
+	}
+
+	if diff := cmp.Diff(resp.Provider.Block.Attributes, expectedProviderAttributes); diff != "" {
+		t.Errorf("provider schema attributes didn't match expectations: %s", diff)
+	}
+
+	expectedResourceAttributes := []*tfprotov6.SchemaAttribute{
+		newAttribute("resource_attribute"),
+	}
+
+	if diff := cmp.Diff(resp.ResourceSchemas["test_resource"].Block.Attributes, expectedResourceAttributes); diff != "" {
+		t.Errorf("resource schema attributes didn't match expectations: %s", diff)
+	}
+
+	expectedDataSourceAttributes := []*tfprotov6.SchemaAttribute{
+		newAttribute("data_source_attribute"),
+	}
+
+	if diff := cmp.Diff(resp.DataSourceSchemas["test_data_source"].Block.Attributes, expectedDataSourceAttributes); diff != "" {
+		t.Errorf("data source schema attributes didn't match expectations: %s", diff)
+	}
+}
+
+func TestMuxServerGetProviderSchemaServerCapabilities(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		servers                    []func() tfprotov6.ProviderServer
+		expectedServerCapabilities *tfprotov6.ServerCapabilities
+	}{
+		"all-resource-servers-plan-destroy": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server2": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov6.ServerCapabilities{
+				PlanDestroy: true,
+			},
+		},
+		"data-source-server-without-plan-destroy": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source_server2": {},
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov6.ServerCapabilities{
+				PlanDestroy: true,
+			},
+		},
+		"mixed-plan-destroy": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server2": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: false,
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: nil,
+		},
+		"mixed-missing-server-capabilities": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server2": {},
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: nil,
+		},
+		"no-resources": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: nil,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := tf6muxserver.NewMuxServer(context.Background(), testCase.servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp.ServerCapabilities, testCase.expectedServerCapabilities); diff != "" {
+				t.Errorf("server capabilities didn't match expectations: %s", diff)
+			}
+		})
+	}
+}
+
+// countingSchemaServer counts the GetProviderSchema calls of the server.
+type countingSchemaServer struct {
+	tfprotov6.ProviderServer
+
+	calls *int64
+}
+
+func (s countingSchemaServer) GetProviderSchema(ctx context.Context, req *tfprotov6.GetProviderSchemaRequest) (*tfprotov6.GetProviderSchemaResponse, error) {
+	atomic.AddInt64(s.calls, 1)
+
+	return s.ProviderServer.GetProviderSchema(ctx, req)
+}
+
+// TestMuxServerGetProviderSchemaConcurrent verifies concurrent
+// GetProviderSchema calls share the schemas retrieved during muxed server
+// creation, rather than each retrieving the schemas from the servers.
+func TestMuxServerGetProviderSchemaConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var calls int64
+
+	server := countingSchemaServer{
+		ProviderServer: (&tf6testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource": {},
+			},
+		}).ProviderServer(),
+		calls: &calls,
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(context.Background(), func() tfprotov6.ProviderServer {
+		return server
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var wg sync.WaitGroup
+
+	responses := make([]*tfprotov6.GetProviderSchemaResponse, 20)
+
+	for i := range responses {
+		i := i
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+
+				return
+			}
+
+			responses[i] = resp
+		}()
+	}
+
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&calls); got != 1 {
+		t.Errorf("expected 1 server GetProviderSchema call, got %d", got)
+	}
+
+	for i, resp := range responses {
+		if diff := cmp.Diff(resp, responses[0]); diff != "" {
+			t.Errorf("unexpected response %d difference: %s", i, diff)
+		}
+	}
+}
@@ -2,13 +2,47 @@ package tf6muxserver_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// partialServer is a server which does not implement the
+// ImportResourceState RPC.
+type partialServer struct {
+	tfprotov6.ProviderServer
+}
+
+func (s partialServer) ImportResourceState(_ context.Context, _ *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportResourceState not implemented")
+}
+
+// multipleImportServer is a server which returns an imported resource for
+// each of its type names from the ImportResourceState RPC.
+type multipleImportServer struct {
+	*tf6testserver.TestServer
+
+	importedTypeNames []string
+}
+
+func (s multipleImportServer) ImportResourceState(_ context.Context, _ *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	resp := &tfprotov6.ImportResourceStateResponse{}
+
+	for _, typeName := range s.importedTypeNames {
+		resp.ImportedResources = append(resp.ImportedResources, &tfprotov6.ImportedResource{
+			TypeName: typeName,
+		})
+	}
+
+	return resp, nil
+}
+
 func TestMuxServerImportResourceState(t *testing.T) {
 	t.Parallel()
 
@@ -64,3 +98,181 @@ func TestMuxServerImportResourceState(t *testing.T) {
 		t.Errorf("expected test_resource_server2 ImportResourceState to be called on server2")
 	}
 }
+
+func TestMuxServerImportResourceStateUnimplementedRPCDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled          bool
+		expectedError    string
+		expectedResponse *tfprotov6.ImportResourceStateResponse
+	}{
+		"disabled": {
+			enabled:       false,
+			expectedError: "method ImportResourceState not implemented",
+		},
+		"enabled": {
+			enabled: true,
+			expectedResponse: &tfprotov6.ImportResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "Unsupported Provider Operation",
+						Detail: "The ImportResourceState operation for \"test_resource\" is not supported by the provider, because the server tf6muxserver_test.partialServer handling it does not implement the operation. " +
+							"This is always an issue in the provider implementation and should be reported to the provider developers.\n\n" +
+							"Error: rpc error: code = Unimplemented desc = method ImportResourceState not implemented",
+					},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			servers := []func() tfprotov6.ProviderServer{
+				func() tfprotov6.ProviderServer {
+					return partialServer{
+						ProviderServer: &tf6testserver.TestServer{
+							ResourceSchemas: map[string]*tfprotov6.Schema{
+								"test_resource": {},
+							},
+						},
+					}
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{tf6muxserver.WithUnimplementedRPCDiagnostics(testCase.enabled)}, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				if testCase.expectedError == "" || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			if diff := cmp.Diff(resp, testCase.expectedResponse); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMuxServerImportResourceStateImportedResources(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options             []tf6muxserver.ServerOption
+		importedTypeNames   []string
+		expectedDiagnostics []*tfprotov6.Diagnostic
+	}{
+		"same-server": {
+			importedTypeNames: []string{"test_resource_server1", "test_resource_server1_other"},
+		},
+		"cross-server": {
+			importedTypeNames: []string{"test_resource_server1", "test_resource_server2"},
+			expectedDiagnostics: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityWarning,
+					Summary:  "Imported Resource Type Implemented by Another Server",
+					Detail: "The import of \"test_resource_server1\" by tf6muxserver_test.multipleImportServer returned a resource of type \"test_resource_server2\", which is implemented by *tf6testserver.TestServer. " +
+						"Later operations on the imported resource are handled by that server, which may not support the imported state. " +
+						"This is always an issue in the provider implementation and should be reported to the provider developers.",
+				},
+			},
+		},
+		"cross-server-WithCrossServerImportErrors": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithCrossServerImportErrors(true),
+			},
+			importedTypeNames: []string{"test_resource_server1", "test_resource_server2"},
+			expectedDiagnostics: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityError,
+					Summary:  "Imported Resource Type Implemented by Another Server",
+					Detail: "The import of \"test_resource_server1\" by tf6muxserver_test.multipleImportServer returned a resource of type \"test_resource_server2\", which is implemented by *tf6testserver.TestServer. " +
+						"Later operations on the imported resource are handled by that server, which may not support the imported state. " +
+						"This is always an issue in the provider implementation and should be reported to the provider developers.",
+				},
+			},
+		},
+		"unsupported": {
+			importedTypeNames: []string{"test_resource_server1", "test_resource_unsupported"},
+			expectedDiagnostics: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityError,
+					Summary:  "Unsupported Imported Resource Type",
+					Detail: "The import of \"test_resource_server1\" by tf6muxserver_test.multipleImportServer returned a resource of type \"test_resource_unsupported\", which is not implemented by any server of the provider, so it cannot be managed. " +
+						"This is always an issue in the provider implementation and should be reported to the provider developers.",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			servers := []func() tfprotov6.ProviderServer{
+				func() tfprotov6.ProviderServer {
+					return multipleImportServer{
+						TestServer: &tf6testserver.TestServer{
+							ResourceSchemas: map[string]*tfprotov6.Schema{
+								"test_resource_server1":       {},
+								"test_resource_server1_other": {},
+							},
+						},
+						importedTypeNames: testCase.importedTypeNames,
+					}
+				},
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server2": {},
+					},
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, testCase.options, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{
+				TypeName: "test_resource_server1",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(resp.ImportedResources) != len(testCase.importedTypeNames) {
+				t.Errorf("expected %d imported resources, got %d", len(testCase.importedTypeNames), len(resp.ImportedResources))
+			}
+
+			if diff := cmp.Diff(resp.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
@@ -10,31 +10,114 @@ import (
 )
 
 // StopProvider calls the StopProvider function for each provider associated
-// with the muxServer, one at a time. All Error fields will be joined
-// together and returned, but will not prevent the rest of the providers'
-// StopProvider methods from being called.
+// with the muxServer, one at a time. All Error fields and errors returned by
+// providers will be joined together, one per line in server order and
+// prefixed with the index and Go type of the server, such as
+// "server 1 (*example.Server): connections still open", and returned in the
+// Error field, but will not prevent the rest of the providers' StopProvider
+// methods from being called. Each line is also logged at WARN level.
+//
+// If the WithConcurrentStopProvider option is enabled, the providers'
+// StopProvider methods are called concurrently instead.
+//
+// In both cases, if the context is cancelled or its deadline is exceeded
+// before every provider returned, no more providers are called and an error
+// wrapping the context error is returned. The error includes the joined lines
+// with a "stop timed out" line for each provider which did not return.
 func (s muxServer) StopProvider(ctx context.Context, req *tfprotov6.StopProviderRequest) (*tfprotov6.StopProviderResponse, error) {
-	rpc := "StopProvider"
 	ctx = logging.InitContext(ctx)
-	ctx = logging.RpcContext(ctx, rpc)
-	var errs []string
+	ctx = logging.RpcContext(ctx, "StopProvider")
+	ctx = logging.RequestIdContext(ctx)
 
-	for _, server := range s.servers {
-		ctx = logging.Tfprotov6ProviderServerContext(ctx, server)
-		logging.MuxTrace(ctx, "calling downstream server")
+	servers := s.currentServers()
+	results := make(chan stopResult, len(servers))
+	errs := make([]string, len(servers))
+	returned := make([]bool, len(servers))
 
-		resp, err := server.StopProvider(ctx, req)
+	if s.options.concurrentStopProvider {
+		for serverIndex, server := range servers {
+			// Logging context fields cannot be safely set concurrently, so
+			// the server context is created before starting each goroutine.
+			go s.stopServer(logging.Tfprotov6ProviderServerContext(ctx, server), serverIndex, server, req, results)
+		}
+	}
 
-		if err != nil {
-			return resp, fmt.Errorf("error stopping %T: %w", server, err)
+wait:
+	for serverIndex, server := range servers {
+		if !s.options.concurrentStopProvider {
+			go s.stopServer(logging.Tfprotov6ProviderServerContext(ctx, server), serverIndex, server, req, results)
 		}
 
-		if resp.Error != "" {
-			errs = append(errs, resp.Error)
+		select {
+		case result := <-results:
+			errs[result.serverIndex] = result.err
+			returned[result.serverIndex] = true
+		case <-ctx.Done():
+			break wait
 		}
 	}
 
+	var joined []string
+	var timedOut bool
+
+	for serverIndex, server := range servers {
+		serverCtx := logging.Tfprotov6ProviderServerContext(ctx, server)
+
+		if !returned[serverIndex] {
+			joined = append(joined, stopProviderError(serverCtx, serverIndex, server, "stop timed out"))
+			timedOut = true
+
+			continue
+		}
+
+		if errs[serverIndex] != "" {
+			joined = append(joined, stopProviderError(serverCtx, serverIndex, server, errs[serverIndex]))
+		}
+	}
+
+	if timedOut {
+		return nil, fmt.Errorf("error stopping servers: %w\n%s", ctx.Err(), strings.Join(joined, "\n"))
+	}
+
 	return &tfprotov6.StopProviderResponse{
-		Error: strings.Join(errs, "\n"),
+		Error: strings.Join(joined, "\n"),
 	}, nil
 }
+
+// stopResult is the StopProvider result of a server.
+type stopResult struct {
+	serverIndex int
+	err         string
+}
+
+// stopServer calls the StopProvider function of the server and sends the
+// result. Nothing is sent if the server did not stop because the context was
+// done, so the server is reported as timed out.
+func (s muxServer) stopServer(ctx context.Context, serverIndex int, server tfprotov6.ProviderServer, req *tfprotov6.StopProviderRequest, results chan<- stopResult) {
+	logging.MuxTrace(ctx, "calling downstream server")
+
+	resp, err := callServer(ctx, s, "StopProvider", req, server.StopProvider)
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return
+	case err != nil:
+		results <- stopResult{serverIndex, fmt.Sprintf("error stopping: %s", err)}
+	case resp != nil:
+		results <- stopResult{serverIndex, resp.Error}
+	default:
+		results <- stopResult{serverIndex, ""}
+	}
+}
+
+// stopProviderError returns the StopProvider response Error line of the
+// server, prefixed with the index and Go type of the server, and logs it at
+// WARN level.
+func stopProviderError(ctx context.Context, serverIndex int, server tfprotov6.ProviderServer, message string) string {
+	logging.MuxWarn(ctx, "server returned StopProvider error", map[string]interface{}{
+		logging.KeyError:            message,
+		logging.KeyTfMuxServerIndex: serverIndex,
+	})
+
+	return fmt.Sprintf("server %d (%T): %s", serverIndex, server, message)
+}
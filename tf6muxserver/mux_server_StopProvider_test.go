@@ -2,7 +2,10 @@ package tf6muxserver_test
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
@@ -30,15 +33,213 @@ func TestMuxServerStopProvider(t *testing.T) {
 		t.Fatalf("error setting up muxer: %s", err)
 	}
 
-	_, err = muxServer.ProviderServer().StopProvider(context.Background(), &tfprotov6.StopProviderRequest{})
+	resp, err := muxServer.ProviderServer().StopProvider(context.Background(), &tfprotov6.StopProviderRequest{})
 
 	if err != nil {
 		t.Fatalf("error calling StopProvider: %s", err)
 	}
 
+	expectedError := "server 1 (*tf6testserver.TestServer): error in server2\nserver 3 (*tf6testserver.TestServer): error in server4"
+
+	if resp.Error != expectedError {
+		t.Errorf("expected response error %q, got: %q", expectedError, resp.Error)
+	}
+
 	for num, server := range servers {
 		if !server().(*tf6testserver.TestServer).StopProviderCalled {
 			t.Errorf("StopProvider not called on server%d", num+1)
 		}
 	}
 }
+
+// blockingStopServer is a server whose StopProvider method does not return
+// until unblocked.
+type blockingStopServer struct {
+	tfprotov6.ProviderServer
+
+	unblock chan struct{}
+}
+
+func (s blockingStopServer) StopProvider(_ context.Context, _ *tfprotov6.StopProviderRequest) (*tfprotov6.StopProviderResponse, error) {
+	<-s.unblock
+
+	return &tfprotov6.StopProviderResponse{}, nil
+}
+
+func TestMuxServerStopProviderDeadlineExceeded(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options       []tf6muxserver.ServerOption
+		expectedError string
+	}{
+		"concurrent": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithConcurrentStopProvider(true),
+				tf6muxserver.WithMaxConcurrency(4),
+			},
+			expectedError: strings.Join([]string{
+				"error stopping servers: context deadline exceeded",
+				"server 1 (*tf6testserver.TestServer): error in server2",
+				"server 2 (tf6muxserver_test.blockingStopServer): stop timed out",
+				"server 3 (*tf6testserver.TestServer): error in server4",
+			}, "\n"),
+		},
+		"sequential": {
+			expectedError: strings.Join([]string{
+				"error stopping servers: context deadline exceeded",
+				"server 1 (*tf6testserver.TestServer): error in server2",
+				"server 2 (tf6muxserver_test.blockingStopServer): stop timed out",
+				"server 3 (*tf6testserver.TestServer): stop timed out",
+			}, "\n"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			unblock := make(chan struct{})
+			defer close(unblock)
+
+			blockingServer := blockingStopServer{
+				ProviderServer: &tf6testserver.TestServer{},
+				unblock:        unblock,
+			}
+
+			servers := []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{}).ProviderServer,
+				(&tf6testserver.TestServer{
+					StopProviderError: "error in server2",
+				}).ProviderServer,
+				func() tfprotov6.ProviderServer { return blockingServer },
+				(&tf6testserver.TestServer{
+					StopProviderError: "error in server4",
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			_, err = muxServer.ProviderServer().StopProvider(ctx, &tfprotov6.StopProviderRequest{})
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected error %v, got: %v", context.DeadlineExceeded, err)
+			}
+
+			if err.Error() != testCase.expectedError {
+				t.Errorf("expected error %q, got: %q", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+// cancelingStopServer is a server whose StopProvider method cancels the
+// request context, as if Terraform cancelled the request during the call.
+type cancelingStopServer struct {
+	tfprotov6.ProviderServer
+
+	cancel context.CancelFunc
+}
+
+func (s cancelingStopServer) StopProvider(ctx context.Context, _ *tfprotov6.StopProviderRequest) (*tfprotov6.StopProviderResponse, error) {
+	s.cancel()
+
+	return nil, ctx.Err()
+}
+
+// errorStopServer is a server whose StopProvider method returns an error.
+type errorStopServer struct {
+	tfprotov6.ProviderServer
+}
+
+func (s errorStopServer) StopProvider(_ context.Context, _ *tfprotov6.StopProviderRequest) (*tfprotov6.StopProviderResponse, error) {
+	return nil, errors.New("test error")
+}
+
+func TestMuxServerStopProviderContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		cancelDuringStop        bool
+		expectedError           error
+		expectedErrorMessage    string
+		expectedResponseError   string
+		expectedCalledOnServer4 bool
+	}{
+		"context-cancelled": {
+			cancelDuringStop:        true,
+			expectedError:           context.Canceled,
+			expectedErrorMessage:    "error stopping servers: context canceled\nserver 1 (tf6muxserver_test.errorStopServer): error stopping: test error\nserver 2 (tf6muxserver_test.cancelingStopServer): stop timed out\nserver 3 (*tf6testserver.TestServer): stop timed out",
+			expectedCalledOnServer4: false,
+		},
+		"server-error": {
+			cancelDuringStop:        false,
+			expectedResponseError:   "server 1 (tf6muxserver_test.errorStopServer): error stopping: test error\nserver 2 (*tf6testserver.TestServer): error in server3",
+			expectedCalledOnServer4: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var server3 tfprotov6.ProviderServer = &tf6testserver.TestServer{
+				StopProviderError: "error in server3",
+			}
+
+			if testCase.cancelDuringStop {
+				server3 = cancelingStopServer{
+					ProviderServer: &tf6testserver.TestServer{},
+					cancel:         cancel,
+				}
+			}
+
+			server4 := &tf6testserver.TestServer{}
+
+			servers := []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{}).ProviderServer,
+				func() tfprotov6.ProviderServer { return errorStopServer{ProviderServer: &tf6testserver.TestServer{}} },
+				func() tfprotov6.ProviderServer { return server3 },
+				server4.ProviderServer,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServer(context.Background(), servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().StopProvider(ctx, &tfprotov6.StopProviderRequest{})
+
+			if !errors.Is(err, testCase.expectedError) {
+				t.Fatalf("expected error %v, got: %v", testCase.expectedError, err)
+			}
+
+			if err != nil && err.Error() != testCase.expectedErrorMessage {
+				t.Errorf("expected error %q, got: %q", testCase.expectedErrorMessage, err)
+			}
+
+			if err == nil && resp.Error != testCase.expectedResponseError {
+				t.Errorf("expected response error %q, got: %q", testCase.expectedResponseError, resp.Error)
+			}
+
+			if server4.StopProviderCalled != testCase.expectedCalledOnServer4 {
+				t.Errorf("expected StopProvider called on server4 %t, got %t", testCase.expectedCalledOnServer4, server4.StopProviderCalled)
+			}
+		})
+	}
+}
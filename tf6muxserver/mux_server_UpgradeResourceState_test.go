@@ -4,9 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxservertest"
 )
 
 func TestMuxServerUpgradeResourceState(t *testing.T) {
@@ -64,3 +66,311 @@ func TestMuxServerUpgradeResourceState(t *testing.T) {
 		t.Errorf("expected test_resource_server2 UpgradeResourceState to be called on server2")
 	}
 }
+
+func TestMuxServerUpgradeResourceStatePassthrough(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		versionCheck       bool
+		request            *tfprotov6.UpgradeResourceStateRequest
+		expectServerCalled bool
+		expectedResp       *tfprotov6.UpgradeResourceStateResponse
+	}{
+		"flatmap": {
+			request: &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "test_resource",
+				Version:  1,
+				RawState: &tfprotov6.RawState{
+					Flatmap: map[string]string{
+						"id":     "test-id",
+						"tags.%": "0",
+					},
+				},
+			},
+			expectServerCalled: true,
+		},
+		"json": {
+			request: &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "test_resource",
+				Version:  1,
+				RawState: &tfprotov6.RawState{
+					JSON: []byte(`{"id":"test-id","tags":{}}`),
+				},
+			},
+			expectServerCalled: true,
+		},
+		"version-check-current-version": {
+			versionCheck: true,
+			request: &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "test_resource",
+				Version:  2,
+				RawState: &tfprotov6.RawState{
+					JSON: []byte(`{"id":"test-id"}`),
+				},
+			},
+			expectServerCalled: true,
+		},
+		"version-check-newer-version": {
+			versionCheck: true,
+			request: &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "test_resource",
+				Version:  3,
+				RawState: &tfprotov6.RawState{
+					JSON: []byte(`{"id":"test-id"}`),
+				},
+			},
+			expectServerCalled: true,
+			expectedResp: &tfprotov6.UpgradeResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "Unsupported Resource State Version",
+						Detail: "The test_resource resource state version 3 is greater than the resource schema version 2. " +
+							"The state was likely written by a newer version of the provider, which must be used to manage this resource.",
+					},
+				},
+			},
+		},
+		"version-check-older-version": {
+			versionCheck: true,
+			request: &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "test_resource",
+				Version:  1,
+				RawState: &tfprotov6.RawState{
+					Flatmap: map[string]string{
+						"id": "test-id",
+					},
+				},
+			},
+			expectServerCalled: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			recordingServer := tf6muxservertest.NewRecordingServer(&tf6testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": {
+						Version: 2,
+					},
+				},
+			})
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{
+				tf6muxserver.WithUpgradeResourceStateVersionCheck(testCase.versionCheck),
+			}, recordingServer.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().UpgradeResourceState(ctx, testCase.request)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp, testCase.expectedResp); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+
+			lastRequest := recordingServer.LastUpgradeResourceStateRequest()
+
+			if !testCase.expectServerCalled {
+				if lastRequest != nil {
+					t.Errorf("unexpected UpgradeResourceState call: %v", lastRequest)
+				}
+
+				return
+			}
+
+			if diff := cmp.Diff(lastRequest, testCase.request); diff != "" {
+				t.Errorf("unexpected request difference: %s", diff)
+			}
+		})
+	}
+}
+
+// upgradedStateServer is a server whose UpgradeResourceState method returns
+// an upgraded state and a warning diagnostic.
+type upgradedStateServer struct {
+	tfprotov6.ProviderServer
+
+	upgradedState *tfprotov6.DynamicValue
+}
+
+func (s upgradedStateServer) UpgradeResourceState(_ context.Context, _ *tfprotov6.UpgradeResourceStateRequest) (*tfprotov6.UpgradeResourceStateResponse, error) {
+	return &tfprotov6.UpgradeResourceStateResponse{
+		Diagnostics: []*tfprotov6.Diagnostic{
+			{
+				Severity: tfprotov6.DiagnosticSeverityWarning,
+				Summary:  "server warning summary",
+			},
+		},
+		UpgradedState: s.upgradedState,
+	}, nil
+}
+
+func TestMuxServerUpgradeResourceStateDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	upgradedState := &tfprotov6.DynamicValue{
+		JSON: []byte(`{"id":"test-id"}`),
+	}
+
+	testCases := map[string]struct {
+		diagnosticsFunc tf6muxserver.UpgradeResourceStateDiagnosticsFunc
+		versionCheck    bool
+		version         int64
+		expectedResp    *tfprotov6.UpgradeResourceStateResponse
+	}{
+		"none": {
+			expectedResp: &tfprotov6.UpgradeResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "server warning summary",
+					},
+				},
+				UpgradedState: upgradedState,
+			},
+		},
+		"diagnostics": {
+			diagnosticsFunc: func(_ context.Context, req *tfprotov6.UpgradeResourceStateRequest) []*tfprotov6.Diagnostic {
+				return []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "mux warning summary",
+						Detail:   req.TypeName,
+					},
+				}
+			},
+			expectedResp: &tfprotov6.UpgradeResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "mux warning summary",
+						Detail:   "test_resource",
+					},
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "server warning summary",
+					},
+				},
+				UpgradedState: upgradedState,
+			},
+		},
+		"version-check": {
+			versionCheck: true,
+			version:      1,
+			expectedResp: &tfprotov6.UpgradeResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "Unsupported Resource State Version",
+						Detail: "The test_resource resource state version 1 is greater than the resource schema version 0. " +
+							"The state was likely written by a newer version of the provider, which must be used to manage this resource.",
+					},
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "server warning summary",
+					},
+				},
+				UpgradedState: upgradedState,
+			},
+		},
+		"version-check-and-diagnostics": {
+			diagnosticsFunc: func(_ context.Context, _ *tfprotov6.UpgradeResourceStateRequest) []*tfprotov6.Diagnostic {
+				return []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "mux warning summary",
+					},
+				}
+			},
+			versionCheck: true,
+			version:      1,
+			expectedResp: &tfprotov6.UpgradeResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "Unsupported Resource State Version",
+						Detail: "The test_resource resource state version 1 is greater than the resource schema version 0. " +
+							"The state was likely written by a newer version of the provider, which must be used to manage this resource.",
+					},
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "mux warning summary",
+					},
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "server warning summary",
+					},
+				},
+				UpgradedState: upgradedState,
+			},
+		},
+		"no-diagnostics": {
+			diagnosticsFunc: func(_ context.Context, _ *tfprotov6.UpgradeResourceStateRequest) []*tfprotov6.Diagnostic {
+				return nil
+			},
+			expectedResp: &tfprotov6.UpgradeResourceStateResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "server warning summary",
+					},
+				},
+				UpgradedState: upgradedState,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := upgradedStateServer{
+				ProviderServer: &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				},
+				upgradedState: upgradedState,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf6muxserver.ServerOption{
+					tf6muxserver.WithUpgradeResourceStateDiagnostics(testCase.diagnosticsFunc),
+					tf6muxserver.WithUpgradeResourceStateVersionCheck(testCase.versionCheck),
+				},
+				func() tfprotov6.ProviderServer { return server },
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			resp, err := muxServer.ProviderServer().UpgradeResourceState(context.Background(), &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "test_resource",
+				Version:  testCase.version,
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp, testCase.expectedResp); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+		})
+	}
+}
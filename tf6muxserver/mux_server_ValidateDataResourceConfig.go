@@ -2,7 +2,6 @@ package tf6muxserver
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
@@ -15,14 +14,25 @@ func (s muxServer) ValidateDataResourceConfig(ctx context.Context, req *tfprotov
 	rpc := "ValidateDataResourceConfig"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	server, ok := s.dataSources[req.TypeName]
+	ctx = logging.RequestIdContext(ctx)
 
-	if !ok {
-		return nil, fmt.Errorf("%q isn't supported by any servers", req.TypeName)
+	if err := checkRequest(rpc, req, func(req *tfprotov6.ValidateDataResourceConfigRequest) string { return req.TypeName }); err != nil {
+		return nil, err
+	}
+
+	server, err := s.routeRequest(ctx, rpc, req.TypeName, req, s.dataSourceServer)
+
+	if err != nil {
+		return nil, err
 	}
 
 	ctx = logging.Tfprotov6ProviderServerContext(ctx, server)
+	ctx, cancel := s.rpcTimeoutContext(ctx, rpc, req.TypeName)
+	defer cancel()
+
 	logging.MuxTrace(ctx, "calling downstream server")
 
-	return server.ValidateDataResourceConfig(ctx, req)
+	resp, err := callServer(ctx, s, rpc, req, namespacedCall(s.dataSourceNamespace(req.TypeName), server.ValidateDataResourceConfig))
+
+	return resp, rpcTimeoutError(ctx, rpc, req.TypeName, err)
 }
@@ -3,44 +3,67 @@ package tf6muxserver
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
 )
 
 // ValidateProviderConfig calls the ValidateProviderConfig method on each server
 // in order, passing `req`. Response diagnostics are appended from all servers.
-// Response PreparedConfig must be equal across all servers with nil values
-// skipped.
+// Response PreparedConfig is reconciled across servers with nil values
+// skipped: a PreparedConfig returned by only one server is used, equal
+// PreparedConfig returned by multiple servers are used, and differing
+// PreparedConfig result in an error Diagnostic and no PreparedConfig.
+//
+// If the WithProviderSchemaMerge option is enabled, the
+// WithRequireSharedProviderSchema option is not enabled, and any server
+// declared a provider schema, only servers which declared a provider schema
+// are called, each with only the Config declared in its own provider schema,
+// and the response PreparedConfig is combined from each server's attributes
+// instead.
+//
+// If the WithDiagnosticProvenance option is enabled, the Go type of the
+// server is appended to the Detail of each Diagnostic.
 func (s muxServer) ValidateProviderConfig(ctx context.Context, req *tfprotov6.ValidateProviderConfigRequest) (*tfprotov6.ValidateProviderConfigResponse, error) {
 	rpc := "ValidateProviderConfig"
 	ctx = logging.InitContext(ctx)
 	ctx = logging.RpcContext(ctx, rpc)
-	var resp *tfprotov6.ValidateProviderConfigResponse
+	ctx = logging.RequestIdContext(ctx)
+
+	if s.options.providerSchemaMerge && !s.options.requireSharedProviderSchema && s.providerSchema != nil {
+		return s.validateMergedProviderConfig(ctx, req)
+	}
 
-	for _, server := range s.servers {
+	var diagnostics []*tfprotov6.Diagnostic
+	var preparedConfig *tfprotov6.DynamicValue
+	var preparedConfigServer tfprotov6.ProviderServer
+	var conflictingServers []string
+	var responded bool
+
+	for _, server := range s.currentServers() {
 		ctx = logging.Tfprotov6ProviderServerContext(ctx, server)
 		logging.MuxTrace(ctx, "calling downstream server")
 
-		res, err := server.ValidateProviderConfig(ctx, req)
+		res, err := callServer(ctx, s, rpc, req, server.ValidateProviderConfig)
 
 		if err != nil {
-			return resp, fmt.Errorf("error from %T validating provider config: %w", server, err)
+			return nil, fmt.Errorf("error from %T validating provider config: %w", server, err)
 		}
 
 		if res == nil {
 			continue
 		}
 
-		if resp == nil {
-			resp = res
-			continue
-		}
+		responded = true
 
-		if len(res.Diagnostics) > 0 {
+		if s.options.diagnosticProvenance {
+			diagnostics = append(diagnostics, diagnosticsWithProvenance(res.Diagnostics, server)...)
+		} else {
 			// This could implement Diagnostic deduplication if/when
 			// implemented upstream.
-			resp.Diagnostics = append(resp.Diagnostics, res.Diagnostics...)
+			diagnostics = append(diagnostics, res.Diagnostics...)
 		}
 
 		// Do not check equality on missing PreparedConfig or unset PreparedConfig
@@ -48,18 +71,166 @@ func (s muxServer) ValidateProviderConfig(ctx context.Context, req *tfprotov6.Va
 			continue
 		}
 
-		equal, err := dynamicValueEquals(s.providerSchema.ValueType(), res.PreparedConfig, resp.PreparedConfig)
+		if preparedConfig == nil {
+			preparedConfig = res.PreparedConfig
+			preparedConfigServer = server
+			continue
+		}
+
+		// PreparedConfig can only be compared using the provider schema,
+		// which is separate from any provider meta schema.
+		if s.providerSchema == nil {
+			if s.providerMetaSchema != nil {
+				return nil, fmt.Errorf("unable to compare ValidateProviderConfig PreparedConfig responses: no server declared a provider schema, only a provider meta schema, which does not apply to provider configuration")
+			}
+
+			return nil, fmt.Errorf("unable to compare ValidateProviderConfig PreparedConfig responses: no server declared a provider schema or provider meta schema")
+		}
+
+		equal, err := dynamicValueEquals(s.providerSchema.ValueType(), res.PreparedConfig, preparedConfig)
 
 		if err != nil {
-			return nil, fmt.Errorf("unable to compare PrepareProviderConfig PreparedConfig responses: %w", err)
+			return nil, fmt.Errorf("unable to compare ValidateProviderConfig PreparedConfig responses: %w", err)
 		}
 
 		if !equal {
-			return nil, fmt.Errorf("got different PrepareProviderConfig PreparedConfig response from multiple servers, not sure which to use")
+			conflictingServers = append(conflictingServers, fmt.Sprintf("%T", server))
+		}
+	}
+
+	if !responded {
+		return nil, nil
+	}
+
+	// The response is always created, rather than reusing a server
+	// response, so server responses are never modified.
+	resp := &tfprotov6.ValidateProviderConfigResponse{
+		Diagnostics:    diagnostics,
+		PreparedConfig: preparedConfig,
+	}
+
+	if len(conflictingServers) > 0 {
+		resp.PreparedConfig = nil
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov6.Diagnostic{
+			Severity: tfprotov6.DiagnosticSeverityError,
+			Summary:  "Conflicting Prepared Provider Configuration",
+			Detail: "Multiple servers returned different prepared provider configurations, so the muxed server cannot choose one. " +
+				"This is always an issue in the provider implementation and should be reported to the provider developers.\n\n" +
+				fmt.Sprintf("The prepared provider configuration of %T differs from: %s", preparedConfigServer, strings.Join(conflictingServers, ", ")),
+		})
+	}
+
+	return resp, nil
+}
+
+// validateMergedProviderConfig calls the ValidateProviderConfig method on each
+// server which declared a provider schema, in order, passing `req` with the
+// Config projected to the provider schema of the server, so each server can
+// decode the Config with its own provider schema. Response diagnostics are
+// appended from all servers. Response PreparedConfig attribute values are
+// taken from the server which declared the attribute, with nil values
+// skipped.
+func (s muxServer) validateMergedProviderConfig(ctx context.Context, req *tfprotov6.ValidateProviderConfigRequest) (*tfprotov6.ValidateProviderConfigResponse, error) {
+	rpc := "ValidateProviderConfig"
+	resp := &tfprotov6.ValidateProviderConfigResponse{}
+	preparedAttributes := make(map[string]tftypes.Value)
+
+	for idx, server := range s.currentServers() {
+		serverProviderSchema := s.serverProviderSchemas[idx]
+
+		if serverProviderSchema == nil {
+			continue
+		}
+
+		ctx = logging.Tfprotov6ProviderServerContext(ctx, server)
+
+		config, err := projectConfig(req.Config, s.providerSchema, serverProviderSchema)
+
+		if err != nil {
+			return nil, fmt.Errorf("error projecting provider config for %T: %w", server, err)
+		}
+
+		serverReq := &tfprotov6.ValidateProviderConfigRequest{
+			Config: config,
+		}
+
+		logging.MuxTrace(ctx, "calling downstream server")
+
+		res, err := callServer(ctx, s, rpc, serverReq, server.ValidateProviderConfig)
+
+		if err != nil {
+			return nil, fmt.Errorf("error from %T validating provider config: %w", server, err)
+		}
+
+		if res == nil {
+			continue
+		}
+
+		if s.options.diagnosticProvenance {
+			res = &tfprotov6.ValidateProviderConfigResponse{
+				Diagnostics:    diagnosticsWithProvenance(res.Diagnostics, server),
+				PreparedConfig: res.PreparedConfig,
+			}
+		}
+
+		resp.Diagnostics = append(resp.Diagnostics, res.Diagnostics...)
+
+		if res.PreparedConfig == nil {
+			continue
+		}
+
+		preparedConfig, err := res.PreparedConfig.Unmarshal(serverProviderSchema.ValueType())
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal ValidateProviderConfig PreparedConfig response from %T: %w", server, err)
+		}
+
+		if preparedConfig.IsNull() || !preparedConfig.IsKnown() {
+			continue
+		}
+
+		var attributes map[string]tftypes.Value
+
+		if err := preparedConfig.As(&attributes); err != nil {
+			return nil, fmt.Errorf("unable to convert ValidateProviderConfig PreparedConfig response from %T: %w", server, err)
+		}
+
+		for name, value := range attributes {
+			// Nested blocks defined by multiple servers are identical, so
+			// the first server response is used.
+			if _, ok := preparedAttributes[name]; ok {
+				continue
+			}
+
+			preparedAttributes[name] = value
+		}
+	}
+
+	if len(preparedAttributes) == 0 {
+		return resp, nil
+	}
+
+	providerType, ok := s.providerSchema.ValueType().(tftypes.Object)
+
+	if !ok {
+		return nil, fmt.Errorf("unexpected provider schema type: %s", s.providerSchema.ValueType())
+	}
+
+	for name, attributeType := range providerType.AttributeTypes {
+		if _, ok := preparedAttributes[name]; ok {
+			continue
 		}
 
-		resp.PreparedConfig = res.PreparedConfig
+		preparedAttributes[name] = tftypes.NewValue(attributeType, nil)
 	}
 
+	preparedConfig, err := tfprotov6.NewDynamicValue(providerType, tftypes.NewValue(providerType, preparedAttributes))
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to create merged ValidateProviderConfig PreparedConfig: %w", err)
+	}
+
+	resp.PreparedConfig = &preparedConfig
+
 	return resp, nil
 }
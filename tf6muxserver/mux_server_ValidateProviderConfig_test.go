@@ -261,6 +261,23 @@ func TestMuxServerValidateProviderConfig(t *testing.T) {
 				PreparedConfig: &config,
 			},
 		},
+		"PreparedConfig-once-after-nil": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema:                 &configSchema,
+					ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &configSchema,
+					ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{
+						PreparedConfig: &config,
+					},
+				}).ProviderServer,
+			},
+			expectedResponse: &tfprotov6.ValidateProviderConfigResponse{
+				PreparedConfig: &config,
+			},
+		},
 		"PreparedConfig-once-and-error": {
 			servers: []func() tfprotov6.ProviderServer{
 				(&tf6testserver.TestServer{
@@ -349,7 +366,17 @@ func TestMuxServerValidateProviderConfig(t *testing.T) {
 					},
 				}).ProviderServer,
 			},
-			expectedError: fmt.Errorf("got different PrepareProviderConfig PreparedConfig response from multiple servers, not sure which to use"),
+			expectedResponse: &tfprotov6.ValidateProviderConfigResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "Conflicting Prepared Provider Configuration",
+						Detail: "Multiple servers returned different prepared provider configurations, so the muxed server cannot choose one. " +
+							"This is always an issue in the provider implementation and should be reported to the provider developers.\n\n" +
+							"The prepared provider configuration of *tf6testserver.TestServer differs from: *tf6testserver.TestServer",
+					},
+				},
+			},
 		},
 		"PreparedConfig-multiple-equal": {
 			servers: []func() tfprotov6.ProviderServer{
@@ -417,3 +444,559 @@ func TestMuxServerValidateProviderConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestMuxServerValidateProviderConfigProviderSchemaMerge(t *testing.T) {
+	t.Parallel()
+
+	server1Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"account_id": tftypes.String,
+		},
+	}
+	server2Type := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"region": tftypes.String,
+		},
+	}
+	mergedType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"account_id": tftypes.String,
+			"region":     tftypes.String,
+		},
+	}
+
+	server1Config, err := tfprotov6.NewDynamicValue(server1Type, tftypes.NewValue(server1Type, map[string]tftypes.Value{
+		"account_id": tftypes.NewValue(tftypes.String, "123456"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	server2Config, err := tfprotov6.NewDynamicValue(server2Type, tftypes.NewValue(server2Type, map[string]tftypes.Value{
+		"region": tftypes.NewValue(tftypes.String, "us-east-1"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	mergedConfig, err := tfprotov6.NewDynamicValue(mergedType, tftypes.NewValue(mergedType, map[string]tftypes.Value{
+		"account_id": tftypes.NewValue(tftypes.String, "123456"),
+		"region":     tftypes.NewValue(tftypes.String, "us-east-1"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	servers := []func() tfprotov6.ProviderServer{
+		(&tf6testserver.TestServer{
+			ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{
+				PreparedConfig: &server1Config,
+			},
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{
+							Name:     "account_id",
+							Type:     tftypes.String,
+							Required: true,
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+		(&tf6testserver.TestServer{}).ProviderServer,
+		(&tf6testserver.TestServer{
+			ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "test warning summary",
+						Detail:   "test warning details",
+					},
+				},
+				PreparedConfig: &server2Config,
+			},
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{
+							Name:     "region",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{tf6muxserver.WithProviderSchemaMerge(true)}, servers...)
+
+	if err != nil {
+		t.Fatalf("error setting up muxer: %s", err)
+	}
+
+	got, err := muxServer.ProviderServer().ValidateProviderConfig(context.Background(), &tfprotov6.ValidateProviderConfigRequest{
+		Config: &mergedConfig,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedResponse := &tfprotov6.ValidateProviderConfigResponse{
+		Diagnostics: []*tfprotov6.Diagnostic{
+			{
+				Severity: tfprotov6.DiagnosticSeverityWarning,
+				Summary:  "test warning summary",
+				Detail:   "test warning details",
+			},
+		},
+		PreparedConfig: &mergedConfig,
+	}
+
+	if !cmp.Equal(got, expectedResponse) {
+		t.Errorf("unexpected response: %s", cmp.Diff(got, expectedResponse))
+	}
+
+	if !servers[0]().(*tf6testserver.TestServer).ValidateProviderConfigCalled {
+		t.Errorf("expected ValidateProviderConfig to be called on server1")
+	}
+
+	if servers[1]().(*tf6testserver.TestServer).ValidateProviderConfigCalled {
+		t.Errorf("unexpected ValidateProviderConfig called on server2")
+	}
+
+	if !servers[2]().(*tf6testserver.TestServer).ValidateProviderConfigCalled {
+		t.Errorf("expected ValidateProviderConfig to be called on server3")
+	}
+}
+
+// configDecodingServer is a server which decodes the provider configuration
+// with its own provider schema, as real servers do, and returns it as the
+// PreparedConfig.
+type configDecodingServer struct {
+	*tf6testserver.TestServer
+}
+
+func (s configDecodingServer) ValidateProviderConfig(ctx context.Context, req *tfprotov6.ValidateProviderConfigRequest) (*tfprotov6.ValidateProviderConfigResponse, error) {
+	s.ValidateProviderConfigCalled = true
+
+	if _, err := req.Config.Unmarshal(s.ProviderSchema.ValueType()); err != nil {
+		return nil, fmt.Errorf("unable to decode config: %w", err)
+	}
+
+	return &tfprotov6.ValidateProviderConfigResponse{
+		PreparedConfig: req.Config,
+	}, nil
+}
+
+func TestMuxServerValidateProviderConfigProviderSchemaMergeDecoding(t *testing.T) {
+	t.Parallel()
+
+	mergedType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"account_id": tftypes.String,
+			"region":     tftypes.String,
+		},
+	}
+
+	mergedConfig, err := tfprotov6.NewDynamicValue(mergedType, tftypes.NewValue(mergedType, map[string]tftypes.Value{
+		"account_id": tftypes.NewValue(tftypes.String, "123456"),
+		"region":     tftypes.NewValue(tftypes.String, "us-east-1"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	testServers := []*tf6testserver.TestServer{
+		{
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{
+							Name:     "account_id",
+							Type:     tftypes.String,
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+		{
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{
+							Name:     "region",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var servers []func() tfprotov6.ProviderServer
+
+	for _, testServer := range testServers {
+		server := configDecodingServer{
+			TestServer: testServer,
+		}
+
+		servers = append(servers, func() tfprotov6.ProviderServer {
+			return server
+		})
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{tf6muxserver.WithProviderSchemaMerge(true)}, servers...)
+
+	if err != nil {
+		t.Fatalf("error setting up muxer: %s", err)
+	}
+
+	got, err := muxServer.ProviderServer().ValidateProviderConfig(context.Background(), &tfprotov6.ValidateProviderConfigRequest{
+		Config: &mergedConfig,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedResponse := &tfprotov6.ValidateProviderConfigResponse{
+		PreparedConfig: &mergedConfig,
+	}
+
+	if !cmp.Equal(got, expectedResponse) {
+		t.Errorf("unexpected response: %s", cmp.Diff(got, expectedResponse))
+	}
+
+	for num, testServer := range testServers {
+		if !testServer.ValidateProviderConfigCalled {
+			t.Errorf("expected ValidateProviderConfig to be called on server%d", num+1)
+		}
+	}
+}
+
+func TestMuxServerValidateProviderConfigSchemaOwnership(t *testing.T) {
+	t.Parallel()
+
+	config, err := tfprotov6.NewDynamicValue(tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"hello": tftypes.String,
+		},
+	}, tftypes.NewValue(tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"hello": tftypes.String,
+		},
+	}, map[string]tftypes.Value{
+		"hello": tftypes.NewValue(tftypes.String, "world"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	configSchema := tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name: "hello",
+					Type: tftypes.String,
+				},
+			},
+		},
+	}
+
+	metaSchema := tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name: "module_id",
+					Type: tftypes.String,
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		options            []tf6muxserver.ServerOption
+		providerSchema     *tfprotov6.Schema
+		providerMetaSchema *tfprotov6.Schema
+		expectedError      error
+		expectedResponse   *tfprotov6.ValidateProviderConfigResponse
+	}{
+		"neither": {
+			expectedError: fmt.Errorf("unable to compare ValidateProviderConfig PreparedConfig responses: no server declared a provider schema or provider meta schema"),
+		},
+		"neither-provider-schema-merge": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			expectedError: fmt.Errorf("unable to compare ValidateProviderConfig PreparedConfig responses: no server declared a provider schema or provider meta schema"),
+		},
+		"provider": {
+			providerSchema: &configSchema,
+			expectedResponse: &tfprotov6.ValidateProviderConfigResponse{
+				PreparedConfig: &config,
+			},
+		},
+		"provider-and-provider-meta": {
+			providerSchema:     &configSchema,
+			providerMetaSchema: &metaSchema,
+			expectedResponse: &tfprotov6.ValidateProviderConfigResponse{
+				PreparedConfig: &config,
+			},
+		},
+		"provider-meta": {
+			providerMetaSchema: &metaSchema,
+			expectedError:      fmt.Errorf("unable to compare ValidateProviderConfig PreparedConfig responses: no server declared a provider schema, only a provider meta schema, which does not apply to provider configuration"),
+		},
+		"provider-meta-provider-schema-merge": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			providerMetaSchema: &metaSchema,
+			expectedError:      fmt.Errorf("unable to compare ValidateProviderConfig PreparedConfig responses: no server declared a provider schema, only a provider meta schema, which does not apply to provider configuration"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{
+						PreparedConfig: &config,
+					},
+					ProviderSchema: testCase.providerSchema,
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{
+						PreparedConfig: &config,
+					},
+					ProviderMetaSchema: testCase.providerMetaSchema,
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			got, err := muxServer.ProviderServer().ValidateProviderConfig(context.Background(), &tfprotov6.ValidateProviderConfigRequest{
+				Config: &config,
+			})
+
+			if err != nil {
+				if testCase.expectedError == nil {
+					t.Fatalf("wanted no error, got error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError.Error()) {
+					t.Fatalf("wanted error %q, got error: %s", testCase.expectedError.Error(), err.Error())
+				}
+			}
+
+			if err == nil && testCase.expectedError != nil {
+				t.Fatalf("got no error, wanted err: %s", testCase.expectedError)
+			}
+
+			if !cmp.Equal(got, testCase.expectedResponse) {
+				t.Errorf("unexpected response: %s", cmp.Diff(got, testCase.expectedResponse))
+			}
+		})
+	}
+}
+
+func TestMuxServerValidateProviderConfigDiagnosticProvenance(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		diagnosticProvenance bool
+		expectedDiagnostics  []*tfprotov6.Diagnostic
+	}{
+		"disabled": {
+			expectedDiagnostics: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityWarning,
+					Summary:  "warning summary",
+					Detail:   "warning detail",
+				},
+				{
+					Severity: tfprotov6.DiagnosticSeverityError,
+					Summary:  "error summary",
+					Detail:   "error detail",
+				},
+			},
+		},
+		"enabled": {
+			diagnosticProvenance: true,
+			expectedDiagnostics: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityWarning,
+					Summary:  "warning summary",
+					Detail:   "warning detail (via *tf6testserver.TestServer)",
+				},
+				{
+					Severity: tfprotov6.DiagnosticSeverityError,
+					Summary:  "error summary",
+					Detail:   "error detail (via *tf6testserver.TestServer)",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{
+						Diagnostics: []*tfprotov6.Diagnostic{
+							{
+								Severity: tfprotov6.DiagnosticSeverityWarning,
+								Summary:  "warning summary",
+								Detail:   "warning detail",
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{
+						Diagnostics: []*tfprotov6.Diagnostic{
+							{
+								Severity: tfprotov6.DiagnosticSeverityError,
+								Summary:  "error summary",
+								Detail:   "error detail",
+							},
+						},
+					},
+				}).ProviderServer,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{tf6muxserver.WithDiagnosticProvenance(testCase.diagnosticProvenance)}, servers...)
+
+			if err != nil {
+				t.Fatalf("error setting up muxer: %s", err)
+			}
+
+			got, err := muxServer.ProviderServer().ValidateProviderConfig(context.Background(), &tfprotov6.ValidateProviderConfigRequest{})
+
+			if err != nil {
+				t.Fatalf("error calling ValidateProviderConfig: %s", err)
+			}
+
+			if diff := cmp.Diff(got.Diagnostics, testCase.expectedDiagnostics); diff != "" {
+				t.Errorf("unexpected diagnostics difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMuxServerValidateProviderConfigServerResponseUnmodified(t *testing.T) {
+	t.Parallel()
+
+	configType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"hello": tftypes.String,
+		},
+	}
+
+	config1, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, map[string]tftypes.Value{
+		"hello": tftypes.NewValue(tftypes.String, "world"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	config2, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, map[string]tftypes.Value{
+		"hello": tftypes.NewValue(tftypes.String, "goodbye"),
+	}))
+
+	if err != nil {
+		t.Fatalf("error constructing config: %s", err)
+	}
+
+	configSchema := &tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name: "hello",
+					Type: tftypes.String,
+				},
+			},
+		},
+	}
+
+	warning := &tfprotov6.Diagnostic{
+		Severity: tfprotov6.DiagnosticSeverityWarning,
+		Summary:  "test warning summary",
+	}
+
+	// The slice has spare capacity, so appending to it in place would not
+	// change its length, only its backing array.
+	diagnostics := make([]*tfprotov6.Diagnostic, 1, 3)
+	diagnostics[0] = warning
+
+	serverResp := &tfprotov6.ValidateProviderConfigResponse{
+		Diagnostics:    diagnostics,
+		PreparedConfig: &config1,
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(
+		context.Background(),
+		(&tf6testserver.TestServer{
+			ValidateProviderConfigResponse: serverResp,
+			ProviderSchema:                 configSchema,
+		}).ProviderServer,
+		(&tf6testserver.TestServer{
+			ValidateProviderConfigResponse: &tfprotov6.ValidateProviderConfigResponse{
+				Diagnostics:    []*tfprotov6.Diagnostic{warning},
+				PreparedConfig: &config2,
+			},
+			ProviderSchema: configSchema,
+		}).ProviderServer,
+	)
+
+	if err != nil {
+		t.Fatalf("error setting up muxer: %s", err)
+	}
+
+	got, err := muxServer.ProviderServer().ValidateProviderConfig(context.Background(), &tfprotov6.ValidateProviderConfigRequest{
+		Config: &config1,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got == serverResp {
+		t.Errorf("expected a new response, got the server response")
+	}
+
+	if len(got.Diagnostics) != 3 {
+		t.Errorf("expected 3 diagnostics, got %d", len(got.Diagnostics))
+	}
+
+	if serverResp.PreparedConfig != &config1 {
+		t.Errorf("expected server response PreparedConfig to be unmodified")
+	}
+
+	if diff := cmp.Diff(diagnostics[:cap(diagnostics)], []*tfprotov6.Diagnostic{warning, nil, nil}); diff != "" {
+		t.Errorf("expected server response Diagnostics to be unmodified: %s", diff)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
@@ -64,3 +65,90 @@ func TestMuxServerValidateResourceConfig(t *testing.T) {
 		t.Errorf("expected test_resource_server2 ValidateResourceConfig to be called on server2")
 	}
 }
+
+func TestMuxServerValidateResourceConfigFanOut(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	servers := []func() tfprotov6.ProviderServer{
+		(&tf6testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource_server1": {},
+			},
+			ValidateResourceConfigResponse: &tfprotov6.ValidateResourceConfigResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "server1 warning",
+					},
+				},
+			},
+		}).ProviderServer,
+		(&tf6testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource_server2": {},
+			},
+			ValidateResourceConfigResponse: &tfprotov6.ValidateResourceConfigResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "server2 error",
+					},
+				},
+			},
+		}).ProviderServer,
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{
+		tf6muxserver.WithValidationFanOut([]string{"test_resource_server2"}),
+	}, servers...)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	resp, err := muxServer.ProviderServer().ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: "test_resource_server2",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedDiagnostics := []*tfprotov6.Diagnostic{
+		{
+			Severity: tfprotov6.DiagnosticSeverityWarning,
+			Summary:  "server1 warning",
+		},
+		{
+			Severity: tfprotov6.DiagnosticSeverityError,
+			Summary:  "server2 error",
+		},
+	}
+
+	if diff := cmp.Diff(resp.Diagnostics, expectedDiagnostics); diff != "" {
+		t.Errorf("unexpected diagnostics difference: %s", diff)
+	}
+
+	for serverIndex, serverFunc := range servers {
+		if !serverFunc().(*tf6testserver.TestServer).ValidateResourceConfigCalled["test_resource_server2"] {
+			t.Errorf("expected test_resource_server2 ValidateResourceConfig to be called on server %d", serverIndex)
+		}
+	}
+
+	_, err = muxServer.ProviderServer().ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: "test_resource_server1",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !servers[0]().(*tf6testserver.TestServer).ValidateResourceConfigCalled["test_resource_server1"] {
+		t.Errorf("expected test_resource_server1 ValidateResourceConfig to be called on server1")
+	}
+
+	if servers[1]().(*tf6testserver.TestServer).ValidateResourceConfigCalled["test_resource_server1"] {
+		t.Errorf("unexpected test_resource_server1 ValidateResourceConfig called on server2")
+	}
+}
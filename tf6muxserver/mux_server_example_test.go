@@ -3,6 +3,7 @@ package tf6muxserver_test
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
@@ -39,3 +40,41 @@ func ExampleNewMuxServer() {
 		log.Fatalln(err.Error())
 	}
 }
+
+func ExampleWithMiddleware() {
+	ctx := context.Background()
+	providers := []func() tfprotov6.ProviderServer{
+		// Example terraform-plugin-framework ProviderServer function
+		// func() tfprotov6.ProviderServer {
+		//   return tfsdk.NewProtocol6Server(frameworkprovider.New("version")())
+		// },
+		//
+		// Example terraform-plugin-go ProviderServer function
+		// goprovider.Provider(),
+	}
+
+	// Log the duration and any error of every downstream server call.
+	loggingMiddleware := func(next tf6muxserver.RPCHandler) tf6muxserver.RPCHandler {
+		return func(ctx context.Context, rpc string, req interface{}) (interface{}, error) {
+			start := time.Now()
+			resp, err := next(ctx, rpc, req)
+
+			log.Printf("%s completed in %s (error: %v)", rpc, time.Since(start), err)
+
+			return resp, err
+		}
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{tf6muxserver.WithMiddleware(loggingMiddleware)}, providers...)
+
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	// Use the result to start a muxed provider
+	err = tf6server.Serve("registry.terraform.io/namespace/example", muxServer.ProviderServer)
+
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+}
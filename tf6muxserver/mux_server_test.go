@@ -1,13 +1,18 @@
 package tf6muxserver_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklogtest"
 	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
 	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
 )
@@ -142,6 +147,116 @@ func TestNewMuxServer(t *testing.T) {
 			},
 			expectedError: fmt.Errorf("got a different provider schema across servers. Provider schemas must be identical across providers"),
 		},
+		"provider-nested-attribute-ordering": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name: "endpoint",
+									NestedType: &tfprotov6.SchemaObject{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name:     "region",
+												Type:     tftypes.String,
+												Optional: true,
+											},
+											{
+												Name:     "url",
+												Type:     tftypes.String,
+												Optional: true,
+											},
+										},
+										Nesting: tfprotov6.SchemaObjectNestingModeSingle,
+									},
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name: "endpoint",
+									NestedType: &tfprotov6.SchemaObject{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name:     "url",
+												Type:     tftypes.String,
+												Optional: true,
+											},
+											{
+												Name:     "region",
+												Type:     tftypes.String,
+												Optional: true,
+											},
+										},
+										Nesting: tfprotov6.SchemaObjectNestingModeSingle,
+									},
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+		"provider-nil-block": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Version: 1,
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Version: 1,
+						Block:   &tfprotov6.SchemaBlock{},
+					},
+				}).ProviderServer,
+			},
+		},
+		"provider-nil-empty-slices": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Version: 1,
+						Block: &tfprotov6.SchemaBlock{
+							Version: 1,
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Version: 1,
+						Block: &tfprotov6.SchemaBlock{
+							Version:    1,
+							Attributes: []*tfprotov6.SchemaAttribute{},
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Block: &tfprotov6.SchemaBlock{
+										Attributes: []*tfprotov6.SchemaAttribute{},
+										BlockTypes: []*tfprotov6.SchemaNestedBlock{},
+									},
+									Nesting: tfprotov6.SchemaNestedBlockNestingModeList,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
 		"provider-ordering": {
 			servers: []func() tfprotov6.ProviderServer{
 				(&tf6testserver.TestServer{
@@ -573,3 +688,1276 @@ func TestNewMuxServer(t *testing.T) {
 		})
 	}
 }
+
+func TestNewMuxServerWithOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options       []tf6muxserver.ServerOption
+		servers       []func() tfprotov6.ProviderServer
+		expectedError error
+	}{
+		"provider-meta-schema-merge-attribute-conflict": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderMetaSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderMetaSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "module_name",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderMetaSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "module_name",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("provider meta schema attribute \"module_name\" is defined by multiple servers; only one definition allowed"),
+		},
+		"provider-meta-schema-merge-disjoint": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderMetaSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderMetaSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "module_name",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderMetaSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "module_version",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+		"provider-schema-merge-attribute-conflict": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("provider schema attribute \"account_id\" is defined by multiple servers; only one definition allowed"),
+		},
+		"provider-schema-merge-block-mismatch": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block:    &tfprotov6.SchemaBlock{},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "feature",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeSet,
+									Block:    &tfprotov6.SchemaBlock{},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("provider schema nested block \"feature\" nesting mode SET does not match nesting mode LIST from other servers"),
+		},
+		"provider-schema-merge-block-attribute-conflict": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "retry",
+									Type:     tftypes.Number,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "retry",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block:    &tfprotov6.SchemaBlock{},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("provider schema nested block \"retry\" has the same name as an attribute from other servers"),
+		},
+		"provider-schema-merge-block-attributes-mismatch": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "assume_role",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block: &tfprotov6.SchemaBlock{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name:     "role_arn",
+												Type:     tftypes.String,
+												Required: true,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "assume_role",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block: &tfprotov6.SchemaBlock{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name:     "role_arn",
+												Type:     tftypes.String,
+												Optional: true,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("provider schema nested block \"assume_role\" is defined differently by multiple servers"),
+		},
+		"provider-schema-merge-block-items-mismatch": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "assume_role",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block:    &tfprotov6.SchemaBlock{},
+									MaxItems: 1,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "assume_role",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block:    &tfprotov6.SchemaBlock{},
+									MinItems: 1,
+									MaxItems: 1,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("provider schema nested block \"assume_role\" items limits (min 1, max 1) do not match items limits (min 0, max 1) from other servers"),
+		},
+		"provider-schema-merge-block-version-mismatch": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Version: 1,
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Version: 2,
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("provider schema block version 2 does not match block version 1 from other servers"),
+		},
+		"provider-schema-merge-disjoint": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "region",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+		"provider-schema-merge-disjoint-blocks": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "assume_role",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeList,
+									Block:    &tfprotov6.SchemaBlock{},
+									MaxItems: 1,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "retry",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeSingle,
+									Block:    &tfprotov6.SchemaBlock{},
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+		"provider-schema-merge-version-mismatch": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Version: 1,
+						Block:   &tfprotov6.SchemaBlock{},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Version: 2,
+						Block:   &tfprotov6.SchemaBlock{},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("provider schema version 2 does not match version 1 from other servers"),
+		},
+		"require-shared-provider-schema-divergent": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithConflictResolution(tf6muxserver.ConflictResolutionFirstServer),
+				tf6muxserver.WithRequireSharedProviderSchema(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "region",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("server 1 (*tf6testserver.TestServer) declares a provider schema different from server 0 (*tf6testserver.TestServer), however all servers must declare the identical provider schema"),
+		},
+		"require-shared-provider-schema-identical": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithRequireSharedProviderSchema(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+			},
+		},
+		"require-shared-provider-schema-missing": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithRequireSharedProviderSchema(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "account_id",
+									Type:     tftypes.String,
+									Required: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			},
+			expectedError: fmt.Errorf("server 1 (*tf6testserver.TestServer) declares no provider schema, however all servers must declare the identical provider schema"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, testCase.servers...)
+
+			if err != nil {
+				if testCase.expectedError == nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError.Error()) {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+			}
+
+			if err == nil && testCase.expectedError != nil {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+		})
+	}
+}
+
+func TestMuxServerNilRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, (&tf6testserver.TestServer{
+		DataSourceSchemas: map[string]*tfprotov6.Schema{
+			"test_data_source": {},
+		},
+		ResourceSchemas: map[string]*tfprotov6.Schema{
+			"test_resource": {},
+		},
+	}).ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	server := muxServer.ProviderServer()
+
+	testCases := map[string]func() error{
+		"ApplyResourceChange": func() error {
+			_, err := server.ApplyResourceChange(ctx, nil)
+			return err
+		},
+		"ImportResourceState": func() error {
+			_, err := server.ImportResourceState(ctx, nil)
+			return err
+		},
+		"PlanResourceChange": func() error {
+			_, err := server.PlanResourceChange(ctx, nil)
+			return err
+		},
+		"ReadDataSource": func() error {
+			_, err := server.ReadDataSource(ctx, nil)
+			return err
+		},
+		"ReadResource": func() error {
+			_, err := server.ReadResource(ctx, nil)
+			return err
+		},
+		"UpgradeResourceState": func() error {
+			_, err := server.UpgradeResourceState(ctx, nil)
+			return err
+		},
+		"ValidateDataResourceConfig": func() error {
+			_, err := server.ValidateDataResourceConfig(ctx, nil)
+			return err
+		},
+		"ValidateResourceConfig": func() error {
+			_, err := server.ValidateResourceConfig(ctx, nil)
+			return err
+		},
+	}
+
+	for name, call := range testCases {
+		name, call := name, call
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := call()
+
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+
+			expectedError := fmt.Sprintf("unable to route %s: request is nil", name)
+
+			if err.Error() != expectedError {
+				t.Errorf("expected error %q, got: %s", expectedError, err)
+			}
+		})
+	}
+}
+
+func TestMuxServerEmptyTypeName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, (&tf6testserver.TestServer{
+		DataSourceSchemas: map[string]*tfprotov6.Schema{
+			"test_data_source": {},
+		},
+		ResourceSchemas: map[string]*tfprotov6.Schema{
+			"test_resource": {},
+		},
+	}).ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	server := muxServer.ProviderServer()
+
+	testCases := map[string]func() error{
+		"ApplyResourceChange": func() error {
+			_, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{})
+			return err
+		},
+		"ImportResourceState": func() error {
+			_, err := server.ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{})
+			return err
+		},
+		"PlanResourceChange": func() error {
+			_, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{})
+			return err
+		},
+		"ReadDataSource": func() error {
+			_, err := server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{})
+			return err
+		},
+		"ReadResource": func() error {
+			_, err := server.ReadResource(ctx, &tfprotov6.ReadResourceRequest{})
+			return err
+		},
+		"UpgradeResourceState": func() error {
+			_, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{})
+			return err
+		},
+		"ValidateDataResourceConfig": func() error {
+			_, err := server.ValidateDataResourceConfig(ctx, &tfprotov6.ValidateDataResourceConfigRequest{})
+			return err
+		},
+		"ValidateResourceConfig": func() error {
+			_, err := server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{})
+			return err
+		},
+	}
+
+	for name, call := range testCases {
+		name, call := name, call
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := call()
+
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+
+			expectedError := fmt.Sprintf("unable to route %s: request missing TypeName", name)
+
+			if err.Error() != expectedError {
+				t.Errorf("expected error %q, got: %s", expectedError, err)
+			}
+		})
+	}
+}
+
+func TestMuxServerUnsupportedTypeName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, (&tf6testserver.TestServer{
+		DataSourceSchemas: map[string]*tfprotov6.Schema{
+			"test_data_source": {},
+		},
+		ResourceSchemas: map[string]*tfprotov6.Schema{
+			"test_resource": {},
+		},
+	}).ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	server := muxServer.ProviderServer()
+
+	testCases := map[string]func() error{
+		"ApplyResourceChange": func() error {
+			_, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+				TypeName: "test_unsupported",
+			})
+			return err
+		},
+		"ImportResourceState": func() error {
+			_, err := server.ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{
+				TypeName: "test_unsupported",
+			})
+			return err
+		},
+		"PlanResourceChange": func() error {
+			_, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName: "test_unsupported",
+			})
+			return err
+		},
+		"ReadDataSource": func() error {
+			_, err := server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+				TypeName: "test_unsupported",
+			})
+			return err
+		},
+		"ReadResource": func() error {
+			_, err := server.ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+				TypeName: "test_unsupported",
+			})
+			return err
+		},
+		"UpgradeResourceState": func() error {
+			_, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "test_unsupported",
+			})
+			return err
+		},
+		"ValidateDataResourceConfig": func() error {
+			_, err := server.ValidateDataResourceConfig(ctx, &tfprotov6.ValidateDataResourceConfigRequest{
+				TypeName: "test_unsupported",
+			})
+			return err
+		},
+		"ValidateResourceConfig": func() error {
+			_, err := server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+				TypeName: "test_unsupported",
+			})
+			return err
+		},
+	}
+
+	for name, call := range testCases {
+		name, call := name, call
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := call()
+
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+
+			expectedError := `"test_unsupported" isn't supported by any servers`
+
+			if err.Error() != expectedError {
+				t.Errorf("expected error %q, got: %s", expectedError, err)
+			}
+
+			if misses := muxServer.Stats().Misses[name]; misses != 1 {
+				t.Errorf("expected 1 %s routing miss, got: %d", name, misses)
+			}
+		})
+	}
+}
+
+func TestMuxServerProviderServerServerReuse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		serverReuse            bool
+		expectedCreated        int
+		expectedReadResourceOn []int
+	}{
+		"disabled": {
+			serverReuse:            false,
+			expectedCreated:        3,
+			expectedReadResourceOn: []int{1, 2},
+		},
+		"enabled": {
+			serverReuse:            true,
+			expectedCreated:        1,
+			expectedReadResourceOn: []int{0},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			var created []*tf6testserver.TestServer
+
+			serverFunc := func() tfprotov6.ProviderServer {
+				server := &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}
+				created = append(created, server)
+
+				return server
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{tf6muxserver.WithServerReuse(testCase.serverReuse)}, serverFunc)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			for i := 0; i < 2; i++ {
+				_, err := muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+					TypeName: "test_resource",
+				})
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			if len(created) != testCase.expectedCreated {
+				t.Fatalf("expected %d servers created, got %d", testCase.expectedCreated, len(created))
+			}
+
+			for _, index := range testCase.expectedReadResourceOn {
+				if !created[index].ReadResourceCalled["test_resource"] {
+					t.Errorf("expected ReadResource to be called on server%d", index+1)
+				}
+			}
+		})
+	}
+}
+
+func TestNewMuxServerEmptyServer(t *testing.T) {
+	t.Parallel()
+
+	warningMessage := "server declares no resources, data sources, or provider schema, which is usually caused by passing the wrong server function"
+
+	testCases := map[string]struct {
+		emptyServerError bool
+		servers          []func() tfprotov6.ProviderServer
+		expectedError    error
+		expectedEntries  []map[string]interface{}
+	}{
+		"empty-server": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{}).ProviderServer,
+			},
+			expectedEntries: []map[string]interface{}{
+				{
+					"@level":              "warn",
+					"@message":            warningMessage,
+					"@module":             "sdk.mux",
+					"tf_mux_provider":     "*tf6testserver.TestServer",
+					"tf_mux_server_index": float64(1),
+				},
+			},
+		},
+		"empty-server-error": {
+			emptyServerError: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{}).ProviderServer,
+			},
+			expectedError:   fmt.Errorf("server 1 (*tf6testserver.TestServer) declares no resources, data sources, or provider schema"),
+			expectedEntries: []map[string]interface{}{},
+		},
+		"provider-schema-only": {
+			emptyServerError: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{},
+				}).ProviderServer,
+			},
+			expectedEntries: []map[string]interface{}{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+
+			ctx := tfsdklogtest.RootLogger(context.Background(), &output)
+
+			_, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{tf6muxserver.WithEmptyServerError(testCase.emptyServerError)}, testCase.servers...)
+
+			if err != nil {
+				if testCase.expectedError == nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError.Error()) {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+			}
+
+			if err == nil && testCase.expectedError != nil {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			entries, err := tfsdklogtest.MultilineJSONDecode(&output)
+
+			if err != nil {
+				t.Fatalf("unable to read log entries: %s", err)
+			}
+
+			gotEntries := []map[string]interface{}{}
+
+			for _, entry := range entries {
+				if entry["@message"] != warningMessage {
+					continue
+				}
+
+				delete(entry, "@caller")
+				delete(entry, "@timestamp")
+				delete(entry, "tf_mux_version")
+
+				gotEntries = append(gotEntries, entry)
+			}
+
+			if diff := cmp.Diff(gotEntries, testCase.expectedEntries); diff != "" {
+				t.Errorf("unexpected log entries: %s", diff)
+			}
+		})
+	}
+}
+
+func TestNewMuxServerRequireProviderSchema(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		requireProviderSchema bool
+		servers               []func() tfprotov6.ProviderServer
+		expectedError         error
+	}{
+		"allowed": {
+			requireProviderSchema: false,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			},
+		},
+		"required-declared": {
+			requireProviderSchema: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{},
+				}).ProviderServer,
+			},
+		},
+		"required-missing": {
+			requireProviderSchema: true,
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ProviderMetaSchema: &tfprotov6.Schema{},
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+				}).ProviderServer,
+			},
+			expectedError: &tf6muxserver.NoProviderSchemaError{
+				ServerCount: 2,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{tf6muxserver.WithRequireProviderSchema(testCase.requireProviderSchema)}, testCase.servers...)
+
+			if diff := cmp.Diff(err, testCase.expectedError); diff != "" {
+				t.Errorf("unexpected error difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestNewMuxServerBestEffortSchema(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		bestEffortSchema bool
+		expectedError    error
+		expectedResp     *tfprotov6.GetProviderSchemaResponse
+	}{
+		"disabled": {
+			bestEffortSchema: false,
+			expectedError:    fmt.Errorf("error retrieving schema for *tf6testserver.TestServer:\n\n\tAttribute: \n\tSummary: test error summary\n\tDetail: test error detail"),
+		},
+		"enabled": {
+			bestEffortSchema: true,
+			expectedResp: &tfprotov6.GetProviderSchemaResponse{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource_server2": {},
+				},
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "Provider Server Excluded",
+						Detail: "Server 0 (*tf6testserver.TestServer) failed to return its schema and was excluded from the provider. " +
+							"Resources and data sources implemented by the server are unavailable.\n\n" +
+							"error retrieving schema for *tf6testserver.TestServer:\n\n\tAttribute: \n\tSummary: test error summary\n\tDetail: test error detail",
+					},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			failingServer := &tf6testserver.TestServer{
+				GetProviderSchemaDiagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "test error summary",
+						Detail:   "test error detail",
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource_server1": {},
+				},
+			}
+			healthyServer := &tf6testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource_server2": {},
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{
+				tf6muxserver.WithBestEffortSchema(testCase.bestEffortSchema),
+			}, failingServer.ProviderServer, healthyServer.ProviderServer)
+
+			if err != nil {
+				if testCase.expectedError == nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if err.Error() != testCase.expectedError.Error() {
+					t.Fatalf("expected error %q, got %q", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != nil {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(resp, testCase.expectedResp); diff != "" {
+				t.Errorf("unexpected response difference: %s", diff)
+			}
+
+			_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+				TypeName: "test_resource_server1",
+			})
+
+			if err == nil {
+				t.Errorf("expected error routing resource of excluded server")
+			}
+
+			_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+				TypeName: "test_resource_server2",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !healthyServer.ReadResourceCalled["test_resource_server2"] {
+				t.Errorf("expected test_resource_server2 ReadResource to be called on server2")
+			}
+
+			_, err = muxServer.ProviderServer().ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if failingServer.ConfigureProviderCalled {
+				t.Errorf("unexpected ConfigureProvider called on excluded server")
+			}
+		})
+	}
+}
+
+func TestNewMuxServerWarningsAsErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		warningsAsErrors bool
+		expectedError    error
+	}{
+		"disabled": {
+			warningsAsErrors: false,
+		},
+		"enabled": {
+			warningsAsErrors: true,
+			expectedError:    fmt.Errorf("error retrieving schema for *tf6testserver.TestServer:\n\n\tAttribute: \n\tSummary: test warning summary\n\tDetail: test warning detail"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &tf6testserver.TestServer{
+				GetProviderSchemaDiagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "test warning summary",
+						Detail:   "test warning detail",
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": {},
+				},
+			}
+
+			_, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{
+				tf6muxserver.WithWarningsAsErrors(testCase.warningsAsErrors),
+			}, server.ProviderServer)
+
+			if err != nil {
+				if testCase.expectedError == nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if err.Error() != testCase.expectedError.Error() {
+					t.Fatalf("expected error %q, got %q", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != nil {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+		})
+	}
+}
+
+func TestMuxServerProviderServerConcurrent(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		serverReuse bool
+	}{
+		"server-reuse-disabled": {
+			serverReuse: false,
+		},
+		"server-reuse-enabled": {
+			serverReuse: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			var created int64
+
+			serverFunc := func() tfprotov6.ProviderServer {
+				atomic.AddInt64(&created, 1)
+
+				return &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{tf6muxserver.WithServerReuse(testCase.serverReuse)}, serverFunc)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			const calls = 50
+
+			var wg sync.WaitGroup
+			errs := make(chan error, calls)
+
+			for i := 0; i < calls; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					providerServer := muxServer.ProviderServer()
+
+					resp, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+
+					if err != nil {
+						errs <- err
+
+						return
+					}
+
+					if _, ok := resp.ResourceSchemas["test_resource"]; !ok {
+						errs <- fmt.Errorf("expected test_resource schema")
+
+						return
+					}
+
+					// Only new servers can be called concurrently, as the
+					// test servers are not safe for concurrent use.
+					if testCase.serverReuse {
+						return
+					}
+
+					_, err = providerServer.ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+						TypeName: "test_resource",
+					})
+
+					if err != nil {
+						errs <- err
+					}
+				}()
+			}
+
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Errorf("unexpected error: %s", err)
+			}
+
+			expectedCreated := int64(1)
+
+			if !testCase.serverReuse {
+				expectedCreated += calls
+			}
+
+			if created != expectedCreated {
+				t.Errorf("expected %d servers created, got %d", expectedCreated, created)
+			}
+
+			if hits := muxServer.Stats().Hits["ReadResource"]; !testCase.serverReuse && hits != calls {
+				t.Errorf("expected %d ReadResource hits, got %d", calls, hits)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// PingFunc performs a cheap operation against a server to verify it is
+// healthy, returning an error if it is not. It is configured via the
+// WithPingFunc option and called by Ping.
+type PingFunc func(ctx context.Context, server tfprotov6.ProviderServer) error
+
+// Ping verifies that each server is healthy, such as for a supervisor
+// liveness check detecting a backing server process which has exited, by
+// calling the PingFunc configured via the WithPingFunc option for each server
+// in order. By default, the GetProviderSchema method of each server is called
+// and error Diagnostics are treated as failures. The first failure is
+// returned, annotated with the server index, and the remaining servers are not
+// called.
+func (s muxServer) Ping(ctx context.Context) error {
+	ctx = logging.InitContext(ctx)
+
+	ping := s.options.pingFunc
+
+	if ping == nil {
+		ping = pingServerSchema
+	}
+
+	for serverIndex, server := range s.currentServers() {
+		ctx = logging.Tfprotov6ProviderServerContext(ctx, server)
+		logging.MuxTrace(ctx, "pinging downstream server", map[string]interface{}{
+			logging.KeyTfMuxServerIndex: serverIndex,
+		})
+
+		if err := ping(ctx, server); err != nil {
+			return fmt.Errorf("error pinging server %d (%T): %w", serverIndex, server, err)
+		}
+	}
+
+	return nil
+}
+
+// pingServerSchema is the default PingFunc, which calls the GetProviderSchema
+// method of the server.
+func pingServerSchema(ctx context.Context, server tfprotov6.ProviderServer) error {
+	_, err := getServerSchema(ctx, server)
+
+	return err
+}
@@ -0,0 +1,98 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestMuxServerPing(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options       []tf6muxserver.ServerOption
+		failingServer bool
+		expectedError string
+	}{
+		"failing-server": {
+			failingServer: true,
+			expectedError: "error pinging server 1 (*tf6testserver.TestServer): error retrieving schema for *tf6testserver.TestServer",
+		},
+		"healthy": {},
+		"ping-func": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithPingFunc(func(_ context.Context, server tfprotov6.ProviderServer) error {
+					if len(server.(*tf6testserver.TestServer).ResourceSchemas) == 0 {
+						return errors.New("no resources")
+					}
+
+					return nil
+				}),
+			},
+			expectedError: "error pinging server 1 (*tf6testserver.TestServer): no resources",
+		},
+		"ping-func-failing-server": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithPingFunc(func(_ context.Context, _ tfprotov6.ProviderServer) error {
+					return nil
+				}),
+			},
+			failingServer: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf6testserver.TestServer{
+				{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				},
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), testCase.options, servers[0].ProviderServer, servers[1].ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if testCase.failingServer {
+				servers[1].GetProviderSchemaDiagnostics = []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "test error summary",
+					},
+				}
+			}
+
+			err = muxServer.Ping(context.Background())
+
+			if err != nil {
+				if testCase.expectedError == "" || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// providerMetaProjection is the muxed provider meta schema and the provider
+// meta schema of the server handling a request, used to project the request
+// ProviderMeta when the WithProviderMetaSchemaMerge option is enabled.
+type providerMetaProjection struct {
+	schema       *tfprotov6.Schema
+	serverSchema *tfprotov6.Schema
+}
+
+// resourceProviderMetaProjection returns the provider meta projection for the
+// server implementing the resource type, or nil if the request ProviderMeta
+// is not projected.
+func (s muxServer) resourceProviderMetaProjection(typeName string) *providerMetaProjection {
+	if !s.options.providerMetaSchemaMerge || s.providerMetaSchema == nil {
+		return nil
+	}
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	serverIndex, ok := s.resourceServerIndex[typeName]
+
+	if !ok {
+		return nil
+	}
+
+	return &providerMetaProjection{
+		schema:       s.providerMetaSchema,
+		serverSchema: s.serverProviderMetaSchemas[serverIndex],
+	}
+}
+
+// dataSourceProviderMetaProjection returns the provider meta projection for
+// the server implementing the data source type, or nil if the request
+// ProviderMeta is not projected.
+func (s muxServer) dataSourceProviderMetaProjection(typeName string) *providerMetaProjection {
+	if !s.options.providerMetaSchemaMerge || s.providerMetaSchema == nil {
+		return nil
+	}
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	serverIndex, ok := s.dataSourceServerIndex[typeName]
+
+	if !ok {
+		return nil
+	}
+
+	return &providerMetaProjection{
+		schema:       s.providerMetaSchema,
+		serverSchema: s.serverProviderMetaSchemas[serverIndex],
+	}
+}
+
+// providerMetaCall returns the server call with the request ProviderMeta
+// projected to the provider meta schema of the server. The call is returned
+// unchanged if the projection is nil.
+func providerMetaCall[Req any, Resp any](projection *providerMetaProjection, call func(context.Context, *Req) (*Resp, error)) func(context.Context, *Req) (*Resp, error) {
+	if projection == nil {
+		return call
+	}
+
+	return func(ctx context.Context, req *Req) (*Resp, error) {
+		projectedReq, err := projection.request(req)
+
+		if err != nil {
+			return nil, fmt.Errorf("error projecting provider meta: %w", err)
+		}
+
+		serverReq, ok := projectedReq.(*Req)
+
+		if !ok {
+			serverReq = req
+		}
+
+		return call(ctx, serverReq)
+	}
+}
+
+// request returns a copy of the request with the ProviderMeta projected to
+// the provider meta schema of the server.
+func (p *providerMetaProjection) request(req interface{}) (interface{}, error) {
+	switch req := req.(type) {
+	case *tfprotov6.ApplyResourceChangeRequest:
+		providerMeta, err := projectConfig(req.ProviderMeta, p.schema, p.serverSchema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result := *req
+		result.ProviderMeta = providerMeta
+
+		return &result, nil
+	case *tfprotov6.PlanResourceChangeRequest:
+		providerMeta, err := projectConfig(req.ProviderMeta, p.schema, p.serverSchema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result := *req
+		result.ProviderMeta = providerMeta
+
+		return &result, nil
+	case *tfprotov6.ReadDataSourceRequest:
+		providerMeta, err := projectConfig(req.ProviderMeta, p.schema, p.serverSchema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result := *req
+		result.ProviderMeta = providerMeta
+
+		return &result, nil
+	case *tfprotov6.ReadResourceRequest:
+		providerMeta, err := projectConfig(req.ProviderMeta, p.schema, p.serverSchema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result := *req
+		result.ProviderMeta = providerMeta
+
+		return &result, nil
+	}
+
+	return req, nil
+}
@@ -0,0 +1,116 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithReadOnly(t *testing.T) {
+	t.Parallel()
+
+	testServer := &tf6testserver.TestServer{
+		DataSourceSchemas: map[string]*tfprotov6.Schema{
+			"test_data_source": {},
+		},
+		ResourceSchemas: map[string]*tfprotov6.Schema{
+			"test_resource": {},
+		},
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), []tf6muxserver.ServerOption{tf6muxserver.WithReadOnly(true)}, testServer.ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	applyResp, err := muxServer.ProviderServer().ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName: "test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ApplyResourceChange error: %s", err)
+	}
+
+	expectedApplyDiagnostics := []*tfprotov6.Diagnostic{
+		{
+			Severity: tfprotov6.DiagnosticSeverityError,
+			Summary:  "Provider in Read-Only Mode",
+			Detail: "The provider is in read-only mode, so the ApplyResourceChange operation for \"test_resource\" is not allowed. " +
+				"Only operations which do not modify infrastructure, such as planning and reading, are available.",
+		},
+	}
+
+	if diff := cmp.Diff(applyResp.Diagnostics, expectedApplyDiagnostics); diff != "" {
+		t.Errorf("unexpected ApplyResourceChange diagnostics difference: %s", diff)
+	}
+
+	importResp, err := muxServer.ProviderServer().ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: "test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ImportResourceState error: %s", err)
+	}
+
+	expectedImportDiagnostics := []*tfprotov6.Diagnostic{
+		{
+			Severity: tfprotov6.DiagnosticSeverityError,
+			Summary:  "Provider in Read-Only Mode",
+			Detail: "The provider is in read-only mode, so the ImportResourceState operation for \"test_resource\" is not allowed. " +
+				"Only operations which do not modify infrastructure, such as planning and reading, are available.",
+		},
+	}
+
+	if diff := cmp.Diff(importResp.Diagnostics, expectedImportDiagnostics); diff != "" {
+		t.Errorf("unexpected ImportResourceState diagnostics difference: %s", diff)
+	}
+
+	if testServer.ApplyResourceChangeCalled["test_resource"] {
+		t.Errorf("unexpected ApplyResourceChange call")
+	}
+
+	if testServer.ImportResourceStateCalled["test_resource"] {
+		t.Errorf("unexpected ImportResourceState call")
+	}
+
+	_, err = muxServer.ProviderServer().PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName: "test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected PlanResourceChange error: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName: "test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ReadResource error: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ReadDataSource(context.Background(), &tfprotov6.ReadDataSourceRequest{
+		TypeName: "test_data_source",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ReadDataSource error: %s", err)
+	}
+
+	if !testServer.PlanResourceChangeCalled["test_resource"] {
+		t.Errorf("expected PlanResourceChange call")
+	}
+
+	if !testServer.ReadResourceCalled["test_resource"] {
+		t.Errorf("expected ReadResource call")
+	}
+
+	if !testServer.ReadDataSourceCalled["test_data_source"] {
+		t.Errorf("expected ReadDataSource call")
+	}
+}
@@ -0,0 +1,143 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// ReplaceServer replaces the server at the given index with a server created
+// by the given function, such as when a backing server process has exited
+// and been restarted in a long-lived host. The server index is the position
+// of the server in the order given to NewMuxServer, excluding servers
+// excluded via the WithBestEffortSchema option. The GetProviderSchema method
+// of the replacement server is called to verify that it declares an identical
+// provider schema and provider meta schema, every type routed to the replaced
+// server with an identical schema, and no types missing from the muxed server
+// schemas, and that it supports the PlanDestroy server capability if the
+// muxed server advertises it and the replacement declares resources, as the
+// muxed server GetProviderSchema response is not changed. After the change,
+// the routing and schemas of the muxed server are checked for consistency, as
+// during muxed server creation.
+//
+// ReplaceServer is safe to call concurrently with other methods. Requests
+// which were already routed complete against the replaced server, while later
+// requests are routed to the replacement server. If the WithServerReuse
+// option is disabled, the function is also used to create the server for
+// later ProviderServer calls.
+func (s muxServer) ReplaceServer(serverIndex int, server func() tfprotov6.ProviderServer) error {
+	if serverIndex < 0 || serverIndex >= len(s.serverFuncs) {
+		return fmt.Errorf("unable to replace server %d: server index is out of range, expected 0 to %d", serverIndex, len(s.serverFuncs)-1)
+	}
+
+	replacement := server()
+
+	ctx := logging.InitContext(context.Background())
+	ctx = logging.Tfprotov6ProviderServerContext(ctx, replacement)
+
+	resp, err := getServerSchema(ctx, replacement)
+
+	if err != nil {
+		return fmt.Errorf("unable to replace server %d: %w", serverIndex, err)
+	}
+
+	if namespace := s.serverNamespace(serverIndex); namespace != "" {
+		resp = namespacedSchemaResponse(resp, namespace)
+	}
+
+	if !schemaEquals(resp.Provider, s.serverProviderSchemas[serverIndex]) {
+		return fmt.Errorf("unable to replace server %d: %T declares a different provider schema. Diff: %s", serverIndex, replacement, schemaDiff(resp.Provider, s.serverProviderSchemas[serverIndex]))
+	}
+
+	if !schemaEquals(resp.ProviderMeta, s.serverProviderMetaSchemas[serverIndex]) {
+		return fmt.Errorf("unable to replace server %d: %T declares a different provider meta schema. Diff: %s", serverIndex, replacement, schemaDiff(resp.ProviderMeta, s.serverProviderMetaSchemas[serverIndex]))
+	}
+
+	// The muxed server only advertises PlanDestroy if all servers with
+	// resources support it.
+	if s.serverCapabilities != nil && s.serverCapabilities.PlanDestroy && len(resp.ResourceSchemas) > 0 && (resp.ServerCapabilities == nil || !resp.ServerCapabilities.PlanDestroy) {
+		return fmt.Errorf("unable to replace server %d: %T does not support the PlanDestroy server capability, which the muxed server advertises", serverIndex, replacement)
+	}
+
+	s.routingMu.Lock()
+	defer s.routingMu.Unlock()
+
+	if err := replaceServerSchemaCheck("resource", serverIndex, replacement, s.resourceSchemas, s.resourceServerIndex, resp.ResourceSchemas); err != nil {
+		return err
+	}
+
+	if err := replaceServerSchemaCheck("data source", serverIndex, replacement, s.dataSourceSchemas, s.dataSourceServerIndex, resp.DataSourceSchemas); err != nil {
+		return err
+	}
+
+	s.servers[serverIndex] = replacement
+	s.serverFuncs[serverIndex] = server
+
+	for resourceType, index := range s.resourceServerIndex {
+		if index == serverIndex {
+			s.resources[resourceType] = replacement
+		}
+	}
+
+	for dataSourceType, index := range s.dataSourceServerIndex {
+		if index == serverIndex {
+			s.dataSources[dataSourceType] = replacement
+		}
+	}
+
+	// Cached responses are from the replaced server.
+	s.dataSourceCache.clear()
+
+	s.updateTimeoutHints(ctx, serverIndex, replacement)
+
+	if err := s.validateInvariants(); err != nil {
+		return fmt.Errorf("unable to replace server %d: %w", serverIndex, err)
+	}
+
+	logging.MuxDebug(ctx, "replaced server", map[string]interface{}{
+		logging.KeyTfMuxServerIndex: serverIndex,
+	})
+
+	return nil
+}
+
+// replaceServerSchemaCheck returns an error if the replacement server schemas
+// do not declare every type routed to the server index with a schema
+// identical to the current schema, or declare types missing from the current
+// schemas.
+func replaceServerSchemaCheck(kind string, serverIndex int, replacement tfprotov6.ProviderServer, current map[string]*tfprotov6.Schema, serverIndexes map[string]int, serverSchemas map[string]*tfprotov6.Schema) error {
+	for _, typeName := range sortedSchemaTypeNames(current) {
+		if serverIndexes[typeName] != serverIndex {
+			continue
+		}
+
+		schema, ok := serverSchemas[typeName]
+
+		if !ok {
+			return fmt.Errorf("unable to replace server %d: %T does not declare the %s type %q", serverIndex, replacement, kind, typeName)
+		}
+
+		if !schemaEquals(schema, current[typeName]) {
+			return fmt.Errorf("unable to replace server %d: %T declares a different %s schema for %q. Diff: %s", serverIndex, replacement, kind, typeName, schemaDiff(schema, current[typeName]))
+		}
+	}
+
+	for _, typeName := range sortedSchemaTypeNames(serverSchemas) {
+		if _, ok := current[typeName]; !ok {
+			return fmt.Errorf("unable to replace server %d: %T declares the %s type %q, which isn't supported by the muxed server", serverIndex, replacement, kind, typeName)
+		}
+	}
+
+	return nil
+}
+
+// currentServers returns a copy of the servers, which may be changed via
+// ReplaceServer.
+func (s muxServer) currentServers() []tfprotov6.ProviderServer {
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	return append([]tfprotov6.ProviderServer(nil), s.servers...)
+}
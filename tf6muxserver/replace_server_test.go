@@ -0,0 +1,294 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestMuxServerReplaceServer(t *testing.T) {
+	t.Parallel()
+
+	testProviderSchema := &tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name:     "region",
+					Type:     tftypes.String,
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	testResourceSchema := &tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name:     "id",
+					Type:     tftypes.String,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		serverCapabilities *tfprotov6.ServerCapabilities
+		serverIndex        int
+		replacement        *tf6testserver.TestServer
+		expectedError      bool
+	}{
+		"different-provider-meta-schema": {
+			serverIndex: 1,
+			replacement: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source": {},
+				},
+				ProviderMetaSchema: testProviderSchema,
+				ProviderSchema:     testProviderSchema,
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": testResourceSchema,
+				},
+			},
+			expectedError: true,
+		},
+		"different-provider-schema": {
+			serverIndex: 1,
+			replacement: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source": {},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": testResourceSchema,
+				},
+			},
+			expectedError: true,
+		},
+		"different-resource-schema": {
+			serverIndex: 1,
+			replacement: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source": {},
+				},
+				ProviderSchema: testProviderSchema,
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": {},
+				},
+			},
+			expectedError: true,
+		},
+		"identical": {
+			serverIndex: 1,
+			replacement: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source": {},
+				},
+				ProviderSchema: testProviderSchema,
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": testResourceSchema,
+				},
+			},
+		},
+		"identical-plan-destroy": {
+			serverCapabilities: &tfprotov6.ServerCapabilities{
+				PlanDestroy: true,
+			},
+			serverIndex: 1,
+			replacement: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source": {},
+				},
+				ProviderSchema: testProviderSchema,
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": testResourceSchema,
+				},
+				ServerCapabilities: &tfprotov6.ServerCapabilities{
+					PlanDestroy: true,
+				},
+			},
+		},
+		"missing-data-source": {
+			serverIndex: 1,
+			replacement: &tf6testserver.TestServer{
+				ProviderSchema: testProviderSchema,
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": testResourceSchema,
+				},
+			},
+			expectedError: true,
+		},
+		"plan-destroy-unsupported": {
+			serverCapabilities: &tfprotov6.ServerCapabilities{
+				PlanDestroy: true,
+			},
+			serverIndex: 1,
+			replacement: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source": {},
+				},
+				ProviderSchema: testProviderSchema,
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": testResourceSchema,
+				},
+			},
+			expectedError: true,
+		},
+		"server-index-out-of-range": {
+			serverIndex:   2,
+			replacement:   &tf6testserver.TestServer{},
+			expectedError: true,
+		},
+		"unsupported-resource": {
+			serverIndex: 1,
+			replacement: &tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source": {},
+				},
+				ProviderSchema: testProviderSchema,
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource":       testResourceSchema,
+					"test_resource_extra": {},
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf6testserver.TestServer{
+				{
+					ProviderSchema: testProviderSchema,
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: testCase.serverCapabilities,
+				},
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+					ProviderSchema: testProviderSchema,
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": testResourceSchema,
+					},
+					ServerCapabilities: testCase.serverCapabilities,
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServer(context.Background(), servers[0].ProviderServer, servers[1].ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			err = muxServer.ReplaceServer(testCase.serverIndex, testCase.replacement.ProviderServer)
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			_, err = muxServer.ProviderServer().ReadDataSource(context.Background(), &tfprotov6.ReadDataSourceRequest{
+				TypeName: "test_data_source",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !testCase.replacement.ReadDataSourceCalled["test_data_source"] {
+				t.Errorf("expected replacement server ReadDataSource to be called")
+			}
+
+			_, err = muxServer.ProviderServer().ConfigureProvider(context.Background(), &tfprotov6.ConfigureProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !testCase.replacement.ConfigureProviderCalled {
+				t.Errorf("expected replacement server ConfigureProvider to be called")
+			}
+
+			if servers[1].ReadDataSourceCalled["test_data_source"] || servers[1].ConfigureProviderCalled {
+				t.Errorf("unexpected replaced server call")
+			}
+		})
+	}
+}
+
+func TestMuxServerReplaceServerConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var calls int64
+
+	server := func() tfprotov6.ProviderServer {
+		return countingReadResourceServer{
+			ProviderServer: &tf6testserver.TestServer{
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": {},
+				},
+			},
+			calls: &calls,
+		}
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(context.Background(), server)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	providerServer := muxServer.ProviderServer()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := providerServer.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			if err := muxServer.ReplaceServer(0, server); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&calls); got != 10 {
+		t.Errorf("expected 10 ReadResource calls, got %d", got)
+	}
+}
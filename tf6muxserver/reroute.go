@@ -0,0 +1,148 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// Reroute changes the server implementing the resource type, data source
+// type, or both, with the given type name to the server at the given index,
+// such as for a blue/green rollout of a rewritten resource implementation.
+// The server index is the position of the server in the order given to
+// NewMuxServer, excluding servers excluded via the WithBestEffortSchema
+// option. The GetProviderSchema method of the server is called to verify that
+// it declares the type name, including any prefix configured for the server
+// via the WithNamespace option, with a schema identical to the current
+// schema, as the muxed server GetProviderSchema response is not changed.
+//
+// After the change, the routing and schemas of the muxed server are checked
+// for consistency, as during muxed server creation. An error from this check
+// indicates a bug in the muxed server rather than in the servers.
+//
+// Reroute is safe to call concurrently with other methods. Requests which
+// were already routed complete against the previous server, while later
+// requests are routed to the new server. If the WithServerReuse option is
+// disabled, only the servers created by later ProviderServer calls are
+// affected.
+func (s muxServer) Reroute(typeName string, serverIndex int) error {
+	servers := s.currentServers()
+
+	if serverIndex < 0 || serverIndex >= len(servers) {
+		return fmt.Errorf("unable to reroute %q: server index %d is out of range, expected 0 to %d", typeName, serverIndex, len(servers)-1)
+	}
+
+	server := servers[serverIndex]
+
+	s.routingMu.RLock()
+	_, isResource := s.resources[typeName]
+	_, isDataSource := s.dataSources[typeName]
+	s.routingMu.RUnlock()
+
+	if !isResource && !isDataSource {
+		return fmt.Errorf("unable to reroute %q: type isn't supported by any servers", typeName)
+	}
+
+	ctx := logging.InitContext(context.Background())
+	ctx = logging.Tfprotov6ProviderServerContext(ctx, server)
+
+	resp, err := getServerSchema(ctx, server)
+
+	if err != nil {
+		return fmt.Errorf("unable to reroute %q: %w", typeName, err)
+	}
+
+	if namespace := s.serverNamespace(serverIndex); namespace != "" {
+		resp = namespacedSchemaResponse(resp, namespace)
+	}
+
+	if isResource {
+		if err := rerouteSchemaCheck("resource", typeName, server, s.resourceSchemas[typeName], resp.ResourceSchemas); err != nil {
+			return err
+		}
+	}
+
+	if isDataSource {
+		if err := rerouteSchemaCheck("data source", typeName, server, s.dataSourceSchemas[typeName], resp.DataSourceSchemas); err != nil {
+			return err
+		}
+	}
+
+	s.routingMu.Lock()
+	defer s.routingMu.Unlock()
+
+	if isResource {
+		s.resources[typeName] = server
+		s.resourceServerIndex[typeName] = serverIndex
+	}
+
+	if isDataSource {
+		s.dataSources[typeName] = server
+		s.dataSourceServerIndex[typeName] = serverIndex
+
+		// Cached responses are from the previous server.
+		s.dataSourceCache.clear()
+	}
+
+	s.updateTimeoutHints(ctx, serverIndex, server)
+
+	if err := s.validateInvariants(); err != nil {
+		return fmt.Errorf("unable to reroute %q: %w", typeName, err)
+	}
+
+	logging.MuxDebug(ctx, "rerouted type", map[string]interface{}{
+		logging.KeyTfMuxServerIndex: serverIndex,
+	})
+
+	return nil
+}
+
+// rerouteSchemaCheck returns an error if the server schemas do not declare
+// the type name with a schema identical to the current schema.
+func rerouteSchemaCheck(kind string, typeName string, server tfprotov6.ProviderServer, current *tfprotov6.Schema, serverSchemas map[string]*tfprotov6.Schema) error {
+	schema, ok := serverSchemas[typeName]
+
+	if !ok {
+		return fmt.Errorf("unable to reroute %q: %T does not declare the %s type", typeName, server, kind)
+	}
+
+	if !schemaEquals(schema, current) {
+		return fmt.Errorf("unable to reroute %q: %T declares a different %s schema. Diff: %s", typeName, server, kind, schemaDiff(schema, current))
+	}
+
+	return nil
+}
+
+// dataSourceServer returns the server implementing the data source type, or
+// the server selected by the Router configured for the type via the
+// WithCustomRouter option.
+func (s muxServer) dataSourceServer(ctx context.Context, typeName string, req interface{}) (tfprotov6.ProviderServer, bool, error) {
+	if router, ok := s.options.customRouters[typeName]; ok {
+		return s.customRoute(ctx, typeName, router, req)
+	}
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	server, ok := s.dataSources[typeName]
+
+	return server, ok, nil
+}
+
+// resourceServer returns the server implementing the resource type, or the
+// server selected by the Router configured for the type via the
+// WithCustomRouter option.
+func (s muxServer) resourceServer(ctx context.Context, typeName string, req interface{}) (tfprotov6.ProviderServer, bool, error) {
+	if router, ok := s.options.customRouters[typeName]; ok {
+		return s.customRoute(ctx, typeName, router, req)
+	}
+
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	server, ok := s.resources[typeName]
+
+	return server, ok, nil
+}
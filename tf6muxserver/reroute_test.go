@@ -0,0 +1,321 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// countingReadResourceServer is a server whose ReadResource method counts
+// its calls, which is safe for concurrent use.
+type countingReadResourceServer struct {
+	tfprotov6.ProviderServer
+
+	calls *int64
+}
+
+func (s countingReadResourceServer) ReadResource(_ context.Context, _ *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	atomic.AddInt64(s.calls, 1)
+
+	return &tfprotov6.ReadResourceResponse{}, nil
+}
+
+func TestMuxServerReroute(t *testing.T) {
+	t.Parallel()
+
+	testResourceSchema := &tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name:     "id",
+					Type:     tftypes.String,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		typeName                 string
+		serverIndex              int
+		expectedError            bool
+		expectedDataSourceServer int
+		expectedResourceServer   int
+	}{
+		"data-source": {
+			typeName:                 "test_data_source",
+			serverIndex:              1,
+			expectedDataSourceServer: 1,
+		},
+		"resource": {
+			typeName:               "test_resource",
+			serverIndex:            1,
+			expectedResourceServer: 1,
+		},
+		"resource-different-schema": {
+			typeName:      "test_resource_different",
+			serverIndex:   1,
+			expectedError: true,
+		},
+		"resource-not-declared": {
+			typeName:      "test_resource_server1",
+			serverIndex:   1,
+			expectedError: true,
+		},
+		"server-index-out-of-range": {
+			typeName:      "test_resource",
+			serverIndex:   2,
+			expectedError: true,
+		},
+		"type-not-found": {
+			typeName:      "test_resource_nonexistent",
+			serverIndex:   1,
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf6testserver.TestServer{
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource":           testResourceSchema,
+						"test_resource_different": testResourceSchema,
+						"test_resource_server1":   {},
+					},
+				},
+				{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource":           testResourceSchema,
+						"test_resource_different": {},
+					},
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf6muxserver.ServerOption{
+					tf6muxserver.WithConflictResolution(tf6muxserver.ConflictResolutionFirstServer),
+				},
+				servers[0].ProviderServer,
+				servers[1].ProviderServer,
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			err = muxServer.Reroute(testCase.typeName, testCase.serverIndex)
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			_, err = muxServer.ProviderServer().ReadDataSource(context.Background(), &tfprotov6.ReadDataSourceRequest{
+				TypeName: "test_data_source",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !servers[testCase.expectedDataSourceServer].ReadDataSourceCalled["test_data_source"] {
+				t.Errorf("expected server %d ReadDataSource to be called", testCase.expectedDataSourceServer)
+			}
+
+			_, err = muxServer.ProviderServer().ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !servers[testCase.expectedResourceServer].ReadResourceCalled["test_resource"] {
+				t.Errorf("expected server %d ReadResource to be called", testCase.expectedResourceServer)
+			}
+		})
+	}
+}
+
+func TestMuxServerRerouteNamespace(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		server2Namespace string
+		expectedError    bool
+	}{
+		"same-namespace": {
+			server2Namespace: "test_",
+		},
+		"different-namespace": {
+			server2Namespace: "other_",
+			expectedError:    true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := []*tf6testserver.TestServer{
+				{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"resource": {},
+					},
+				},
+				{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"resource": {},
+					},
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf6muxserver.ServerOption{
+					tf6muxserver.WithConflictResolution(tf6muxserver.ConflictResolutionFirstServer),
+					tf6muxserver.WithNamespace(0, "test_"),
+					tf6muxserver.WithNamespace(1, testCase.server2Namespace),
+				},
+				servers[0].ProviderServer,
+				servers[1].ProviderServer,
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			err = muxServer.Reroute("test_resource", 1)
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			_, err = muxServer.ProviderServer().ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if servers[0].ReadResourceCalled["resource"] {
+				t.Errorf("unexpected server 0 ReadResource call")
+			}
+
+			if !servers[1].ReadResourceCalled["resource"] {
+				t.Errorf("expected server 1 ReadResource to be called")
+			}
+		})
+	}
+}
+
+func TestMuxServerRerouteConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var server1Calls, server2Calls int64
+
+	servers := []func() tfprotov6.ProviderServer{
+		func() tfprotov6.ProviderServer {
+			return countingReadResourceServer{
+				ProviderServer: &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				},
+				calls: &server1Calls,
+			}
+		},
+		func() tfprotov6.ProviderServer {
+			return countingReadResourceServer{
+				ProviderServer: &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				},
+				calls: &server2Calls,
+			}
+		},
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+		context.Background(),
+		[]tf6muxserver.ServerOption{
+			tf6muxserver.WithConflictResolution(tf6muxserver.ConflictResolutionFirstServer),
+		},
+		servers...,
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	providerServer := muxServer.ProviderServer()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := providerServer.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+				TypeName: "test_resource",
+			})
+
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+
+		go func(serverIndex int) {
+			defer wg.Done()
+
+			if err := muxServer.Reroute("test_resource", serverIndex); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}(i % 2)
+	}
+
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&server1Calls) + atomic.LoadInt64(&server2Calls); got != 10 {
+		t.Errorf("expected 10 ReadResource calls, got %d", got)
+	}
+}
@@ -0,0 +1,80 @@
+package tf6muxserver
+
+import (
+	"sync"
+)
+
+// RoutingStats is a snapshot of the type name routing results of a muxed
+// server. Counts are keyed by RPC name, such as "ReadResource".
+type RoutingStats struct {
+	// Hits is the number of requests routed to the server which declared
+	// the requested type name in its schema.
+	Hits map[string]int64
+
+	// Misses is the number of requests for a type name which was not
+	// declared by any server.
+	Misses map[string]int64
+}
+
+// routingStats records type name routing results. It is safe for
+// concurrent use.
+type routingStats struct {
+	mu     sync.Mutex
+	hits   map[string]int64
+	misses map[string]int64
+}
+
+func newRoutingStats() *routingStats {
+	return &routingStats{
+		hits:   make(map[string]int64),
+		misses: make(map[string]int64),
+	}
+}
+
+// record increments the hit or miss count for the RPC.
+func (r *routingStats) record(rpc string, hit bool) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if hit {
+		r.hits[rpc]++
+		return
+	}
+
+	r.misses[rpc]++
+}
+
+// snapshot returns a copy of the current counts.
+func (r *routingStats) snapshot() RoutingStats {
+	result := RoutingStats{
+		Hits:   make(map[string]int64),
+		Misses: make(map[string]int64),
+	}
+
+	if r == nil {
+		return result
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for rpc, count := range r.hits {
+		result.Hits[rpc] = count
+	}
+
+	for rpc, count := range r.misses {
+		result.Misses[rpc] = count
+	}
+
+	return result
+}
+
+// Stats returns a snapshot of the type name routing results since the muxed
+// server was created. It is safe to call concurrently with other methods.
+func (s muxServer) Stats() RoutingStats {
+	return s.routingStats.snapshot()
+}
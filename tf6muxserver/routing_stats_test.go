@@ -0,0 +1,112 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestMuxServerStats(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	servers := []func() tfprotov6.ProviderServer{
+		(&tf6testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov6.Schema{
+				"test_data_source": {},
+			},
+		}).ProviderServer,
+		(&tf6testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource": {},
+			},
+		}).ProviderServer,
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, servers...)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: "test_data_source",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+		TypeName: "test_resource",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+		TypeName: "test_missing",
+	})
+
+	if err == nil {
+		t.Fatalf("expected error for unsupported type")
+	}
+
+	expected := tf6muxserver.RoutingStats{
+		Hits: map[string]int64{
+			"ReadDataSource": 1,
+			"ReadResource":   1,
+		},
+		Misses: map[string]int64{
+			"ReadResource": 1,
+		},
+	}
+
+	if diff := cmp.Diff(muxServer.Stats(), expected); diff != "" {
+		t.Errorf("unexpected stats: %s", diff)
+	}
+}
+
+func TestMuxServerStatsConcurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, (&tf6testserver.TestServer{}).ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+				TypeName: "test_missing",
+			})
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_ = muxServer.Stats()
+		}()
+	}
+
+	wg.Wait()
+
+	if got := muxServer.Stats().Misses["ReadResource"]; got != 50 {
+		t.Errorf("expected 50 misses, got: %d", got)
+	}
+}
@@ -0,0 +1,715 @@
+package tf6muxserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// schemaCache is the content of the schema cache file written by
+// WithSchemaCacheFile. It contains the schemas cached during muxed server
+// creation and the index of the server implementing each type, so routing can
+// be restored without calling GetProviderSchema.
+type schemaCache struct {
+	// Hash identifies the servers which declared the cached schemas.
+	Hash string `json:"hash"`
+
+	DataSourceSchemas         map[string]*schemaCacheSchema `json:"data_source_schemas"`
+	DataSourceServerIndex     map[string]int                `json:"data_source_server_index"`
+	ProviderMetaSchema        *schemaCacheSchema            `json:"provider_meta_schema,omitempty"`
+	ProviderSchema            *schemaCacheSchema            `json:"provider_schema,omitempty"`
+	ResourceSchemas           map[string]*schemaCacheSchema `json:"resource_schemas"`
+	ResourceServerIndex       map[string]int                `json:"resource_server_index"`
+	ServerCapabilities        *tfprotov6.ServerCapabilities `json:"server_capabilities,omitempty"`
+	ServerProviderSchemas     []*schemaCacheSchema          `json:"server_provider_schemas"`
+	ServerProviderMetaSchemas []*schemaCacheSchema          `json:"server_provider_meta_schemas"`
+}
+
+// schemaCacheSchema is the JSON representation of a tfprotov6.Schema.
+type schemaCacheSchema struct {
+	Version int64             `json:"version"`
+	Block   *schemaCacheBlock `json:"block,omitempty"`
+}
+
+// schemaCacheBlock is the JSON representation of a tfprotov6.SchemaBlock.
+type schemaCacheBlock struct {
+	Version         int64                     `json:"version"`
+	Attributes      []*schemaCacheAttribute   `json:"attributes,omitempty"`
+	BlockTypes      []*schemaCacheNestedBlock `json:"block_types,omitempty"`
+	Description     string                    `json:"description,omitempty"`
+	DescriptionKind tfprotov6.StringKind      `json:"description_kind,omitempty"`
+	Deprecated      bool                      `json:"deprecated,omitempty"`
+}
+
+// schemaCacheAttribute is the JSON representation of a
+// tfprotov6.SchemaAttribute. The attribute type uses the tftypes JSON type
+// encoding.
+type schemaCacheAttribute struct {
+	Name            string               `json:"name"`
+	Type            json.RawMessage      `json:"type,omitempty"`
+	NestedType      *schemaCacheObject   `json:"nested_type,omitempty"`
+	Description     string               `json:"description,omitempty"`
+	Required        bool                 `json:"required,omitempty"`
+	Optional        bool                 `json:"optional,omitempty"`
+	Computed        bool                 `json:"computed,omitempty"`
+	Sensitive       bool                 `json:"sensitive,omitempty"`
+	DescriptionKind tfprotov6.StringKind `json:"description_kind,omitempty"`
+	Deprecated      bool                 `json:"deprecated,omitempty"`
+}
+
+// schemaCacheObject is the JSON representation of a tfprotov6.SchemaObject.
+type schemaCacheObject struct {
+	Attributes []*schemaCacheAttribute           `json:"attributes,omitempty"`
+	Nesting    tfprotov6.SchemaObjectNestingMode `json:"nesting"`
+}
+
+// schemaCacheNestedBlock is the JSON representation of a
+// tfprotov6.SchemaNestedBlock.
+type schemaCacheNestedBlock struct {
+	TypeName string                                 `json:"type_name"`
+	Block    *schemaCacheBlock                      `json:"block,omitempty"`
+	Nesting  tfprotov6.SchemaNestedBlockNestingMode `json:"nesting"`
+	MinItems int64                                  `json:"min_items,omitempty"`
+	MaxItems int64                                  `json:"max_items,omitempty"`
+}
+
+// SchemaFingerprinter is an optional interface which servers can implement to
+// cheaply identify their schemas without a GetProviderSchema call, such as
+// with a hash of the schemas computed when the server is built. It is used to
+// identify schema cache files when enabled via the
+// WithSchemaCacheContentHash option. The fingerprint must change whenever any
+// schema of the server changes.
+type SchemaFingerprinter interface {
+	// SchemaFingerprint returns the fingerprint of the server schemas.
+	SchemaFingerprint() string
+}
+
+// schemaCacheHash returns the hash identifying the version and the options
+// affecting the schemas and routing of the muxed server, the number, order,
+// and Go types of the given servers, and the content hashes of their
+// schemas, if any.
+func schemaCacheHash(options serverOptions, servers []tfprotov6.ProviderServer, contentHashes []string) string {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "%q\n", options.schemaCacheVersion)
+
+	for _, option := range schemaCacheOptions(options) {
+		fmt.Fprintf(hash, "%s\n", option)
+	}
+
+	for serverIndex, server := range servers {
+		fmt.Fprintf(hash, "%d:%T\n", serverIndex, server)
+	}
+
+	for serverIndex, contentHash := range contentHashes {
+		fmt.Fprintf(hash, "%d:%s\n", serverIndex, contentHash)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// schemaCacheOptions returns a canonical encoding of the options which affect
+// the schemas and routing of the muxed server, or the checks of the
+// GetProviderSchema responses of the servers, so that changing any of them
+// invalidates the schema cache file. Options must be added here when they
+// affect schema assembly.
+func schemaCacheOptions(options serverOptions) []string {
+	result := []string{
+		fmt.Sprintf("best_effort_schema=%t", options.bestEffortSchema),
+		fmt.Sprintf("conflict_resolution=%d", options.conflictResolution),
+		fmt.Sprintf("empty_server_error=%t", options.emptyServerError),
+		fmt.Sprintf("feature_server_overlap_warnings=%t", options.featureServerOverlapWarnings),
+		fmt.Sprintf("provider_meta_schema_merge=%t", options.providerMetaSchemaMerge),
+		fmt.Sprintf("provider_schema_merge=%t", options.providerSchemaMerge),
+		fmt.Sprintf("require_shared_provider_schema=%t", options.requireSharedProviderSchema),
+		fmt.Sprintf("strict_response_fields=%t", options.strictResponseFields),
+		fmt.Sprintf("warnings_as_errors=%t", options.warningsAsErrors),
+	}
+
+	serverIndexes := make([]int, 0, len(options.namespaces))
+
+	for serverIndex, namespace := range options.namespaces {
+		if namespace != "" {
+			serverIndexes = append(serverIndexes, serverIndex)
+		}
+	}
+
+	sort.Ints(serverIndexes)
+
+	for _, serverIndex := range serverIndexes {
+		result = append(result, fmt.Sprintf("namespace.%d=%q", serverIndex, options.namespaces[serverIndex]))
+	}
+
+	return result
+}
+
+// schemaContentHashes returns the content hash of the schemas of each server,
+// as enabled via the WithSchemaCacheContentHash option, and the
+// GetProviderSchema responses retrieved to compute them, keyed by server
+// index. The content hash of servers implementing SchemaFingerprinter is
+// their fingerprint, which requires no GetProviderSchema call. If an error is
+// returned, the responses retrieved before the error are still returned.
+func schemaContentHashes(ctx context.Context, servers []tfprotov6.ProviderServer) ([]string, map[int]*tfprotov6.GetProviderSchemaResponse, error) {
+	result := make([]string, 0, len(servers))
+	responses := make(map[int]*tfprotov6.GetProviderSchemaResponse)
+
+	for serverIndex, server := range servers {
+		if fingerprinter, ok := server.(SchemaFingerprinter); ok {
+			result = append(result, "fingerprint:"+fingerprinter.SchemaFingerprint())
+
+			continue
+		}
+
+		resp, err := getServerSchema(logging.Tfprotov6ProviderServerContext(ctx, server), server)
+
+		if err != nil {
+			return nil, responses, fmt.Errorf("unable to hash schemas of server %d (%T): %w", serverIndex, server, err)
+		}
+
+		responses[serverIndex] = resp
+
+		contentHash, err := schemaResponseHash(resp)
+
+		if err != nil {
+			return nil, responses, fmt.Errorf("unable to hash schemas of server %d (%T): %w", serverIndex, server, err)
+		}
+
+		result = append(result, "schema:"+contentHash)
+	}
+
+	return result, responses, nil
+}
+
+// schemaResponseHash returns the hash of the schemas and server capabilities
+// of the GetProviderSchema response, using the schema cache file encoding.
+func schemaResponseHash(resp *tfprotov6.GetProviderSchemaResponse) (string, error) {
+	var content struct {
+		DataSourceSchemas  map[string]*schemaCacheSchema `json:"data_source_schemas"`
+		ProviderMetaSchema *schemaCacheSchema            `json:"provider_meta_schema,omitempty"`
+		ProviderSchema     *schemaCacheSchema            `json:"provider_schema,omitempty"`
+		ResourceSchemas    map[string]*schemaCacheSchema `json:"resource_schemas"`
+		ServerCapabilities *tfprotov6.ServerCapabilities `json:"server_capabilities,omitempty"`
+	}
+
+	var err error
+
+	if content.DataSourceSchemas, err = newSchemaCacheSchemas(resp.DataSourceSchemas); err != nil {
+		return "", err
+	}
+
+	if content.ProviderMetaSchema, err = newSchemaCacheSchema(resp.ProviderMeta); err != nil {
+		return "", err
+	}
+
+	if content.ProviderSchema, err = newSchemaCacheSchema(resp.Provider); err != nil {
+		return "", err
+	}
+
+	if content.ResourceSchemas, err = newSchemaCacheSchemas(resp.ResourceSchemas); err != nil {
+		return "", err
+	}
+
+	content.ServerCapabilities = resp.ServerCapabilities
+
+	// Map keys are sorted when encoding, so the encoding is deterministic.
+	data, err := json.Marshal(content)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// readSchemaCacheFile returns the schema cache from the file at the given
+// path. A nil schema cache is returned if the file does not exist or was
+// written for a different hash.
+func readSchemaCacheFile(path string, hash string) (*schemaCache, error) {
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema cache file: %w", err)
+	}
+
+	var cache schemaCache
+
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("unable to decode schema cache file: %w", err)
+	}
+
+	if cache.Hash != hash {
+		return nil, nil
+	}
+
+	return &cache, nil
+}
+
+// writeSchemaCacheFile writes the schema cache to the file at the given path.
+// The file is replaced atomically, so concurrent readers never observe a
+// partially written file.
+func writeSchemaCacheFile(path string, cache *schemaCache) error {
+	data, err := json.Marshal(cache)
+
+	if err != nil {
+		return fmt.Errorf("unable to encode schema cache file: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+
+	if err != nil {
+		return fmt.Errorf("unable to create schema cache file: %w", err)
+	}
+
+	defer os.Remove(file.Name()) //nolint:errcheck
+
+	if _, err := file.Write(data); err != nil {
+		file.Close() //nolint:errcheck
+
+		return fmt.Errorf("unable to write schema cache file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to write schema cache file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("unable to write schema cache file: %w", err)
+	}
+
+	return nil
+}
+
+// newSchemaCache returns the schema cache of the muxed server.
+func newSchemaCache(s muxServer, hash string) (*schemaCache, error) {
+	var err error
+
+	cache := &schemaCache{
+		Hash:                  hash,
+		DataSourceServerIndex: s.dataSourceServerIndex,
+		ResourceServerIndex:   s.resourceServerIndex,
+		ServerCapabilities:    s.serverCapabilities,
+	}
+
+	cache.DataSourceSchemas, err = newSchemaCacheSchemas(s.dataSourceSchemas)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cache.ProviderMetaSchema, err = newSchemaCacheSchema(s.providerMetaSchema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cache.ProviderSchema, err = newSchemaCacheSchema(s.providerSchema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cache.ResourceSchemas, err = newSchemaCacheSchemas(s.resourceSchemas)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, serverProviderSchema := range s.serverProviderSchemas {
+		schema, err := newSchemaCacheSchema(serverProviderSchema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		cache.ServerProviderSchemas = append(cache.ServerProviderSchemas, schema)
+	}
+
+	for _, serverProviderMetaSchema := range s.serverProviderMetaSchemas {
+		schema, err := newSchemaCacheSchema(serverProviderMetaSchema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		cache.ServerProviderMetaSchemas = append(cache.ServerProviderMetaSchemas, schema)
+	}
+
+	return cache, nil
+}
+
+// restore populates the schemas and routing of the muxed server from the
+// schema cache, using the given servers. The muxed server is not modified if
+// an error is returned.
+func (c *schemaCache) restore(s *muxServer, servers []tfprotov6.ProviderServer) error {
+	if len(c.ServerProviderSchemas) != len(servers) {
+		return fmt.Errorf("schema cache contains %d servers, expected %d", len(c.ServerProviderSchemas), len(servers))
+	}
+
+	if len(c.ServerProviderMetaSchemas) != len(servers) {
+		return fmt.Errorf("schema cache contains %d server provider meta schemas, expected %d", len(c.ServerProviderMetaSchemas), len(servers))
+	}
+
+	for dataSourceType, serverIndex := range c.DataSourceServerIndex {
+		if serverIndex < 0 || serverIndex >= len(servers) {
+			return fmt.Errorf("schema cache data source %q server index %d is out of range", dataSourceType, serverIndex)
+		}
+	}
+
+	for resourceType, serverIndex := range c.ResourceServerIndex {
+		if serverIndex < 0 || serverIndex >= len(servers) {
+			return fmt.Errorf("schema cache resource %q server index %d is out of range", resourceType, serverIndex)
+		}
+	}
+
+	dataSourceSchemas, err := schemaCacheSchemas(c.DataSourceSchemas)
+
+	if err != nil {
+		return err
+	}
+
+	resourceSchemas, err := schemaCacheSchemas(c.ResourceSchemas)
+
+	if err != nil {
+		return err
+	}
+
+	providerMetaSchema, err := c.ProviderMetaSchema.schema()
+
+	if err != nil {
+		return err
+	}
+
+	providerSchema, err := c.ProviderSchema.schema()
+
+	if err != nil {
+		return err
+	}
+
+	serverProviderSchemas := make([]*tfprotov6.Schema, 0, len(c.ServerProviderSchemas))
+
+	for _, serverProviderSchema := range c.ServerProviderSchemas {
+		schema, err := serverProviderSchema.schema()
+
+		if err != nil {
+			return err
+		}
+
+		serverProviderSchemas = append(serverProviderSchemas, schema)
+	}
+
+	serverProviderMetaSchemas := make([]*tfprotov6.Schema, 0, len(c.ServerProviderMetaSchemas))
+
+	for _, serverProviderMetaSchema := range c.ServerProviderMetaSchemas {
+		schema, err := serverProviderMetaSchema.schema()
+
+		if err != nil {
+			return err
+		}
+
+		serverProviderMetaSchemas = append(serverProviderMetaSchemas, schema)
+	}
+
+	for dataSourceType, serverIndex := range c.DataSourceServerIndex {
+		s.dataSources[dataSourceType] = servers[serverIndex]
+		s.dataSourceServerIndex[dataSourceType] = serverIndex
+	}
+
+	for resourceType, serverIndex := range c.ResourceServerIndex {
+		s.resources[resourceType] = servers[serverIndex]
+		s.resourceServerIndex[resourceType] = serverIndex
+	}
+
+	s.dataSourceSchemas = dataSourceSchemas
+	s.providerMetaSchema = providerMetaSchema
+	s.providerSchema = providerSchema
+	s.resourceSchemas = resourceSchemas
+	s.serverCapabilities = c.ServerCapabilities
+	s.serverProviderMetaSchemas = serverProviderMetaSchemas
+	s.serverProviderSchemas = serverProviderSchemas
+	s.servers = servers
+
+	return nil
+}
+
+func schemaCacheSchemas(in map[string]*schemaCacheSchema) (map[string]*tfprotov6.Schema, error) {
+	out := make(map[string]*tfprotov6.Schema, len(in))
+
+	for typeName, cacheSchema := range in {
+		schema, err := cacheSchema.schema()
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode schema cache %q schema: %w", typeName, err)
+		}
+
+		out[typeName] = schema
+	}
+
+	return out, nil
+}
+
+func newSchemaCacheSchemas(in map[string]*tfprotov6.Schema) (map[string]*schemaCacheSchema, error) {
+	out := make(map[string]*schemaCacheSchema, len(in))
+
+	for typeName, schema := range in {
+		cacheSchema, err := newSchemaCacheSchema(schema)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode %q schema: %w", typeName, err)
+		}
+
+		out[typeName] = cacheSchema
+	}
+
+	return out, nil
+}
+
+func newSchemaCacheSchema(in *tfprotov6.Schema) (*schemaCacheSchema, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	block, err := newSchemaCacheBlock(in.Block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaCacheSchema{
+		Version: in.Version,
+		Block:   block,
+	}, nil
+}
+
+func (c *schemaCacheSchema) schema() (*tfprotov6.Schema, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	block, err := c.Block.block()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.Schema{
+		Version: c.Version,
+		Block:   block,
+	}, nil
+}
+
+func newSchemaCacheBlock(in *tfprotov6.SchemaBlock) (*schemaCacheBlock, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &schemaCacheBlock{
+		Version:         in.Version,
+		Description:     in.Description,
+		DescriptionKind: in.DescriptionKind,
+		Deprecated:      in.Deprecated,
+	}
+
+	for _, attribute := range in.Attributes {
+		cacheAttribute, err := newSchemaCacheAttribute(attribute)
+
+		if err != nil {
+			return nil, err
+		}
+
+		out.Attributes = append(out.Attributes, cacheAttribute)
+	}
+
+	for _, nestedBlock := range in.BlockTypes {
+		cacheNestedBlock, err := newSchemaCacheNestedBlock(nestedBlock)
+
+		if err != nil {
+			return nil, err
+		}
+
+		out.BlockTypes = append(out.BlockTypes, cacheNestedBlock)
+	}
+
+	return out, nil
+}
+
+func (c *schemaCacheBlock) block() (*tfprotov6.SchemaBlock, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	out := &tfprotov6.SchemaBlock{
+		Version:         c.Version,
+		Description:     c.Description,
+		DescriptionKind: c.DescriptionKind,
+		Deprecated:      c.Deprecated,
+	}
+
+	for _, cacheAttribute := range c.Attributes {
+		attribute, err := cacheAttribute.attribute()
+
+		if err != nil {
+			return nil, err
+		}
+
+		out.Attributes = append(out.Attributes, attribute)
+	}
+
+	for _, cacheNestedBlock := range c.BlockTypes {
+		nestedBlock, err := cacheNestedBlock.nestedBlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		out.BlockTypes = append(out.BlockTypes, nestedBlock)
+	}
+
+	return out, nil
+}
+
+func newSchemaCacheAttribute(in *tfprotov6.SchemaAttribute) (*schemaCacheAttribute, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &schemaCacheAttribute{
+		Name:            in.Name,
+		Description:     in.Description,
+		Required:        in.Required,
+		Optional:        in.Optional,
+		Computed:        in.Computed,
+		Sensitive:       in.Sensitive,
+		DescriptionKind: in.DescriptionKind,
+		Deprecated:      in.Deprecated,
+	}
+
+	if in.Type != nil {
+		typ, err := in.Type.MarshalJSON()
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode attribute %q type: %w", in.Name, err)
+		}
+
+		out.Type = typ
+	}
+
+	if in.NestedType != nil {
+		out.NestedType = &schemaCacheObject{
+			Nesting: in.NestedType.Nesting,
+		}
+
+		for _, attribute := range in.NestedType.Attributes {
+			cacheAttribute, err := newSchemaCacheAttribute(attribute)
+
+			if err != nil {
+				return nil, err
+			}
+
+			out.NestedType.Attributes = append(out.NestedType.Attributes, cacheAttribute)
+		}
+	}
+
+	return out, nil
+}
+
+func (c *schemaCacheAttribute) attribute() (*tfprotov6.SchemaAttribute, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	out := &tfprotov6.SchemaAttribute{
+		Name:            c.Name,
+		Description:     c.Description,
+		Required:        c.Required,
+		Optional:        c.Optional,
+		Computed:        c.Computed,
+		Sensitive:       c.Sensitive,
+		DescriptionKind: c.DescriptionKind,
+		Deprecated:      c.Deprecated,
+	}
+
+	if len(c.Type) > 0 {
+		typ, err := tftypes.ParseJSONType(c.Type) //nolint:staticcheck
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode attribute %q type: %w", c.Name, err)
+		}
+
+		out.Type = typ
+	}
+
+	if c.NestedType != nil {
+		out.NestedType = &tfprotov6.SchemaObject{
+			Nesting: c.NestedType.Nesting,
+		}
+
+		for _, cacheAttribute := range c.NestedType.Attributes {
+			attribute, err := cacheAttribute.attribute()
+
+			if err != nil {
+				return nil, err
+			}
+
+			out.NestedType.Attributes = append(out.NestedType.Attributes, attribute)
+		}
+	}
+
+	return out, nil
+}
+
+func newSchemaCacheNestedBlock(in *tfprotov6.SchemaNestedBlock) (*schemaCacheNestedBlock, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	block, err := newSchemaCacheBlock(in.Block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaCacheNestedBlock{
+		TypeName: in.TypeName,
+		Block:    block,
+		Nesting:  in.Nesting,
+		MinItems: in.MinItems,
+		MaxItems: in.MaxItems,
+	}, nil
+}
+
+func (c *schemaCacheNestedBlock) nestedBlock() (*tfprotov6.SchemaNestedBlock, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	block, err := c.Block.block()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov6.SchemaNestedBlock{
+		TypeName: c.TypeName,
+		Block:    block,
+		Nesting:  c.Nesting,
+		MinItems: c.MinItems,
+		MaxItems: c.MaxItems,
+	}, nil
+}
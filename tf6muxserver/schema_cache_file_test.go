@@ -0,0 +1,505 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithSchemaCacheFile(t *testing.T) {
+	t.Parallel()
+
+	newTestServers := func() []*tf6testserver.TestServer {
+		return []*tf6testserver.TestServer{
+			{
+				ProviderSchema: &tfprotov6.Schema{
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource_server1": {
+						Version: 1,
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name: "tags",
+									Type: tftypes.Map{
+										ElementType: tftypes.String,
+									},
+									Optional:        true,
+									Description:     "resource tags",
+									DescriptionKind: tfprotov6.StringKindMarkdown,
+								},
+							},
+							BlockTypes: []*tfprotov6.SchemaNestedBlock{
+								{
+									TypeName: "rule",
+									Nesting:  tfprotov6.SchemaNestedBlockNestingModeSet,
+									MaxItems: 2,
+									Block: &tfprotov6.SchemaBlock{
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name: "ports",
+												Type: tftypes.List{
+													ElementType: tftypes.Number,
+												},
+												Computed: true,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				ServerCapabilities: &tfprotov6.ServerCapabilities{
+					PlanDestroy: true,
+				},
+			},
+			{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source_server2": {
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:      "secret",
+									Type:      tftypes.String,
+									Computed:  true,
+									Sensitive: true,
+								},
+								{
+									Name: "endpoints",
+									NestedType: &tfprotov6.SchemaObject{
+										Nesting: tfprotov6.SchemaObjectNestingModeList,
+										Attributes: []*tfprotov6.SchemaAttribute{
+											{
+												Name:     "url",
+												Type:     tftypes.String,
+												Computed: true,
+											},
+										},
+									},
+									Computed: true,
+								},
+							},
+						},
+					},
+				},
+				ProviderMetaSchema: &tfprotov6.Schema{
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "module_id",
+								Type:     tftypes.String,
+								Optional: true,
+							},
+						},
+					},
+				},
+				ProviderSchema: &tfprotov6.Schema{
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		cacheFileContent              []byte
+		cacheVersion                  string
+		expectGetProviderSchemaCalled bool
+	}{
+		"cache-hit": {
+			cacheVersion:                  "1.0.0",
+			expectGetProviderSchemaCalled: false,
+		},
+		"cache-invalid": {
+			cacheFileContent:              []byte("not json"),
+			expectGetProviderSchemaCalled: true,
+		},
+		"cache-missing": {
+			expectGetProviderSchemaCalled: true,
+		},
+		"cache-version-mismatch": {
+			cacheVersion:                  "0.9.0",
+			expectGetProviderSchemaCalled: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "schema-cache.json")
+
+			if testCase.cacheFileContent != nil {
+				if err := os.WriteFile(path, testCase.cacheFileContent, 0600); err != nil {
+					t.Fatalf("unable to write cache file: %s", err)
+				}
+			}
+
+			var expectedResp *tfprotov6.GetProviderSchemaResponse
+
+			if testCase.cacheVersion != "" {
+				var serverFuncs []func() tfprotov6.ProviderServer
+
+				for _, server := range newTestServers() {
+					serverFuncs = append(serverFuncs, server.ProviderServer)
+				}
+
+				muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{
+					tf6muxserver.WithSchemaCacheFile(path, testCase.cacheVersion),
+				}, serverFuncs...)
+
+				if err != nil {
+					t.Fatalf("unexpected error setting up cache file: %s", err)
+				}
+
+				expectedResp, err = muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			// The muxed server is created twice with new servers, as the
+			// first creation rewrites the cache file when it is not used.
+			for i := 0; i < 2; i++ {
+				testServers := newTestServers()
+
+				var serverFuncs []func() tfprotov6.ProviderServer
+
+				for _, server := range testServers {
+					serverFuncs = append(serverFuncs, server.ProviderServer)
+				}
+
+				muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{
+					tf6muxserver.WithSchemaCacheFile(path, "1.0.0"),
+				}, serverFuncs...)
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if testServers[0].GetProviderSchemaCalled != testCase.expectGetProviderSchemaCalled {
+					t.Errorf("expected GetProviderSchema called %t, got %t", testCase.expectGetProviderSchemaCalled, testServers[0].GetProviderSchemaCalled)
+				}
+
+				resp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if expectedResp == nil {
+					expectedResp = resp
+				}
+
+				if diff := cmp.Diff(resp, expectedResp); diff != "" {
+					t.Errorf("unexpected response difference: %s", diff)
+				}
+
+				_, err = muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+					TypeName: "test_resource_server1",
+				})
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !testServers[0].ReadResourceCalled["test_resource_server1"] {
+					t.Errorf("expected test_resource_server1 ReadResource to be called on server1")
+				}
+
+				_, err = muxServer.ProviderServer().ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+					TypeName: "test_data_source_server2",
+				})
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !testServers[1].ReadDataSourceCalled["test_data_source_server2"] {
+					t.Errorf("expected test_data_source_server2 ReadDataSource to be called on server2")
+				}
+
+				testCase.expectGetProviderSchemaCalled = false
+			}
+		})
+	}
+}
+
+func TestWithSchemaCacheFileOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options                       []tf6muxserver.ServerOption
+		expectGetProviderSchemaCalled bool
+		expectedResourceTypes         []string
+	}{
+		"unchanged": {
+			expectGetProviderSchemaCalled: false,
+			expectedResourceTypes:         []string{"test_resource"},
+		},
+		"conflict-resolution": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithConflictResolution(tf6muxserver.ConflictResolutionFirstServer),
+			},
+			expectGetProviderSchemaCalled: true,
+			expectedResourceTypes:         []string{"test_resource"},
+		},
+		"namespace": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithNamespace(0, "example_"),
+			},
+			expectGetProviderSchemaCalled: true,
+			expectedResourceTypes:         []string{"example_test_resource"},
+		},
+		"namespace-empty": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithNamespace(0, ""),
+			},
+			expectGetProviderSchemaCalled: false,
+			expectedResourceTypes:         []string{"test_resource"},
+		},
+		"provider-schema-merge": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithProviderSchemaMerge(true),
+			},
+			expectGetProviderSchemaCalled: true,
+			expectedResourceTypes:         []string{"test_resource"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "schema-cache.json")
+			newTestServer := func() *tf6testserver.TestServer {
+				return &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}
+			}
+
+			_, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{
+				tf6muxserver.WithSchemaCacheFile(path, "1.0.0"),
+			}, newTestServer().ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up cache file: %s", err)
+			}
+
+			testServer := newTestServer()
+			options := append([]tf6muxserver.ServerOption{tf6muxserver.WithSchemaCacheFile(path, "1.0.0")}, testCase.options...)
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, options, testServer.ProviderServer)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if testServer.GetProviderSchemaCalled != testCase.expectGetProviderSchemaCalled {
+				t.Errorf("expected GetProviderSchema called %t, got %t", testCase.expectGetProviderSchemaCalled, testServer.GetProviderSchemaCalled)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var resourceTypes []string
+
+			for resourceType := range resp.ResourceSchemas {
+				resourceTypes = append(resourceTypes, resourceType)
+			}
+
+			if diff := cmp.Diff(resourceTypes, testCase.expectedResourceTypes); diff != "" {
+				t.Errorf("unexpected resource types difference: %s", diff)
+			}
+		})
+	}
+}
+
+// fingerprintServer is a server implementing SchemaFingerprinter which
+// counts its GetProviderSchema calls.
+type fingerprintServer struct {
+	countingSchemaServer
+
+	fingerprint string
+}
+
+func (s fingerprintServer) SchemaFingerprint() string {
+	return s.fingerprint
+}
+
+func TestWithSchemaCacheContentHash(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(attributeName string, fingerprint string, calls *int64) tfprotov6.ProviderServer {
+		server := countingSchemaServer{
+			ProviderServer: (&tf6testserver.TestServer{
+				ProviderSchema: &tfprotov6.Schema{
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     attributeName,
+								Type:     tftypes.String,
+								Optional: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": {},
+				},
+			}).ProviderServer(),
+			calls: calls,
+		}
+
+		if fingerprint == "" {
+			return server
+		}
+
+		return fingerprintServer{
+			countingSchemaServer: server,
+			fingerprint:          fingerprint,
+		}
+	}
+
+	testCases := map[string]struct {
+		cached            bool
+		cachedAttribute   string
+		cachedFingerprint string
+		attribute         string
+		fingerprint       string
+		expectedCalls     int64
+		expectedAttribute string
+	}{
+		"fingerprint-hit": {
+			cached:            true,
+			cachedAttribute:   "region",
+			cachedFingerprint: "1",
+			attribute:         "region",
+			fingerprint:       "1",
+			expectedCalls:     0,
+			expectedAttribute: "region",
+		},
+		"fingerprint-miss": {
+			attribute:         "region",
+			fingerprint:       "1",
+			expectedCalls:     1,
+			expectedAttribute: "region",
+		},
+		"fingerprint-stale": {
+			cached:            true,
+			cachedAttribute:   "region",
+			cachedFingerprint: "1",
+			attribute:         "endpoint",
+			fingerprint:       "2",
+			expectedCalls:     1,
+			expectedAttribute: "endpoint",
+		},
+		"schema-hit": {
+			cached:            true,
+			cachedAttribute:   "region",
+			attribute:         "region",
+			expectedCalls:     1,
+			expectedAttribute: "region",
+		},
+		"schema-miss": {
+			attribute:         "region",
+			expectedCalls:     1,
+			expectedAttribute: "region",
+		},
+		"schema-stale": {
+			cached:            true,
+			cachedAttribute:   "region",
+			attribute:         "endpoint",
+			expectedCalls:     1,
+			expectedAttribute: "endpoint",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			options := []tf6muxserver.ServerOption{
+				tf6muxserver.WithSchemaCacheFile(filepath.Join(t.TempDir(), "schema-cache.json"), "1.0.0"),
+				tf6muxserver.WithSchemaCacheContentHash(),
+			}
+
+			if testCase.cached {
+				var cachedCalls int64
+
+				cachedServer := newServer(testCase.cachedAttribute, testCase.cachedFingerprint, &cachedCalls)
+
+				_, err := tf6muxserver.NewMuxServerWithOptions(ctx, options, func() tfprotov6.ProviderServer { return cachedServer })
+
+				if err != nil {
+					t.Fatalf("unexpected error setting up cache file: %s", err)
+				}
+			}
+
+			var calls int64
+
+			server := newServer(testCase.attribute, testCase.fingerprint, &calls)
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, options, func() tfprotov6.ProviderServer { return server })
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			// Responses retrieved for the content hash are reused, so
+			// GetProviderSchema is called at most once per server.
+			if calls != testCase.expectedCalls {
+				t.Errorf("expected %d GetProviderSchema calls, got: %d", testCase.expectedCalls, calls)
+			}
+
+			resp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := resp.Provider.Block.Attributes[0].Name; got != testCase.expectedAttribute {
+				t.Errorf("expected provider schema attribute %q, got: %q", testCase.expectedAttribute, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package tf6muxserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// schemaContractDeclared is the Expected or Actual value of a
+// SchemaContractDifference for a type or nested block.
+const schemaContractDeclared = "declared"
+
+// SchemaContractError is returned during muxed server creation when the
+// WithSchemaContract option is enabled and the muxed server schemas do not
+// match the schema contract.
+type SchemaContractError struct {
+	// Differences are the differences between the schema contract and the
+	// muxed server schemas, sorted by Path.
+	Differences []SchemaContractDifference
+}
+
+// Error returns a description of the error, listing each difference.
+func (e *SchemaContractError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("the muxed server schemas do not match the schema contract:")
+
+	for _, difference := range e.Differences {
+		expected, actual := difference.Expected, difference.Actual
+
+		if expected == "" {
+			expected = "none"
+		}
+
+		if actual == "" {
+			actual = "none"
+		}
+
+		fmt.Fprintf(&b, "\n  - %s: expected %s, got %s", difference.Path, expected, actual)
+	}
+
+	return b.String()
+}
+
+// SchemaContractDifference is a type, attribute, or nested block which
+// differs between the schema contract and the muxed server schemas.
+type SchemaContractDifference struct {
+	// Path identifies the type, attribute, or nested block, such as
+	// `resource "examplecloud_thing" attribute "settings.name"`.
+	Path string
+
+	// Expected is the JSON representation of the attribute in the schema
+	// contract, or "declared" for a type or nested block. It is empty if the
+	// schema contract does not declare it.
+	Expected string
+
+	// Actual is the JSON representation of the attribute in the muxed server
+	// schemas, or "declared" for a type or nested block. It is empty if the
+	// muxed server schemas do not declare it.
+	Actual string
+}
+
+// schemaContract is the content of the schema contract file read by
+// WithSchemaContract. Omitted schemas are compared as empty.
+type schemaContract struct {
+	Provider    *schemaContractBlock            `json:"provider,omitempty"`
+	Resources   map[string]*schemaContractBlock `json:"resources,omitempty"`
+	DataSources map[string]*schemaContractBlock `json:"data_sources,omitempty"`
+}
+
+// schemaContractBlock is the JSON representation of the attributes and nested
+// blocks of a tfprotov6.SchemaBlock within a schema contract.
+type schemaContractBlock struct {
+	Attributes map[string]*schemaContractAttribute `json:"attributes,omitempty"`
+	BlockTypes map[string]*schemaContractBlock     `json:"block_types,omitempty"`
+}
+
+// schemaContractAttribute is the JSON representation of a
+// tfprotov6.SchemaAttribute within a schema contract. The attribute type uses
+// the tftypes JSON type encoding, such as "string" or ["list","string"]. The
+// type of a nested attribute is the object, or collection of objects, type of
+// its nested attributes.
+type schemaContractAttribute struct {
+	Type     json.RawMessage `json:"type"`
+	Required bool            `json:"required,omitempty"`
+	Optional bool            `json:"optional,omitempty"`
+	Computed bool            `json:"computed,omitempty"`
+}
+
+// readSchemaContractFile returns the schema contract from the file at the
+// given path.
+func readSchemaContractFile(path string) (*schemaContract, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema contract file: %w", err)
+	}
+
+	var contract schemaContract
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&contract); err != nil {
+		return nil, fmt.Errorf("unable to decode schema contract file: %w", err)
+	}
+
+	return &contract, nil
+}
+
+// checkSchemaContract returns an error if the muxed server schemas do not
+// match the schema contract file configured via the WithSchemaContract
+// option. Any differences are returned as a *SchemaContractError.
+func (s muxServer) checkSchemaContract() error {
+	contract, err := readSchemaContractFile(s.options.schemaContract)
+
+	if err != nil {
+		return err
+	}
+
+	var differences []SchemaContractDifference
+
+	providerDifferences, err := schemaContractBlockDifferences("provider", "", contract.Provider, schemaBlock(s.providerSchema))
+
+	if err != nil {
+		return err
+	}
+
+	differences = append(differences, providerDifferences...)
+
+	resourceDifferences, err := schemaContractTypeDifferences("resource", contract.Resources, s.resourceSchemas)
+
+	if err != nil {
+		return err
+	}
+
+	differences = append(differences, resourceDifferences...)
+
+	dataSourceDifferences, err := schemaContractTypeDifferences("data source", contract.DataSources, s.dataSourceSchemas)
+
+	if err != nil {
+		return err
+	}
+
+	differences = append(differences, dataSourceDifferences...)
+
+	if len(differences) == 0 {
+		return nil
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+
+	return &SchemaContractError{
+		Differences: differences,
+	}
+}
+
+// schemaContractTypeDifferences returns the differences between the resource
+// or data source types of the schema contract and the schemas.
+func schemaContractTypeDifferences(kind string, contractBlocks map[string]*schemaContractBlock, schemas map[string]*tfprotov6.Schema) ([]SchemaContractDifference, error) {
+	var differences []SchemaContractDifference
+
+	for typeName, contractBlock := range contractBlocks {
+		path := fmt.Sprintf("%s %q", kind, typeName)
+		schema, ok := schemas[typeName]
+
+		if !ok {
+			differences = append(differences, SchemaContractDifference{
+				Path:     path,
+				Expected: schemaContractDeclared,
+			})
+
+			continue
+		}
+
+		typeDifferences, err := schemaContractBlockDifferences(path, "", contractBlock, schemaBlock(schema))
+
+		if err != nil {
+			return nil, err
+		}
+
+		differences = append(differences, typeDifferences...)
+	}
+
+	for typeName := range schemas {
+		if _, ok := contractBlocks[typeName]; ok {
+			continue
+		}
+
+		differences = append(differences, SchemaContractDifference{
+			Path:   fmt.Sprintf("%s %q", kind, typeName),
+			Actual: schemaContractDeclared,
+		})
+	}
+
+	return differences, nil
+}
+
+// schemaContractBlockDifferences returns the differences between the
+// attributes and nested blocks of the schema contract block and the schema
+// block, including within nested blocks. Nested names are prefixed, such as
+// "settings.name".
+func schemaContractBlockDifferences(path string, prefix string, contractBlock *schemaContractBlock, block *tfprotov6.SchemaBlock) ([]SchemaContractDifference, error) {
+	var differences []SchemaContractDifference
+
+	if contractBlock == nil {
+		contractBlock = &schemaContractBlock{}
+	}
+
+	attributes := make(map[string]*tfprotov6.SchemaAttribute)
+	nestedBlocks := make(map[string]*tfprotov6.SchemaNestedBlock)
+
+	if block != nil {
+		for _, attribute := range block.Attributes {
+			if attribute != nil {
+				attributes[attribute.Name] = attribute
+			}
+		}
+
+		for _, nestedBlock := range block.BlockTypes {
+			if nestedBlock != nil {
+				nestedBlocks[nestedBlock.TypeName] = nestedBlock
+			}
+		}
+	}
+
+	for name, contractAttribute := range contractBlock.Attributes {
+		attributePath := fmt.Sprintf("%s attribute %q", path, prefix+name)
+
+		expected, err := json.Marshal(contractAttribute)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode schema contract %s: %w", attributePath, err)
+		}
+
+		attribute, ok := attributes[name]
+
+		if !ok {
+			differences = append(differences, SchemaContractDifference{
+				Path:     attributePath,
+				Expected: string(expected),
+			})
+
+			continue
+		}
+
+		expectedType, err := tftypes.ParseJSONType(contractAttribute.Type) //nolint:staticcheck
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse schema contract %s type: %w", attributePath, err)
+		}
+
+		actual, err := newSchemaContractAttributeJSON(attribute)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode %s: %w", attributePath, err)
+		}
+
+		if attribute.ValueType() != nil && attribute.ValueType().Equal(expectedType) && attribute.Required == contractAttribute.Required &&
+			attribute.Optional == contractAttribute.Optional && attribute.Computed == contractAttribute.Computed {
+			continue
+		}
+
+		differences = append(differences, SchemaContractDifference{
+			Path:     attributePath,
+			Expected: string(expected),
+			Actual:   actual,
+		})
+	}
+
+	for name, attribute := range attributes {
+		if _, ok := contractBlock.Attributes[name]; ok {
+			continue
+		}
+
+		attributePath := fmt.Sprintf("%s attribute %q", path, prefix+name)
+
+		actual, err := newSchemaContractAttributeJSON(attribute)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode %s: %w", attributePath, err)
+		}
+
+		differences = append(differences, SchemaContractDifference{
+			Path:   attributePath,
+			Actual: actual,
+		})
+	}
+
+	for name, contractNestedBlock := range contractBlock.BlockTypes {
+		nestedBlock, ok := nestedBlocks[name]
+
+		if !ok {
+			differences = append(differences, SchemaContractDifference{
+				Path:     fmt.Sprintf("%s block %q", path, prefix+name),
+				Expected: schemaContractDeclared,
+			})
+
+			continue
+		}
+
+		nestedDifferences, err := schemaContractBlockDifferences(path, prefix+name+".", contractNestedBlock, nestedBlock.Block)
+
+		if err != nil {
+			return nil, err
+		}
+
+		differences = append(differences, nestedDifferences...)
+	}
+
+	for name := range nestedBlocks {
+		if _, ok := contractBlock.BlockTypes[name]; ok {
+			continue
+		}
+
+		differences = append(differences, SchemaContractDifference{
+			Path:   fmt.Sprintf("%s block %q", path, prefix+name),
+			Actual: schemaContractDeclared,
+		})
+	}
+
+	return differences, nil
+}
+
+// newSchemaContractAttributeJSON returns the JSON representation of the
+// attribute within a schema contract.
+func newSchemaContractAttributeJSON(attribute *tfprotov6.SchemaAttribute) (string, error) {
+	contractAttribute := &schemaContractAttribute{
+		Type:     json.RawMessage("null"),
+		Required: attribute.Required,
+		Optional: attribute.Optional,
+		Computed: attribute.Computed,
+	}
+
+	if attribute.ValueType() != nil {
+		typ, err := attribute.ValueType().MarshalJSON()
+
+		if err != nil {
+			return "", err
+		}
+
+		contractAttribute.Type = typ
+	}
+
+	result, err := json.Marshal(contractAttribute)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
@@ -0,0 +1,250 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithSchemaContract(t *testing.T) {
+	t.Parallel()
+
+	servers := []func() tfprotov6.ProviderServer{
+		(&tf6testserver.TestServer{
+			ProviderSchema: &tfprotov6.Schema{
+				Block: &tfprotov6.SchemaBlock{
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{
+							Name:     "region",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource": {
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "id",
+								Type:     tftypes.String,
+								Computed: true,
+							},
+							{
+								Name: "settings",
+								NestedType: &tfprotov6.SchemaObject{
+									Nesting: tfprotov6.SchemaObjectNestingModeList,
+									Attributes: []*tfprotov6.SchemaAttribute{
+										{
+											Name:     "name",
+											Type:     tftypes.String,
+											Optional: true,
+										},
+									},
+								},
+								Optional: true,
+							},
+							{
+								Name:     "tags",
+								Type:     tftypes.Map{ElementType: tftypes.String},
+								Optional: true,
+							},
+						},
+						BlockTypes: []*tfprotov6.SchemaNestedBlock{
+							{
+								TypeName: "timeouts",
+								Nesting:  tfprotov6.SchemaNestedBlockNestingModeSingle,
+								Block: &tfprotov6.SchemaBlock{
+									Attributes: []*tfprotov6.SchemaAttribute{
+										{
+											Name:     "create",
+											Type:     tftypes.String,
+											Optional: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+		(&tf6testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov6.Schema{
+				"test_data_source": {
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "name",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+		}).ProviderServer,
+	}
+
+	testCases := map[string]struct {
+		contract            string
+		expectedDifferences []tf6muxserver.SchemaContractDifference
+		expectedError       bool
+	}{
+		"invalid-json": {
+			contract:      `{"resources": [}`,
+			expectedError: true,
+		},
+		"invalid-type": {
+			contract: `{
+				"provider": {"attributes": {"region": {"type": "not-a-type", "optional": true}}}
+			}`,
+			expectedError: true,
+		},
+		"matching": {
+			contract: `{
+				"provider": {"attributes": {"region": {"type": "string", "optional": true}}},
+				"resources": {
+					"test_resource": {
+						"attributes": {
+							"id": {"type": "string", "computed": true},
+							"settings": {"type": ["list", ["object", {"name": "string"}]], "optional": true},
+							"tags": {"type": ["map", "string"], "optional": true}
+						},
+						"block_types": {
+							"timeouts": {"attributes": {"create": {"type": "string", "optional": true}}}
+						}
+					}
+				},
+				"data_sources": {
+					"test_data_source": {"attributes": {"name": {"type": "string", "required": true}}}
+				}
+			}`,
+		},
+		"mismatching": {
+			contract: `{
+				"provider": {"attributes": {"region": {"type": "string", "required": true}}},
+				"resources": {
+					"test_other_resource": {},
+					"test_resource": {
+						"attributes": {
+							"id": {"type": "string", "computed": true},
+							"name": {"type": "string", "optional": true},
+							"settings": {"type": ["list", ["object", {"name": "number"}]], "optional": true}
+						},
+						"block_types": {
+							"timeouts": {"attributes": {"create": {"type": "number", "optional": true}}}
+						}
+					}
+				},
+				"data_sources": {}
+			}`,
+			expectedDifferences: []tf6muxserver.SchemaContractDifference{
+				{
+					Path:   `data source "test_data_source"`,
+					Actual: "declared",
+				},
+				{
+					Path:     `provider attribute "region"`,
+					Expected: `{"type":"string","required":true}`,
+					Actual:   `{"type":"string","optional":true}`,
+				},
+				{
+					Path:     `resource "test_other_resource"`,
+					Expected: "declared",
+				},
+				{
+					Path:     `resource "test_resource" attribute "name"`,
+					Expected: `{"type":"string","optional":true}`,
+				},
+				{
+					Path:     `resource "test_resource" attribute "settings"`,
+					Expected: `{"type":["list",["object",{"name":"number"}]],"optional":true}`,
+					Actual:   `{"type":["list",["object",{"name":"string"}]],"optional":true}`,
+				},
+				{
+					Path:   `resource "test_resource" attribute "tags"`,
+					Actual: `{"type":["map","string"],"optional":true}`,
+				},
+				{
+					Path:     `resource "test_resource" attribute "timeouts.create"`,
+					Expected: `{"type":"number","optional":true}`,
+					Actual:   `{"type":"string","optional":true}`,
+				},
+			},
+		},
+		"unknown-field": {
+			contract:      `{"resource_schemas": {}}`,
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "contract.json")
+
+			if err := os.WriteFile(path, []byte(testCase.contract), 0o600); err != nil {
+				t.Fatalf("unable to write schema contract file: %s", err)
+			}
+
+			_, err := tf6muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf6muxserver.ServerOption{
+					tf6muxserver.WithSchemaContract(path),
+				},
+				servers...,
+			)
+
+			var contractErr *tf6muxserver.SchemaContractError
+
+			if errors.As(err, &contractErr) {
+				if diff := cmp.Diff(contractErr.Differences, testCase.expectedDifferences); diff != "" {
+					t.Errorf("unexpected differences: %s", diff)
+				}
+
+				return
+			}
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError || testCase.expectedDifferences != nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestWithSchemaContractMissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := tf6muxserver.NewMuxServerWithOptions(
+		context.Background(),
+		[]tf6muxserver.ServerOption{
+			tf6muxserver.WithSchemaContract(filepath.Join(t.TempDir(), "missing.json")),
+		},
+		(&tf6testserver.TestServer{}).ProviderServer,
+	)
+
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
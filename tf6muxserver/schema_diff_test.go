@@ -0,0 +1,242 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a                              func() tfprotov6.ProviderServer
+		b                              func() tfprotov6.ProviderServer
+		expectedReport                 *tf6muxserver.SchemaDiffReport
+		expectedOverlappingDataSources []string
+		expectedOverlappingResources   []string
+		expectedError                  bool
+	}{
+		"differences": {
+			a: (&tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source_a": {},
+					"test_data_source":   {},
+				},
+				ProviderSchema: &tfprotov6.Schema{
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+							{
+								Name:     "region",
+								Type:     tftypes.String,
+								Optional: true,
+							},
+							{
+								Name:     "token",
+								Type:     tftypes.String,
+								Optional: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource_a": {},
+					"test_resource":   {},
+				},
+			}).ProviderServer,
+			b: (&tf6testserver.TestServer{
+				DataSourceSchemas: map[string]*tfprotov6.Schema{
+					"test_data_source": {},
+				},
+				ProviderSchema: &tfprotov6.Schema{
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+							{
+								Name:     "endpoint",
+								Type:     tftypes.String,
+								Optional: true,
+							},
+							{
+								Name:     "region",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource_b": {},
+				},
+			}).ProviderServer,
+			expectedReport: &tf6muxserver.SchemaDiffReport{
+				DataSources: []tf6muxserver.SchemaDiffType{
+					{
+						TypeName:   "test_data_source",
+						DeclaredBy: tf6muxserver.SchemaDiffSideBoth,
+					},
+					{
+						TypeName:   "test_data_source_a",
+						DeclaredBy: tf6muxserver.SchemaDiffSideA,
+					},
+				},
+				Resources: []tf6muxserver.SchemaDiffType{
+					{
+						TypeName:   "test_resource",
+						DeclaredBy: tf6muxserver.SchemaDiffSideA,
+					},
+					{
+						TypeName:   "test_resource_a",
+						DeclaredBy: tf6muxserver.SchemaDiffSideA,
+					},
+					{
+						TypeName:   "test_resource_b",
+						DeclaredBy: tf6muxserver.SchemaDiffSideB,
+					},
+				},
+				ProviderAttributes: []tf6muxserver.SchemaDiffAttribute{
+					{
+						Name: "endpoint",
+						B: &tfprotov6.SchemaAttribute{
+							Name:     "endpoint",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+					{
+						Name: "region",
+						A: &tfprotov6.SchemaAttribute{
+							Name:     "region",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+						B: &tfprotov6.SchemaAttribute{
+							Name:     "region",
+							Type:     tftypes.String,
+							Required: true,
+						},
+					},
+					{
+						Name: "token",
+						A: &tfprotov6.SchemaAttribute{
+							Name:     "token",
+							Type:     tftypes.String,
+							Optional: true,
+						},
+					},
+				},
+			},
+			expectedOverlappingDataSources: []string{"test_data_source"},
+		},
+		"error": {
+			a: (&tf6testserver.TestServer{}).ProviderServer,
+			b: (&tf6testserver.TestServer{
+				GetProviderSchemaDiagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "test error summary",
+					},
+				},
+			}).ProviderServer,
+			expectedError: true,
+		},
+		"identical": {
+			a: (&tf6testserver.TestServer{
+				ProviderSchema: &tfprotov6.Schema{
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": {},
+				},
+			}).ProviderServer,
+			b: (&tf6testserver.TestServer{
+				ProviderSchema: &tfprotov6.Schema{
+					Block: &tfprotov6.SchemaBlock{
+						Attributes: []*tfprotov6.SchemaAttribute{
+							{
+								Name:     "account_id",
+								Type:     tftypes.String,
+								Required: true,
+							},
+						},
+					},
+				},
+				ResourceSchemas: map[string]*tfprotov6.Schema{
+					"test_resource": {},
+				},
+			}).ProviderServer,
+			expectedReport: &tf6muxserver.SchemaDiffReport{
+				Resources: []tf6muxserver.SchemaDiffType{
+					{
+						TypeName:   "test_resource",
+						DeclaredBy: tf6muxserver.SchemaDiffSideBoth,
+					},
+				},
+			},
+			expectedOverlappingResources: []string{"test_resource"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := tf6muxserver.SchemaDiff(context.Background(), testCase.a, testCase.b)
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			if diff := cmp.Diff(report, testCase.expectedReport, cmpopts.IgnoreFields(tf6muxserver.SchemaDiffAttribute{}, "Diff")); diff != "" {
+				t.Errorf("unexpected report difference: %s", diff)
+			}
+
+			for _, attribute := range report.ProviderAttributes {
+				if attribute.Diff == "" {
+					t.Errorf("expected provider attribute %q Diff", attribute.Name)
+				}
+			}
+
+			if diff := cmp.Diff(report.OverlappingDataSources(), testCase.expectedOverlappingDataSources); diff != "" {
+				t.Errorf("unexpected overlapping data sources difference: %s", diff)
+			}
+
+			if diff := cmp.Diff(report.OverlappingResources(), testCase.expectedOverlappingResources); diff != "" {
+				t.Errorf("unexpected overlapping resources difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package tf6muxserver
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// logSchemaContributions emits a DEBUG level log containing the type names
+// declared by a server, so conflicts between servers are easier to find.
+func logSchemaContributions(ctx context.Context, serverIndex int, resp *tfprotov6.GetProviderSchemaResponse) {
+	logging.MuxDebug(ctx, "server schema contributions", map[string]interface{}{
+		logging.KeyTfMuxDataSourceTypes: sortedSchemaTypeNames(resp.DataSourceSchemas),
+		logging.KeyTfMuxResourceTypes:   sortedSchemaTypeNames(resp.ResourceSchemas),
+		logging.KeyTfMuxServerIndex:     serverIndex,
+	})
+}
+
+// sortedSchemaTypeNames returns the sorted type names of the schemas.
+func sortedSchemaTypeNames(schemas map[string]*tfprotov6.Schema) []string {
+	typeNames := make([]string, 0, len(schemas))
+
+	for typeName := range schemas {
+		typeNames = append(typeNames, typeName)
+	}
+
+	sort.Strings(typeNames)
+
+	return typeNames
+}
@@ -1,31 +1,124 @@
 package tf6muxserver
 
 import (
+	"sort"
+
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
-// schemaCmpOptions ensures comparisons of SchemaAttribute and
-// SchemaNestedBlock slices are considered equal despite ordering differences.
-var schemaCmpOptions = []cmp.Option{
-	cmpopts.SortSlices(func(i, j *tfprotov6.SchemaAttribute) bool {
-		return i.Name < j.Name
-	}),
-	cmpopts.SortSlices(func(i, j *tfprotov6.SchemaNestedBlock) bool {
-		return i.TypeName < j.TypeName
-	}),
-}
-
 // schemaDiff outputs the difference between schemas while accounting for
-// inconsequential ordering differences in SchemaAttribute and
-// SchemaNestedBlock slices.
+// inconsequential differences, as described by normalizeSchema.
 func schemaDiff(i, j *tfprotov6.Schema) string {
-	return cmp.Diff(i, j, schemaCmpOptions...)
+	return cmp.Diff(normalizeSchema(i), normalizeSchema(j))
 }
 
-// schemaEquals asserts equality between schemas by normalizing inconsequential
-// ordering differences in SchemaAttribute and SchemaNestedBlock slices.
+// schemaEquals asserts equality between schemas after normalizing
+// inconsequential differences, as described by normalizeSchema.
 func schemaEquals(i, j *tfprotov6.Schema) bool {
-	return cmp.Equal(i, j, schemaCmpOptions...)
+	return cmp.Equal(normalizeSchema(i), normalizeSchema(j))
+}
+
+// normalizeSchema returns a copy of the schema with inconsequential
+// differences removed, so that cosmetically different but equivalent schemas
+// compare equal. The given schema is not modified. The normalization rules
+// are:
+//
+//   - A nil schema block is equivalent to an empty schema block, including
+//     within nested blocks.
+//   - Attributes, including nested attributes, are sorted by name and
+//     nested blocks are sorted by type name.
+//   - Nil and empty attribute, nested attribute, and nested block slices
+//     are equivalent.
+//   - Nil attributes and nested blocks are removed.
+//
+// Unset optional fields, such as DescriptionKind, are already their zero
+// values and are compared as-is.
+func normalizeSchema(schema *tfprotov6.Schema) *tfprotov6.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	return &tfprotov6.Schema{
+		Version: schema.Version,
+		Block:   normalizeSchemaBlock(schema.Block),
+	}
+}
+
+func normalizeSchemaBlock(block *tfprotov6.SchemaBlock) *tfprotov6.SchemaBlock {
+	if block == nil {
+		return &tfprotov6.SchemaBlock{}
+	}
+
+	result := &tfprotov6.SchemaBlock{
+		Version:         block.Version,
+		Description:     block.Description,
+		DescriptionKind: block.DescriptionKind,
+		Deprecated:      block.Deprecated,
+	}
+
+	for _, attribute := range block.Attributes {
+		if attribute == nil {
+			continue
+		}
+
+		result.Attributes = append(result.Attributes, normalizeSchemaAttribute(attribute))
+	}
+
+	sort.Slice(result.Attributes, func(i, j int) bool {
+		return result.Attributes[i].Name < result.Attributes[j].Name
+	})
+
+	result.BlockTypes = normalizeSchemaNestedBlocks(block.BlockTypes)
+
+	return result
+}
+
+func normalizeSchemaAttribute(attribute *tfprotov6.SchemaAttribute) *tfprotov6.SchemaAttribute {
+	if attribute.NestedType == nil {
+		return attribute
+	}
+
+	result := *attribute
+	result.NestedType = &tfprotov6.SchemaObject{
+		Nesting: attribute.NestedType.Nesting,
+	}
+
+	for _, nestedAttribute := range attribute.NestedType.Attributes {
+		if nestedAttribute == nil {
+			continue
+		}
+
+		result.NestedType.Attributes = append(result.NestedType.Attributes, normalizeSchemaAttribute(nestedAttribute))
+	}
+
+	sort.Slice(result.NestedType.Attributes, func(i, j int) bool {
+		return result.NestedType.Attributes[i].Name < result.NestedType.Attributes[j].Name
+	})
+
+	return &result
+}
+
+func normalizeSchemaNestedBlocks(nestedBlocks []*tfprotov6.SchemaNestedBlock) []*tfprotov6.SchemaNestedBlock {
+	var result []*tfprotov6.SchemaNestedBlock
+
+	for _, nestedBlock := range nestedBlocks {
+		if nestedBlock == nil {
+			continue
+		}
+
+		result = append(result, &tfprotov6.SchemaNestedBlock{
+			TypeName: nestedBlock.TypeName,
+			Block:    normalizeSchemaBlock(nestedBlock.Block),
+			Nesting:  nestedBlock.Nesting,
+			MinItems: nestedBlock.MinItems,
+			MaxItems: nestedBlock.MaxItems,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TypeName < result[j].TypeName
+	})
+
+	return result
 }
@@ -0,0 +1,23 @@
+package tf6muxserver
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// ServerCapabilities returns the protocol features advertised by the muxed
+// server in the GetProviderSchema response, such as to verify capability
+// negotiation without retrieving the full schema. The capabilities are the
+// global intersection of the capabilities of the servers which declared
+// managed resources: a capability, such as PlanDestroy, is only advertised
+// if every such server advertised it. Servers which declared no managed
+// resources do not affect the result. Unlike the GetProviderSchema response,
+// the result is never nil; the returned value is a copy and may be modified.
+func (s muxServer) ServerCapabilities() *tfprotov6.ServerCapabilities {
+	if s.serverCapabilities == nil {
+		return &tfprotov6.ServerCapabilities{}
+	}
+
+	result := *s.serverCapabilities
+
+	return &result
+}
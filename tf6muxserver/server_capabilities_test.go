@@ -0,0 +1,122 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestMuxServerServerCapabilities(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		servers                    []func() tfprotov6.ProviderServer
+		expectedServerCapabilities *tfprotov6.ServerCapabilities
+	}{
+		"all-plan-destroy": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server2": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov6.ServerCapabilities{
+				PlanDestroy: true,
+			},
+		},
+		"data-source-only-server-without-plan-destroy": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source_server2": {},
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov6.ServerCapabilities{
+				PlanDestroy: true,
+			},
+		},
+		"mixed-plan-destroy": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_server2": {},
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov6.ServerCapabilities{},
+		},
+		"no-resources": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source_server1": {},
+					},
+					ServerCapabilities: &tfprotov6.ServerCapabilities{
+						PlanDestroy: true,
+					},
+				}).ProviderServer,
+			},
+			expectedServerCapabilities: &tfprotov6.ServerCapabilities{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			muxServer, err := tf6muxserver.NewMuxServer(context.Background(), testCase.servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := muxServer.ServerCapabilities()
+
+			if diff := cmp.Diff(got, testCase.expectedServerCapabilities); diff != "" {
+				t.Errorf("unexpected server capabilities difference: %s", diff)
+			}
+
+			// The returned value is a copy.
+			got.PlanDestroy = !got.PlanDestroy
+
+			if diff := cmp.Diff(muxServer.ServerCapabilities(), testCase.expectedServerCapabilities); diff != "" {
+				t.Errorf("unexpected server capabilities difference after modification: %s", diff)
+			}
+		})
+	}
+}
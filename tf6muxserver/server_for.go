@@ -0,0 +1,29 @@
+package tf6muxserver
+
+// ServerForResource returns the index of the server implementing the managed
+// resource type, and whether any server implements it. The server index is
+// the position of the server in the order given to NewMuxServer, excluding
+// servers excluded via the WithBestEffortSchema option, and reflects any
+// Reroute calls. Routers configured via the WithCustomRouter option are not
+// consulted, as they select servers per request. It is safe to call
+// concurrently with other methods.
+func (s muxServer) ServerForResource(typeName string) (int, bool) {
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	serverIndex, ok := s.resourceServerIndex[typeName]
+
+	return serverIndex, ok
+}
+
+// ServerForDataSource returns the index of the server implementing the data
+// source type, and whether any server implements it, in the same manner as
+// ServerForResource.
+func (s muxServer) ServerForDataSource(typeName string) (int, bool) {
+	s.routingMu.RLock()
+	defer s.routingMu.RUnlock()
+
+	serverIndex, ok := s.dataSourceServerIndex[typeName]
+
+	return serverIndex, ok
+}
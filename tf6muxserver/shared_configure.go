@@ -0,0 +1,84 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConfigureResultSharer is an optional interface which the primary server,
+// configured via the WithSharedConfigure option, implements to share the
+// result of its ConfigureProvider call with the other servers, such as a
+// configured API client, so the other servers do not each re-establish
+// connections.
+type ConfigureResultSharer interface {
+	// ConfigureResult returns the result of the most recent successful
+	// ConfigureProvider call of the server.
+	ConfigureResult() interface{}
+}
+
+// sharedConfigureResultKey is the context key of the result shared by the
+// primary server via ConfigureResultSharer.
+type sharedConfigureResultKey struct{}
+
+// SharedConfigureResult returns the result shared by the primary server,
+// configured via the WithSharedConfigure option, and whether there is one.
+// It is intended to be called by the other servers with the context given to
+// their ConfigureProvider method, which is always called after the primary
+// server was configured successfully.
+func SharedConfigureResult(ctx context.Context) (interface{}, bool) {
+	result, ok := ctx.Value(sharedConfigureResultKey{}).(sharedConfigureResult)
+
+	if !ok {
+		return nil, false
+	}
+
+	return result.value, true
+}
+
+// sharedConfigureResult wraps the shared result, so a nil result is
+// distinguished from no result.
+type sharedConfigureResult struct {
+	value interface{}
+}
+
+// checkSharedConfigure returns an error if the primary server configured via
+// the WithSharedConfigure option is out of range, does not implement
+// ConfigureResultSharer, or is not configured first per the
+// WithConfigureOrder option.
+func (s muxServer) checkSharedConfigure() error {
+	primary := s.options.sharedConfigurePrimary
+
+	if primary < 0 || primary >= len(s.servers) {
+		return fmt.Errorf("shared configure primary server index %d is out of range for %d servers", primary, len(s.servers))
+	}
+
+	if _, ok := s.servers[primary].(ConfigureResultSharer); !ok {
+		return fmt.Errorf("shared configure primary server %d (%T) does not implement ConfigureResultSharer", primary, s.servers[primary])
+	}
+
+	if s.options.configureOrder != nil && s.options.configureOrder[0] != primary {
+		return fmt.Errorf("shared configure primary server %d must be first in the configure order, however server %d is first", primary, s.options.configureOrder[0])
+	}
+
+	return nil
+}
+
+// sharedConfigureContext returns the context with the result of the primary
+// server, configured via the WithSharedConfigure option, if the server is
+// the primary server and implements ConfigureResultSharer. Otherwise, the
+// context is returned unchanged.
+func (s muxServer) sharedConfigureContext(ctx context.Context, serverIndex int, server interface{}) context.Context {
+	if !s.options.sharedConfigure || serverIndex != s.options.sharedConfigurePrimary {
+		return ctx
+	}
+
+	sharer, ok := server.(ConfigureResultSharer)
+
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, sharedConfigureResultKey{}, sharedConfigureResult{
+		value: sharer.ConfigureResult(),
+	})
+}
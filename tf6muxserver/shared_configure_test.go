@@ -0,0 +1,185 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// sharedClient is a client configured by a primary server and shared with
+// the other servers.
+type sharedClient struct {
+	endpoint string
+}
+
+// primaryServer is a server implementing ConfigureResultSharer which
+// configures a sharedClient.
+type primaryServer struct {
+	*tf6testserver.TestServer
+
+	configureProviderDiagnostics []*tfprotov6.Diagnostic
+
+	client *sharedClient
+}
+
+func (s *primaryServer) ConfigureProvider(_ context.Context, _ *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	s.client = &sharedClient{
+		endpoint: "https://example.com",
+	}
+
+	return &tfprotov6.ConfigureProviderResponse{
+		Diagnostics: s.configureProviderDiagnostics,
+	}, nil
+}
+
+func (s *primaryServer) ConfigureResult() interface{} {
+	return s.client
+}
+
+// secondaryServer is a server recording the result shared with it during
+// ConfigureProvider.
+type secondaryServer struct {
+	*tf6testserver.TestServer
+
+	configured   bool
+	sharedResult interface{}
+}
+
+func (s *secondaryServer) ConfigureProvider(ctx context.Context, _ *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	s.configured = true
+	s.sharedResult, _ = tf6muxserver.SharedConfigureResult(ctx)
+
+	return &tfprotov6.ConfigureProviderResponse{}, nil
+}
+
+func TestWithSharedConfigure(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options                      []tf6muxserver.ServerOption
+		configureProviderDiagnostics []*tfprotov6.Diagnostic
+		expectedError                string
+		expectedConfigured           bool
+		expectedShared               bool
+	}{
+		"disabled": {
+			expectedConfigured: true,
+		},
+		"shared": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithSharedConfigure(1),
+			},
+			expectedConfigured: true,
+			expectedShared:     true,
+		},
+		"shared-configure-order": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithConfigureOrder([]int{1, 0}),
+				tf6muxserver.WithSharedConfigure(1),
+			},
+			expectedConfigured: true,
+			expectedShared:     true,
+		},
+		"primary-error-diagnostic": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithSharedConfigure(1),
+			},
+			configureProviderDiagnostics: []*tfprotov6.Diagnostic{
+				{
+					Severity: tfprotov6.DiagnosticSeverityError,
+					Summary:  "test error summary",
+				},
+			},
+		},
+		"primary-not-first": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithConfigureOrder([]int{0, 1}),
+				tf6muxserver.WithSharedConfigure(1),
+			},
+			expectedError: "shared configure primary server 1 must be first in the configure order, however server 0 is first",
+		},
+		"primary-not-sharer": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithSharedConfigure(0),
+			},
+			expectedError: "shared configure primary server 0 (*tf6muxserver_test.secondaryServer) does not implement ConfigureResultSharer",
+		},
+		"primary-out-of-range": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithSharedConfigure(2),
+			},
+			expectedError: "shared configure primary server index 2 is out of range for 2 servers",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			secondary := &secondaryServer{
+				TestServer: &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_secondary": {},
+					},
+				},
+			}
+			primary := &primaryServer{
+				TestServer: &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_primary": {},
+					},
+				},
+				configureProviderDiagnostics: testCase.configureProviderDiagnostics,
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+				ctx,
+				testCase.options,
+				func() tfprotov6.ProviderServer { return secondary },
+				func() tfprotov6.ProviderServer { return primary },
+			)
+
+			if err != nil {
+				if err.Error() != testCase.expectedError {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error %q", testCase.expectedError)
+			}
+
+			_, err = muxServer.ProviderServer().ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if secondary.configured != testCase.expectedConfigured {
+				t.Errorf("expected secondary server configured %t, got: %t", testCase.expectedConfigured, secondary.configured)
+			}
+
+			if !testCase.expectedShared {
+				if secondary.sharedResult != nil {
+					t.Errorf("unexpected shared result: %v", secondary.sharedResult)
+				}
+
+				return
+			}
+
+			client, ok := secondary.sharedResult.(*sharedClient)
+
+			if !ok || client != primary.client {
+				t.Errorf("expected shared result to be the primary server client, got: %v", secondary.sharedResult)
+			}
+		})
+	}
+}
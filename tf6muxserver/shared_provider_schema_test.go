@@ -0,0 +1,87 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+func TestWithRequireSharedProviderSchema(t *testing.T) {
+	t.Parallel()
+
+	providerSchema := &tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name:     "account_id",
+					Type:     tftypes.String,
+					Required: true,
+				},
+			},
+		},
+	}
+
+	servers := []*tf6testserver.TestServer{
+		{
+			ProviderSchema: providerSchema,
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource_server1": {},
+			},
+		},
+		{
+			ProviderSchema: providerSchema,
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource_server2": {},
+			},
+		},
+	}
+
+	options := []tf6muxserver.ServerOption{
+		tf6muxserver.WithProviderSchemaMerge(true),
+		tf6muxserver.WithRequireSharedProviderSchema(true),
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), options, servers[0].ProviderServer, servers[1].ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	config, err := tfprotov6.NewDynamicValue(providerSchema.ValueType(), tftypes.NewValue(providerSchema.ValueType(), map[string]tftypes.Value{
+		"account_id": tftypes.NewValue(tftypes.String, "test-account"),
+	}))
+
+	if err != nil {
+		t.Fatalf("unexpected error creating config: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ValidateProviderConfig(context.Background(), &tfprotov6.ValidateProviderConfigRequest{
+		Config: &config,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ValidateProviderConfig error: %s", err)
+	}
+
+	_, err = muxServer.ProviderServer().ConfigureProvider(context.Background(), &tfprotov6.ConfigureProviderRequest{
+		Config: &config,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected ConfigureProvider error: %s", err)
+	}
+
+	for serverIndex, server := range servers {
+		if !server.ValidateProviderConfigCalled {
+			t.Errorf("expected server %d ValidateProviderConfig to be called", serverIndex)
+		}
+
+		if !server.ConfigureProviderCalled {
+			t.Errorf("expected server %d ConfigureProvider to be called", serverIndex)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package tf6muxserver
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// normalizedState returns the input state if the state returned by the
+// server is semantically equal to it when decoded using the resource schema
+// type, such as when only the order of set elements differs. Otherwise, or if
+// the states cannot be compared, the returned state is returned. The field is
+// the response field of the returned state, used for logging.
+func (s muxServer) normalizedState(ctx context.Context, typeName string, field string, returned *tfprotov6.DynamicValue, input *tfprotov6.DynamicValue) *tfprotov6.DynamicValue {
+	if returned == nil || input == nil || returned == input {
+		return returned
+	}
+
+	schema := s.resourceSchemas[typeName]
+
+	if schema == nil {
+		return returned
+	}
+
+	equal, err := dynamicValueEquals(schema.ValueType(), returned, input)
+
+	if err != nil {
+		logging.MuxDebug(ctx, "unable to compare state for normalization, using server state", map[string]interface{}{
+			logging.KeyError:                  err.Error(),
+			logging.KeyTfMuxDynamicValueField: field,
+		})
+
+		return returned
+	}
+
+	if !equal {
+		return returned
+	}
+
+	logging.MuxTrace(ctx, "replacing semantically equal server state with input state", map[string]interface{}{
+		logging.KeyTfMuxDynamicValueField: field,
+	})
+
+	return input
+}
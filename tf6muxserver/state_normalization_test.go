@@ -0,0 +1,169 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// fixedStateServer is a server whose PlanResourceChange, ApplyResourceChange,
+// and ReadResource methods return the given state.
+type fixedStateServer struct {
+	tfprotov6.ProviderServer
+
+	state *tfprotov6.DynamicValue
+}
+
+func (s fixedStateServer) ApplyResourceChange(_ context.Context, _ *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	return &tfprotov6.ApplyResourceChangeResponse{
+		NewState: s.state,
+	}, nil
+}
+
+func (s fixedStateServer) PlanResourceChange(_ context.Context, _ *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	return &tfprotov6.PlanResourceChangeResponse{
+		PlannedState: s.state,
+	}, nil
+}
+
+func (s fixedStateServer) ReadResource(_ context.Context, _ *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	return &tfprotov6.ReadResourceResponse{
+		NewState: s.state,
+	}, nil
+}
+
+func TestWithStateNormalization(t *testing.T) {
+	t.Parallel()
+
+	schema := &tfprotov6.Schema{
+		Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{
+					Name:     "tags",
+					Type:     tftypes.Set{ElementType: tftypes.String},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	newState := func(tags ...string) *tfprotov6.DynamicValue {
+		elements := make([]tftypes.Value, 0, len(tags))
+
+		for _, tag := range tags {
+			elements = append(elements, tftypes.NewValue(tftypes.String, tag))
+		}
+
+		state, err := tfprotov6.NewDynamicValue(schema.ValueType(), tftypes.NewValue(schema.ValueType(), map[string]tftypes.Value{
+			"tags": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elements),
+		}))
+
+		if err != nil {
+			t.Fatalf("unable to create state: %s", err)
+		}
+
+		return &state
+	}
+
+	inputState := newState("a", "b")
+	reorderedState := newState("b", "a")
+	differentState := newState("a", "c")
+
+	testCases := map[string]struct {
+		types         []string
+		serverState   *tfprotov6.DynamicValue
+		expectedState *tfprotov6.DynamicValue
+	}{
+		"disabled": {
+			serverState:   reorderedState,
+			expectedState: reorderedState,
+		},
+		"enabled-different": {
+			types:         []string{"test_resource"},
+			serverState:   differentState,
+			expectedState: differentState,
+		},
+		"enabled-other-type": {
+			types:         []string{"test_resource_other"},
+			serverState:   reorderedState,
+			expectedState: reorderedState,
+		},
+		"enabled-reordered": {
+			types:         []string{"test_resource"},
+			serverState:   reorderedState,
+			expectedState: inputState,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			servers := []func() tfprotov6.ProviderServer{
+				func() tfprotov6.ProviderServer {
+					return fixedStateServer{
+						ProviderServer: &tf6testserver.TestServer{
+							ResourceSchemas: map[string]*tfprotov6.Schema{
+								"test_resource": schema,
+							},
+						},
+						state: testCase.serverState,
+					}
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(ctx, []tf6muxserver.ServerOption{tf6muxserver.WithStateNormalization(testCase.types)}, servers...)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			planResp, err := muxServer.ProviderServer().PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				ProposedNewState: inputState,
+				TypeName:         "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(planResp.PlannedState, testCase.expectedState); diff != "" {
+				t.Errorf("unexpected PlanResourceChange PlannedState difference: %s", diff)
+			}
+
+			applyResp, err := muxServer.ProviderServer().ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+				PlannedState: inputState,
+				TypeName:     "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(applyResp.NewState, testCase.expectedState); diff != "" {
+				t.Errorf("unexpected ApplyResourceChange NewState difference: %s", diff)
+			}
+
+			readResp, err := muxServer.ProviderServer().ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+				CurrentState: inputState,
+				TypeName:     "test_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(readResp.NewState, testCase.expectedState); diff != "" {
+				t.Errorf("unexpected ReadResource NewState difference: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package tf6muxserver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// TestKnownResponseFieldsCoverage verifies knownResponseFields lists exactly
+// the fields of every tfprotov6.ProviderServer response type, so response
+// fields added upstream cannot be missed.
+func TestKnownResponseFieldsCoverage(t *testing.T) {
+	t.Parallel()
+
+	providerServerType := reflect.TypeOf((*tfprotov6.ProviderServer)(nil)).Elem()
+
+	for i := 0; i < providerServerType.NumMethod(); i++ {
+		method := providerServerType.Method(i)
+		respType := method.Type.Out(0).Elem()
+
+		t.Run(method.Name, func(t *testing.T) {
+			knownFields, ok := knownResponseFields[respType]
+
+			if !ok {
+				t.Fatalf("knownResponseFields does not list %s fields", respType)
+			}
+
+			var fields []string
+
+			for i := 0; i < respType.NumField(); i++ {
+				if respType.Field(i).IsExported() {
+					fields = append(fields, respType.Field(i).Name)
+				}
+			}
+
+			if diff := cmp.Diff(knownFields, fields); diff != "" {
+				t.Errorf("unexpected %s fields difference, review how the muxed server handles the fields before updating knownResponseFields: %s", respType, diff)
+			}
+		})
+	}
+}
+
+func TestUnknownResponseFields(t *testing.T) {
+	t.Parallel()
+
+	known := map[reflect.Type][]string{
+		reflect.TypeOf(tfprotov6.ReadResourceResponse{}): {"Diagnostics"},
+	}
+
+	testCases := map[string]struct {
+		resp     interface{}
+		expected []string
+	}{
+		"nil": {
+			resp: (*tfprotov6.ReadResourceResponse)(nil),
+		},
+		"known-fields": {
+			resp: &tfprotov6.ReadResourceResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "test summary",
+					},
+				},
+			},
+		},
+		"unknown-fields": {
+			resp: &tfprotov6.ReadResourceResponse{
+				NewState: &tfprotov6.DynamicValue{},
+				Private:  []byte("test"),
+			},
+			expected: []string{"NewState", "Private"},
+		},
+		"unknown-fields-zero": {
+			resp: &tfprotov6.ReadResourceResponse{},
+		},
+		"unlisted-type": {
+			resp: &tfprotov6.ReadDataSourceResponse{
+				State: &tfprotov6.DynamicValue{},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := unknownResponseFields(known, testCase.resp)
+
+			if diff := cmp.Diff(got, testCase.expected); diff != "" {
+				t.Errorf("unexpected unknown fields difference: %s", diff)
+			}
+		})
+	}
+}
+
+func TestStrictResponseFieldsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	known := map[reflect.Type][]string{
+		reflect.TypeOf(tfprotov6.ReadResourceResponse{}): {"Diagnostics"},
+	}
+
+	testCases := map[string]struct {
+		resp          *tfprotov6.ReadResourceResponse
+		err           error
+		expectedError string
+	}{
+		"error": {
+			err:           errors.New("test error"),
+			expectedError: "test error",
+		},
+		"known-fields": {
+			resp: &tfprotov6.ReadResourceResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityWarning,
+						Summary:  "test summary",
+					},
+				},
+			},
+		},
+		"unknown-fields": {
+			resp: &tfprotov6.ReadResourceResponse{
+				NewState: &tfprotov6.DynamicValue{},
+				Private:  []byte("test"),
+			},
+			expectedError: "ReadResource response sets fields unknown to the muxed server, which would not be forwarded: NewState, Private",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := strictResponseFieldsMiddleware(known)(func(_ context.Context, _ string, _ interface{}) (interface{}, error) {
+				return testCase.resp, testCase.err
+			})
+
+			resp, err := handler(context.Background(), "ReadResource", &tfprotov6.ReadResourceRequest{})
+
+			if err != nil {
+				if err.Error() != testCase.expectedError {
+					t.Errorf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+
+				if resp != nil && testCase.err == nil {
+					t.Errorf("unexpected response: %v", resp)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error %q", testCase.expectedError)
+			}
+
+			if resp != testCase.resp {
+				t.Errorf("expected response to be passed through")
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// timeoutHintServer is a server implementing TimeoutHintProvider whose
+// ApplyResourceChange method records the remaining time until the context
+// deadline.
+type timeoutHintServer struct {
+	*tf6testserver.TestServer
+
+	timeoutHints map[string]time.Duration
+
+	applyResourceChangeTimeout time.Duration
+}
+
+func (s *timeoutHintServer) ApplyResourceChange(ctx context.Context, _ *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		s.applyResourceChangeTimeout = time.Until(deadline)
+	}
+
+	return &tfprotov6.ApplyResourceChangeResponse{}, nil
+}
+
+func (s *timeoutHintServer) TimeoutHints() map[string]time.Duration {
+	return s.timeoutHints
+}
+
+func TestWithTimeoutHints(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options         []tf6muxserver.ServerOption
+		typeName        string
+		expectedTimeout time.Duration
+	}{
+		"disabled": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithRPCTimeout("ApplyResourceChange", time.Minute),
+			},
+			typeName:        "test_slow_resource",
+			expectedTimeout: time.Minute,
+		},
+		"hinted": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithRPCTimeout("ApplyResourceChange", time.Minute),
+				tf6muxserver.WithTimeoutHints(),
+			},
+			typeName:        "test_slow_resource",
+			expectedTimeout: time.Hour,
+		},
+		"hinted-shorter-than-rpc-timeout": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithRPCTimeout("ApplyResourceChange", 2*time.Hour),
+				tf6muxserver.WithTimeoutHints(),
+			},
+			typeName:        "test_slow_resource",
+			expectedTimeout: 2 * time.Hour,
+		},
+		"hinted-without-rpc-timeout": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithTimeoutHints(),
+			},
+			typeName: "test_slow_resource",
+		},
+		"not-hinted": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithRPCTimeout("ApplyResourceChange", time.Minute),
+				tf6muxserver.WithTimeoutHints(),
+			},
+			typeName:        "test_resource",
+			expectedTimeout: time.Minute,
+		},
+		"not-hinted-without-rpc-timeout": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithTimeoutHints(),
+			},
+			typeName: "test_resource",
+		},
+		"other-server-type": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithTimeoutHints(),
+			},
+			typeName: "test_other_resource",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			hintServer := &timeoutHintServer{
+				TestServer: &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource":      {},
+						"test_slow_resource": {},
+					},
+				},
+				timeoutHints: map[string]time.Duration{
+					"test_other_resource": time.Hour,
+					"test_slow_resource":  time.Hour,
+				},
+			}
+			otherServer := &timeoutHintServer{
+				TestServer: &tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_other_resource": {},
+					},
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+				ctx,
+				testCase.options,
+				func() tfprotov6.ProviderServer { return hintServer },
+				func() tfprotov6.ProviderServer { return otherServer },
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+				TypeName: testCase.typeName,
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			timeout := hintServer.applyResourceChangeTimeout + otherServer.applyResourceChangeTimeout
+
+			// The remaining time is measured after the deadline is set, so
+			// allow for the time elapsed since.
+			if timeout > testCase.expectedTimeout || timeout < testCase.expectedTimeout-time.Second {
+				t.Errorf("expected ApplyResourceChange timeout of %s, got: %s", testCase.expectedTimeout, timeout)
+			}
+		})
+	}
+}
+
+// routingChanger is implemented by muxed servers.
+type routingChanger interface {
+	ReplaceServer(serverIndex int, server func() tfprotov6.ProviderServer) error
+	Reroute(typeName string, serverIndex int) error
+}
+
+func TestWithTimeoutHintsRoutingChange(t *testing.T) {
+	t.Parallel()
+
+	// Each change returns the server which the type is routed to afterwards.
+	testCases := map[string]struct {
+		change          func(muxServer routingChanger, servers []*timeoutHintServer) (*timeoutHintServer, error)
+		expectedTimeout time.Duration
+	}{
+		"ReplaceServer": {
+			change: func(muxServer routingChanger, _ []*timeoutHintServer) (*timeoutHintServer, error) {
+				replacement := &timeoutHintServer{
+					TestServer: &tf6testserver.TestServer{
+						ResourceSchemas: map[string]*tfprotov6.Schema{
+							"test_slow_resource": {},
+						},
+					},
+				}
+
+				return replacement, muxServer.ReplaceServer(0, func() tfprotov6.ProviderServer { return replacement })
+			},
+			expectedTimeout: time.Minute,
+		},
+		"Reroute": {
+			change: func(muxServer routingChanger, servers []*timeoutHintServer) (*timeoutHintServer, error) {
+				return servers[1], muxServer.Reroute("test_slow_resource", 1)
+			},
+			expectedTimeout: 2 * time.Hour,
+		},
+		"unchanged": {
+			change: func(_ routingChanger, servers []*timeoutHintServer) (*timeoutHintServer, error) {
+				return servers[0], nil
+			},
+			expectedTimeout: time.Hour,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			servers := []*timeoutHintServer{
+				{
+					TestServer: &tf6testserver.TestServer{
+						ResourceSchemas: map[string]*tfprotov6.Schema{
+							"test_slow_resource": {},
+						},
+					},
+					timeoutHints: map[string]time.Duration{
+						"test_slow_resource": time.Hour,
+					},
+				},
+				{
+					TestServer: &tf6testserver.TestServer{
+						ResourceSchemas: map[string]*tfprotov6.Schema{
+							"test_slow_resource": {},
+						},
+					},
+					timeoutHints: map[string]time.Duration{
+						"test_slow_resource": 2 * time.Hour,
+					},
+				},
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+				ctx,
+				[]tf6muxserver.ServerOption{
+					tf6muxserver.WithConflictResolution(tf6muxserver.ConflictResolutionFirstServer),
+					tf6muxserver.WithRPCTimeout("ApplyResourceChange", time.Minute),
+					tf6muxserver.WithTimeoutHints(),
+				},
+				func() tfprotov6.ProviderServer { return servers[0] },
+				func() tfprotov6.ProviderServer { return servers[1] },
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error setting up factory: %s", err)
+			}
+
+			server, err := testCase.change(muxServer, servers)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = muxServer.ProviderServer().ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+				TypeName: "test_slow_resource",
+			})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			timeout := server.applyResourceChangeTimeout
+
+			// The remaining time is measured after the deadline is set, so
+			// allow for the time elapsed since.
+			if timeout > testCase.expectedTimeout || timeout < testCase.expectedTimeout-time.Second {
+				t.Errorf("expected ApplyResourceChange timeout of %s, got: %s", testCase.expectedTimeout, timeout)
+			}
+		})
+	}
+}
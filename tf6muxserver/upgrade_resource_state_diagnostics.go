@@ -0,0 +1,33 @@
+package tf6muxserver
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// UpgradeResourceStateDiagnosticsFunc returns muxed server diagnostics for an
+// UpgradeResourceState request, such as warnings about the state version,
+// which are prepended to the diagnostics of the server implementing the
+// resource type.
+type UpgradeResourceStateDiagnosticsFunc func(ctx context.Context, req *tfprotov6.UpgradeResourceStateRequest) []*tfprotov6.Diagnostic
+
+// upgradeResourceStateResponseWithDiagnostics returns a copy of the response
+// with the diagnostics prepended to the response diagnostics. The upgraded
+// state is preserved. A nil response results in a response containing only the
+// diagnostics.
+func upgradeResourceStateResponseWithDiagnostics(resp *tfprotov6.UpgradeResourceStateResponse, diags []*tfprotov6.Diagnostic) *tfprotov6.UpgradeResourceStateResponse {
+	if len(diags) == 0 {
+		return resp
+	}
+
+	result := &tfprotov6.UpgradeResourceStateResponse{}
+
+	if resp != nil {
+		*result = *resp
+	}
+
+	result.Diagnostics = append(append(make([]*tfprotov6.Diagnostic, 0, len(diags)+len(result.Diagnostics)), diags...), result.Diagnostics...)
+
+	return result
+}
@@ -0,0 +1,126 @@
+package tf6muxserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/logging"
+)
+
+// WarmupFunc prepares a server to serve requests, such as by establishing
+// its clients, returning an error if it fails. It is configured via the
+// WithWarmupFunc option and called by Warmup.
+type WarmupFunc func(ctx context.Context, server tfprotov6.ProviderServer) error
+
+// Warmup prepares each server to serve requests ahead of the first request
+// from Terraform, such as for servers with a slow cold start, by calling the
+// WarmupFunc configured via the WithWarmupFunc option for each server
+// concurrently, limited by the WithMaxConcurrency option. By default,
+// the ConfigureProvider method of each server is called with a null provider
+// configuration and error Diagnostics are treated as failures.
+//
+// Warmup is opt-in and may not be safe for all providers: by default, servers
+// are configured before, and again when, Terraform configures the provider,
+// and servers which require provider configuration may fail or behave
+// differently. Configure a WarmupFunc if the default is not suitable.
+//
+// All failures are returned, one per line in server order, prefixed with the
+// index and Go type of the server.
+func (s muxServer) Warmup(ctx context.Context) error {
+	ctx = logging.InitContext(ctx)
+
+	servers := s.currentServers()
+	errs := make([]string, len(servers))
+
+	var wg sync.WaitGroup
+
+	for serverIndex, server := range servers {
+		// Logging context fields cannot be safely set concurrently, so the
+		// server context is created before starting each goroutine.
+		serverCtx := logging.Tfprotov6ProviderServerContext(ctx, server)
+
+		wg.Add(1)
+
+		go func(ctx context.Context, serverIndex int, server tfprotov6.ProviderServer) {
+			defer wg.Done()
+
+			if err := s.fanOutLimiter.acquire(ctx); err != nil {
+				errs[serverIndex] = fmt.Sprintf("server %d (%T): %s", serverIndex, server, err)
+				return
+			}
+
+			defer s.fanOutLimiter.release()
+
+			logging.MuxTrace(ctx, "warming up downstream server", map[string]interface{}{
+				logging.KeyTfMuxServerIndex: serverIndex,
+			})
+
+			if err := s.warmupServer(ctx, serverIndex, server); err != nil {
+				errs[serverIndex] = fmt.Sprintf("server %d (%T): %s", serverIndex, server, err)
+			}
+		}(serverCtx, serverIndex, server)
+	}
+
+	wg.Wait()
+
+	var failures []string
+
+	for _, err := range errs {
+		if err != "" {
+			failures = append(failures, err)
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("error warming up servers:\n%s", strings.Join(failures, "\n"))
+}
+
+// warmupServer calls the WarmupFunc configured via the WithWarmupFunc option
+// for the server, or by default its ConfigureProvider method with a null
+// configuration of its provider schema type.
+func (s muxServer) warmupServer(ctx context.Context, serverIndex int, server tfprotov6.ProviderServer) error {
+	if s.options.warmupFunc != nil {
+		return s.options.warmupFunc(ctx, server)
+	}
+
+	configType := tftypes.Type(tftypes.Object{AttributeTypes: map[string]tftypes.Type{}})
+
+	if serverIndex < len(s.serverProviderSchemas) && s.serverProviderSchemas[serverIndex] != nil {
+		configType = s.serverProviderSchemas[serverIndex].ValueType()
+	}
+
+	config, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, nil))
+
+	if err != nil {
+		return fmt.Errorf("unable to create null provider config: %w", err)
+	}
+
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: &config,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if resp == nil {
+		return nil
+	}
+
+	for _, diag := range resp.Diagnostics {
+		if diag == nil || diag.Severity != tfprotov6.DiagnosticSeverityError {
+			continue
+		}
+
+		return fmt.Errorf("error configuring provider: %s: %s", diag.Summary, diag.Detail)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package tf6muxserver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxservertest"
+)
+
+func TestMuxServerWarmup(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		configureProviderResponse *tfprotov6.ConfigureProviderResponse
+		expectedError             string
+	}{
+		"configure-provider": {},
+		"configure-provider-error": {
+			configureProviderResponse: &tfprotov6.ConfigureProviderResponse{
+				Diagnostics: []*tfprotov6.Diagnostic{
+					{
+						Severity: tfprotov6.DiagnosticSeverityError,
+						Summary:  "test error summary",
+						Detail:   "test error detail",
+					},
+				},
+			},
+			expectedError: "error warming up servers:\n" +
+				"server 1 (*tf6muxservertest.RecordingServer): error configuring provider: test error summary: test error detail",
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recordingServers := []*tf6muxservertest.RecordingServer{
+				tf6muxservertest.NewRecordingServer((&tf6testserver.TestServer{
+					ProviderSchema: &tfprotov6.Schema{
+						Block: &tfprotov6.SchemaBlock{
+							Attributes: []*tfprotov6.SchemaAttribute{
+								{
+									Name:     "region",
+									Type:     tftypes.String,
+									Optional: true,
+								},
+							},
+						},
+					},
+				}).ProviderServer()),
+				tf6muxservertest.NewRecordingServer((&tf6testserver.TestServer{
+					ConfigureProviderResponse: testCase.configureProviderResponse,
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer()),
+			}
+
+			muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+				context.Background(),
+				[]tf6muxserver.ServerOption{
+					tf6muxserver.WithProviderSchemaMerge(true),
+				},
+				recordingServers[0].ProviderServer,
+				recordingServers[1].ProviderServer,
+			)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			err = muxServer.Warmup(context.Background())
+
+			if err == nil && testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			if err != nil && err.Error() != testCase.expectedError {
+				t.Fatalf("expected error %q, got %q", testCase.expectedError, err)
+			}
+
+			expectedConfigTypes := []tftypes.Type{
+				tftypes.Object{
+					AttributeTypes: map[string]tftypes.Type{
+						"region": tftypes.String,
+					},
+				},
+				tftypes.Object{
+					AttributeTypes: map[string]tftypes.Type{},
+				},
+			}
+
+			for idx, recordingServer := range recordingServers {
+				req := recordingServer.LastConfigureProviderRequest()
+
+				if req == nil || req.Config == nil {
+					t.Fatalf("expected server %d ConfigureProvider request config", idx)
+				}
+
+				config, err := req.Config.Unmarshal(expectedConfigTypes[idx])
+
+				if err != nil {
+					t.Fatalf("error unmarshaling server %d config: %s", idx, err)
+				}
+
+				if !config.IsNull() {
+					t.Errorf("expected server %d null config, got %s", idx, config)
+				}
+			}
+		})
+	}
+}
+
+func TestMuxServerWarmupParallel(t *testing.T) {
+	t.Parallel()
+
+	const serverCount = 3
+
+	var started int64
+
+	allStarted := make(chan struct{})
+
+	warmupFunc := func(ctx context.Context, server tfprotov6.ProviderServer) error {
+		if atomic.AddInt64(&started, 1) == serverCount {
+			close(allStarted)
+		}
+
+		// Each server waits for all servers to start, which only succeeds
+		// if the servers are warmed up concurrently.
+		select {
+		case <-allStarted:
+		case <-time.After(5 * time.Second):
+			return errors.New("timed out waiting for concurrent warmup")
+		}
+
+		if len(server.(*tf6testserver.TestServer).ResourceSchemas) == 0 {
+			return errors.New("no resources")
+		}
+
+		return nil
+	}
+
+	servers := make([]func() tfprotov6.ProviderServer, 0, serverCount)
+
+	for i := 0; i < serverCount; i++ {
+		testServer := &tf6testserver.TestServer{}
+
+		if i != 1 {
+			testServer.ResourceSchemas = map[string]*tfprotov6.Schema{
+				fmt.Sprintf("test_resource_server%d", i+1): {},
+			}
+		}
+
+		servers = append(servers, testServer.ProviderServer)
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServerWithOptions(
+		context.Background(),
+		[]tf6muxserver.ServerOption{
+			tf6muxserver.WithMaxConcurrency(serverCount),
+			tf6muxserver.WithWarmupFunc(warmupFunc),
+		},
+		servers...,
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = muxServer.Warmup(context.Background())
+
+	expectedError := "error warming up servers:\nserver 1 (*tf6testserver.TestServer): no resources"
+
+	if err == nil || err.Error() != expectedError {
+		t.Fatalf("expected error %q, got %v", expectedError, err)
+	}
+}
@@ -0,0 +1,99 @@
+package tf6muxservertest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxservertest"
+)
+
+// errorRecordingT records the errors of a test, rather than failing it.
+type errorRecordingT struct {
+	testing.TB
+
+	errors []string
+}
+
+func (t *errorRecordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *errorRecordingT) Helper() {}
+
+func TestAssertRouting(t *testing.T) {
+	t.Parallel()
+
+	muxServer, err := tf6muxserver.NewMuxServer(
+		context.Background(),
+		(&tf6testserver.TestServer{
+			DataSourceSchemas: map[string]*tfprotov6.Schema{
+				"test_data_source_server1": {},
+				"test_shared":              {},
+			},
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource_server1": {},
+			},
+		}).ProviderServer,
+		(&tf6testserver.TestServer{
+			ResourceSchemas: map[string]*tfprotov6.Schema{
+				"test_resource_server2": {},
+				"test_shared":           {},
+			},
+		}).ProviderServer,
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := map[string]struct {
+		expected       map[string]int
+		expectedFailed bool
+	}{
+		"matching": {
+			expected: map[string]int{
+				"test_data_source_server1": 0,
+				"test_resource_server1":    0,
+				"test_resource_server2":    1,
+			},
+		},
+		"mismatching-server-index": {
+			expected: map[string]int{
+				"test_resource_server1": 1,
+			},
+			expectedFailed: true,
+		},
+		"mismatching-shared-type-name": {
+			expected: map[string]int{
+				"test_shared": 0,
+			},
+			expectedFailed: true,
+		},
+		"unrouted": {
+			expected: map[string]int{
+				"test_resource_unknown": 0,
+			},
+			expectedFailed: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recordingT := &errorRecordingT{TB: t}
+
+			tf6muxservertest.AssertRouting(recordingT, muxServer, testCase.expected)
+
+			if failed := len(recordingT.errors) > 0; failed != testCase.expectedFailed {
+				t.Errorf("expected failed %t, got %t: %v", testCase.expectedFailed, failed, recordingT.errors)
+			}
+		})
+	}
+}
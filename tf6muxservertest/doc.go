@@ -0,0 +1,8 @@
+// Package tf6muxservertest contains helpers for testing protocol version 6
+// muxed provider servers.
+//
+// Refer to the NewRecordingServer() function for capturing the requests a
+// tf6muxserver forwards to an underlying server, the ProviderServerFactory()
+// function for acceptance testing muxed servers, and the AssertRouting()
+// function for verifying the server implementing each type name.
+package tf6muxservertest
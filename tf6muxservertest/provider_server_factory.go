@@ -0,0 +1,32 @@
+package tf6muxservertest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// ProviderServerFactory returns a function compatible with the
+// ProtoV6ProviderFactories field of acceptance testing TestCase types, which
+// creates a muxed server of the given servers each time it is called. Any
+// error creating the muxed server is returned, which fails the test.
+func ProviderServerFactory(servers ...func() tfprotov6.ProviderServer) func() (tfprotov6.ProviderServer, error) {
+	return ProviderServerFactoryWithOptions(nil, servers...)
+}
+
+// ProviderServerFactoryWithOptions returns a function in the same manner as
+// ProviderServerFactory, with optional muxed server behaviors configured by
+// the given options.
+func ProviderServerFactoryWithOptions(opts []tf6muxserver.ServerOption, servers ...func() tfprotov6.ProviderServer) func() (tfprotov6.ProviderServer, error) {
+	return func() (tfprotov6.ProviderServer, error) {
+		muxServer, err := tf6muxserver.NewMuxServerWithOptions(context.Background(), opts, servers...)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to create muxed provider server: %w", err)
+		}
+
+		return muxServer.ProviderServer(), nil
+	}
+}
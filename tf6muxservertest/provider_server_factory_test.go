@@ -0,0 +1,107 @@
+package tf6muxservertest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxservertest"
+)
+
+func TestProviderServerFactory(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options       []tf6muxserver.ServerOption
+		servers       []func() tfprotov6.ProviderServer
+		expectedError string
+	}{
+		"duplicate-resource": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			},
+			expectedError: `unable to create muxed provider server: resource "test_resource" is implemented by multiple servers; only one implementation allowed`,
+		},
+		"options": {
+			options: []tf6muxserver.ServerOption{
+				tf6muxserver.WithRequireProviderSchema(true),
+			},
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			},
+			expectedError: "unable to create muxed provider server: none of the 1 servers declared a provider schema",
+		},
+		"success": {
+			servers: []func() tfprotov6.ProviderServer{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					DataSourceSchemas: map[string]*tfprotov6.Schema{
+						"test_data_source": {},
+					},
+				}).ProviderServer,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			factory := tf6muxservertest.ProviderServerFactoryWithOptions(testCase.options, testCase.servers...)
+
+			server, err := factory()
+
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error %q, got: %s", testCase.expectedError, err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError != "" {
+				t.Fatalf("expected error: %s", testCase.expectedError)
+			}
+
+			resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if _, ok := resp.ResourceSchemas["test_resource"]; !ok {
+				t.Errorf("expected test_resource schema")
+			}
+
+			if _, ok := resp.DataSourceSchemas["test_data_source"]; !ok {
+				t.Errorf("expected test_data_source schema")
+			}
+		})
+	}
+}
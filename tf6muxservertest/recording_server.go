@@ -0,0 +1,237 @@
+package tf6muxservertest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+var _ tfprotov6.ProviderServer = &RecordingServer{}
+
+// RecordingServer is a tfprotov6.ProviderServer which records the last
+// request of each RPC before calling the same method on an underlying server.
+// It is safe for concurrent use and should always be instantiated by calling
+// NewRecordingServer().
+type RecordingServer struct {
+	mu     sync.Mutex
+	server tfprotov6.ProviderServer
+
+	lastApplyResourceChangeRequest        *tfprotov6.ApplyResourceChangeRequest
+	lastConfigureProviderRequest          *tfprotov6.ConfigureProviderRequest
+	lastGetProviderSchemaRequest          *tfprotov6.GetProviderSchemaRequest
+	lastImportResourceStateRequest        *tfprotov6.ImportResourceStateRequest
+	lastPlanResourceChangeRequest         *tfprotov6.PlanResourceChangeRequest
+	lastReadDataSourceRequest             *tfprotov6.ReadDataSourceRequest
+	lastReadResourceRequest               *tfprotov6.ReadResourceRequest
+	lastStopProviderRequest               *tfprotov6.StopProviderRequest
+	lastUpgradeResourceStateRequest       *tfprotov6.UpgradeResourceStateRequest
+	lastValidateDataResourceConfigRequest *tfprotov6.ValidateDataResourceConfigRequest
+	lastValidateProviderConfigRequest     *tfprotov6.ValidateProviderConfigRequest
+	lastValidateResourceConfigRequest     *tfprotov6.ValidateResourceConfigRequest
+}
+
+// NewRecordingServer returns a RecordingServer which calls the given server
+// after recording each request.
+func NewRecordingServer(server tfprotov6.ProviderServer) *RecordingServer {
+	return &RecordingServer{
+		server: server,
+	}
+}
+
+// ProviderServer is a function compatible with tf6muxserver.NewMuxServer.
+func (s *RecordingServer) ProviderServer() tfprotov6.ProviderServer {
+	return s
+}
+
+func (s *RecordingServer) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	s.mu.Lock()
+	s.lastApplyResourceChangeRequest = req
+	s.mu.Unlock()
+
+	return s.server.ApplyResourceChange(ctx, req)
+}
+
+// LastApplyResourceChangeRequest returns the most recent request, if any.
+func (s *RecordingServer) LastApplyResourceChangeRequest() *tfprotov6.ApplyResourceChangeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastApplyResourceChangeRequest
+}
+
+func (s *RecordingServer) ConfigureProvider(ctx context.Context, req *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	s.mu.Lock()
+	s.lastConfigureProviderRequest = req
+	s.mu.Unlock()
+
+	return s.server.ConfigureProvider(ctx, req)
+}
+
+// LastConfigureProviderRequest returns the most recent request, if any.
+func (s *RecordingServer) LastConfigureProviderRequest() *tfprotov6.ConfigureProviderRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastConfigureProviderRequest
+}
+
+func (s *RecordingServer) GetProviderSchema(ctx context.Context, req *tfprotov6.GetProviderSchemaRequest) (*tfprotov6.GetProviderSchemaResponse, error) {
+	s.mu.Lock()
+	s.lastGetProviderSchemaRequest = req
+	s.mu.Unlock()
+
+	return s.server.GetProviderSchema(ctx, req)
+}
+
+// LastGetProviderSchemaRequest returns the most recent request, if any.
+func (s *RecordingServer) LastGetProviderSchemaRequest() *tfprotov6.GetProviderSchemaRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastGetProviderSchemaRequest
+}
+
+func (s *RecordingServer) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	s.mu.Lock()
+	s.lastImportResourceStateRequest = req
+	s.mu.Unlock()
+
+	return s.server.ImportResourceState(ctx, req)
+}
+
+// LastImportResourceStateRequest returns the most recent request, if any.
+func (s *RecordingServer) LastImportResourceStateRequest() *tfprotov6.ImportResourceStateRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastImportResourceStateRequest
+}
+
+func (s *RecordingServer) PlanResourceChange(ctx context.Context, req *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	s.mu.Lock()
+	s.lastPlanResourceChangeRequest = req
+	s.mu.Unlock()
+
+	return s.server.PlanResourceChange(ctx, req)
+}
+
+// LastPlanResourceChangeRequest returns the most recent request, if any.
+func (s *RecordingServer) LastPlanResourceChangeRequest() *tfprotov6.PlanResourceChangeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastPlanResourceChangeRequest
+}
+
+func (s *RecordingServer) ValidateProviderConfig(ctx context.Context, req *tfprotov6.ValidateProviderConfigRequest) (*tfprotov6.ValidateProviderConfigResponse, error) {
+	s.mu.Lock()
+	s.lastValidateProviderConfigRequest = req
+	s.mu.Unlock()
+
+	return s.server.ValidateProviderConfig(ctx, req)
+}
+
+// LastValidateProviderConfigRequest returns the most recent request, if any.
+func (s *RecordingServer) LastValidateProviderConfigRequest() *tfprotov6.ValidateProviderConfigRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastValidateProviderConfigRequest
+}
+
+func (s *RecordingServer) ReadDataSource(ctx context.Context, req *tfprotov6.ReadDataSourceRequest) (*tfprotov6.ReadDataSourceResponse, error) {
+	s.mu.Lock()
+	s.lastReadDataSourceRequest = req
+	s.mu.Unlock()
+
+	return s.server.ReadDataSource(ctx, req)
+}
+
+// LastReadDataSourceRequest returns the most recent request, if any.
+func (s *RecordingServer) LastReadDataSourceRequest() *tfprotov6.ReadDataSourceRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastReadDataSourceRequest
+}
+
+func (s *RecordingServer) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	s.mu.Lock()
+	s.lastReadResourceRequest = req
+	s.mu.Unlock()
+
+	return s.server.ReadResource(ctx, req)
+}
+
+// LastReadResourceRequest returns the most recent request, if any.
+func (s *RecordingServer) LastReadResourceRequest() *tfprotov6.ReadResourceRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastReadResourceRequest
+}
+
+func (s *RecordingServer) StopProvider(ctx context.Context, req *tfprotov6.StopProviderRequest) (*tfprotov6.StopProviderResponse, error) {
+	s.mu.Lock()
+	s.lastStopProviderRequest = req
+	s.mu.Unlock()
+
+	return s.server.StopProvider(ctx, req)
+}
+
+// LastStopProviderRequest returns the most recent request, if any.
+func (s *RecordingServer) LastStopProviderRequest() *tfprotov6.StopProviderRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastStopProviderRequest
+}
+
+func (s *RecordingServer) UpgradeResourceState(ctx context.Context, req *tfprotov6.UpgradeResourceStateRequest) (*tfprotov6.UpgradeResourceStateResponse, error) {
+	s.mu.Lock()
+	s.lastUpgradeResourceStateRequest = req
+	s.mu.Unlock()
+
+	return s.server.UpgradeResourceState(ctx, req)
+}
+
+// LastUpgradeResourceStateRequest returns the most recent request, if any.
+func (s *RecordingServer) LastUpgradeResourceStateRequest() *tfprotov6.UpgradeResourceStateRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastUpgradeResourceStateRequest
+}
+
+func (s *RecordingServer) ValidateDataResourceConfig(ctx context.Context, req *tfprotov6.ValidateDataResourceConfigRequest) (*tfprotov6.ValidateDataResourceConfigResponse, error) {
+	s.mu.Lock()
+	s.lastValidateDataResourceConfigRequest = req
+	s.mu.Unlock()
+
+	return s.server.ValidateDataResourceConfig(ctx, req)
+}
+
+// LastValidateDataResourceConfigRequest returns the most recent request, if any.
+func (s *RecordingServer) LastValidateDataResourceConfigRequest() *tfprotov6.ValidateDataResourceConfigRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastValidateDataResourceConfigRequest
+}
+
+func (s *RecordingServer) ValidateResourceConfig(ctx context.Context, req *tfprotov6.ValidateResourceConfigRequest) (*tfprotov6.ValidateResourceConfigResponse, error) {
+	s.mu.Lock()
+	s.lastValidateResourceConfigRequest = req
+	s.mu.Unlock()
+
+	return s.server.ValidateResourceConfig(ctx, req)
+}
+
+// LastValidateResourceConfigRequest returns the most recent request, if any.
+func (s *RecordingServer) LastValidateResourceConfigRequest() *tfprotov6.ValidateResourceConfigRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastValidateResourceConfigRequest
+}
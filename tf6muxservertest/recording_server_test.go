@@ -0,0 +1,70 @@
+package tf6muxservertest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxservertest"
+)
+
+func TestRecordingServer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	state, err := tfprotov6.NewDynamicValue(tftypes.String, tftypes.NewValue(tftypes.String, "test"))
+
+	if err != nil {
+		t.Fatalf("unable to create DynamicValue: %s", err)
+	}
+
+	testServer := &tf6testserver.TestServer{
+		ResourceSchemas: map[string]*tfprotov6.Schema{
+			"test_resource": {},
+		},
+	}
+	recordingServer := tf6muxservertest.NewRecordingServer(testServer)
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, recordingServer.ProviderServer)
+
+	if err != nil {
+		t.Fatalf("unexpected error setting up factory: %s", err)
+	}
+
+	if recordingServer.LastReadResourceRequest() != nil {
+		t.Fatalf("expected no ReadResource request before calling ReadResource")
+	}
+
+	req := &tfprotov6.ReadResourceRequest{
+		CurrentState: &state,
+		Private:      []byte("private"),
+		TypeName:     "test_resource",
+	}
+
+	_, err = muxServer.ProviderServer().ReadResource(ctx, req)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff(recordingServer.LastReadResourceRequest(), req); diff != "" {
+		t.Errorf("unexpected ReadResource request: %s", diff)
+	}
+
+	if !testServer.ReadResourceCalled["test_resource"] {
+		t.Errorf("expected test_resource ReadResource to be called on underlying server")
+	}
+
+	if recordingServer.LastGetProviderSchemaRequest() == nil {
+		t.Errorf("expected GetProviderSchema request to be recorded")
+	}
+
+	if recordingServer.LastApplyResourceChangeRequest() != nil {
+		t.Errorf("unexpected ApplyResourceChange request recorded")
+	}
+}
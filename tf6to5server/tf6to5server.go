@@ -15,6 +15,8 @@ import (
 //
 //   - GetProviderSchema is called to ensure SchemaAttribute.NestedType
 //     (nested attributes) are not implemented.
+//   - GetProviderSchema is called to ensure schema value types are
+//     identical after conversion to protocol version 5.
 //
 // Protocol version 5 servers require Terraform CLI 0.12 or later.
 func DowngradeServer(ctx context.Context, v6server func() tfprotov6.ProviderServer) (tfprotov5.ProviderServer, error) {
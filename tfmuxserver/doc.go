@@ -0,0 +1,11 @@
+// Package tfmuxserver combines providers that implement protocol version 5
+// and protocol version 6 into a single muxed provider, choosing the protocol
+// version to serve.
+//
+// If all providers implement protocol version 5, they are combined using the
+// tf5muxserver package. Otherwise, protocol version 5 providers are upgraded
+// using the tf5to6server package and all providers are combined using the
+// tf6muxserver package.
+//
+// Refer to the NewMuxServer() function for creating a muxed server.
+package tfmuxserver
@@ -0,0 +1,133 @@
+package tfmuxserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+)
+
+// ProtocolVersion is a Terraform plugin protocol version.
+type ProtocolVersion int
+
+const (
+	// ProtocolVersion5 is Terraform plugin protocol version 5.
+	ProtocolVersion5 ProtocolVersion = 5
+
+	// ProtocolVersion6 is Terraform plugin protocol version 6, which requires
+	// Terraform CLI 1.0 or later.
+	ProtocolVersion6 ProtocolVersion = 6
+)
+
+// MuxServer is a muxed server created by NewMuxServer. Only the provider
+// server function of the chosen protocol version is set.
+type MuxServer struct {
+	// ProtocolVersion is the protocol version of the muxed server.
+	ProtocolVersion ProtocolVersion
+
+	// V5ProviderServer is a function compatible with tf5server.Serve. It is
+	// only set if ProtocolVersion is ProtocolVersion5.
+	V5ProviderServer func() tfprotov5.ProviderServer
+
+	// V6ProviderServer is a function compatible with tf6server.Serve. It is
+	// only set if ProtocolVersion is ProtocolVersion6.
+	V6ProviderServer func() tfprotov6.ProviderServer
+}
+
+// NewMuxServer returns a muxed server combining the given servers, which may
+// be any mix of protocol version 5 servers, of type
+// func() tfprotov5.ProviderServer, and protocol version 6 servers, of type
+// func() tfprotov6.ProviderServer, using the lowest protocol version
+// supported by all servers:
+//
+//   - If there are no protocol version 6 servers, the protocol version 5
+//     servers are combined with tf5muxserver.NewMuxServer and the muxed
+//     server serves protocol version 5.
+//   - Otherwise, each protocol version 5 server is upgraded with
+//     tf5to6server.UpgradeServer, all servers are combined with
+//     tf6muxserver.NewMuxServer, and the muxed server serves protocol
+//     version 6.
+//
+// Servers are muxed in the order given, regardless of their protocol version.
+// Upgraded protocol version 5 servers are created once and reused. An error
+// is returned if no servers are given, a server is of any other type, or the
+// servers cannot be muxed.
+func NewMuxServer(ctx context.Context, servers ...interface{}) (*MuxServer, error) {
+	if len(servers) == 0 {
+		return nil, errors.New("unable to create muxed server: no servers given")
+	}
+
+	var v5Servers []func() tfprotov5.ProviderServer
+	var hasV6Servers bool
+
+	for serverIndex, server := range servers {
+		switch server := server.(type) {
+		case func() tfprotov5.ProviderServer:
+			v5Servers = append(v5Servers, server)
+		case func() tfprotov6.ProviderServer:
+			hasV6Servers = true
+		default:
+			return nil, fmt.Errorf("unable to create muxed server: server %d has unsupported type %T, expected func() tfprotov5.ProviderServer or func() tfprotov6.ProviderServer", serverIndex, server)
+		}
+	}
+
+	if !hasV6Servers {
+		muxServer, err := tf5muxserver.NewMuxServer(ctx, v5Servers...)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to create protocol version 5 muxed server: %w", err)
+		}
+
+		return &MuxServer{
+			ProtocolVersion:  ProtocolVersion5,
+			V5ProviderServer: muxServer.ProviderServer,
+		}, nil
+	}
+
+	v6Servers := make([]func() tfprotov6.ProviderServer, 0, len(servers))
+
+	for serverIndex, server := range servers {
+		switch server := server.(type) {
+		case func() tfprotov5.ProviderServer:
+			upgradedServer, err := upgradeServer(ctx, serverIndex, server)
+
+			if err != nil {
+				return nil, err
+			}
+
+			v6Servers = append(v6Servers, upgradedServer)
+		case func() tfprotov6.ProviderServer:
+			v6Servers = append(v6Servers, server)
+		}
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, v6Servers...)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to create protocol version 6 muxed server: %w", err)
+	}
+
+	return &MuxServer{
+		ProtocolVersion:  ProtocolVersion6,
+		V6ProviderServer: muxServer.ProviderServer,
+	}, nil
+}
+
+// upgradeServer returns the protocol version 5 server upgraded to protocol
+// version 6. The upgraded server is created once and reused.
+func upgradeServer(ctx context.Context, serverIndex int, v5Server func() tfprotov5.ProviderServer) (func() tfprotov6.ProviderServer, error) {
+	upgradedServer, err := tf5to6server.UpgradeServer(ctx, v5Server)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to upgrade protocol version 5 server %d: %w", serverIndex, err)
+	}
+
+	return func() tfprotov6.ProviderServer {
+		return upgradedServer
+	}, nil
+}
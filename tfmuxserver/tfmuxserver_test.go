@@ -0,0 +1,172 @@
+package tfmuxserver_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf5testserver"
+	"github.com/hashicorp/terraform-plugin-mux/internal/tf6testserver"
+	"github.com/hashicorp/terraform-plugin-mux/tfmuxserver"
+)
+
+func TestNewMuxServer(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		servers                 []interface{}
+		expectedProtocolVersion tfmuxserver.ProtocolVersion
+		expectedResourceTypes   []string
+		expectedError           bool
+	}{
+		"no-servers": {
+			expectedError: true,
+		},
+		"nil-server": {
+			servers: []interface{}{
+				nil,
+			},
+			expectedError: true,
+		},
+		"unsupported-server-type": {
+			servers: []interface{}{
+				(&tf5testserver.TestServer{}).ProviderServer,
+				(&tf5testserver.TestServer{}).ProviderServer(),
+			},
+			expectedError: true,
+		},
+		"v5-servers": {
+			servers: []interface{}{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_v5_server1": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_v5_server2": {},
+					},
+				}).ProviderServer,
+			},
+			expectedProtocolVersion: tfmuxserver.ProtocolVersion5,
+			expectedResourceTypes:   []string{"test_resource_v5_server1", "test_resource_v5_server2"},
+		},
+		"v5-and-v6-servers": {
+			servers: []interface{}{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_v6": {},
+					},
+				}).ProviderServer,
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource_v5": {},
+					},
+				}).ProviderServer,
+			},
+			expectedProtocolVersion: tfmuxserver.ProtocolVersion6,
+			expectedResourceTypes:   []string{"test_resource_v5", "test_resource_v6"},
+		},
+		"v5-and-v6-servers-conflict": {
+			servers: []interface{}{
+				(&tf5testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov5.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource": {},
+					},
+				}).ProviderServer,
+			},
+			expectedError: true,
+		},
+		"v6-servers": {
+			servers: []interface{}{
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_v6_server1": {},
+					},
+				}).ProviderServer,
+				(&tf6testserver.TestServer{
+					ResourceSchemas: map[string]*tfprotov6.Schema{
+						"test_resource_v6_server2": {},
+					},
+				}).ProviderServer,
+			},
+			expectedProtocolVersion: tfmuxserver.ProtocolVersion6,
+			expectedResourceTypes:   []string{"test_resource_v6_server1", "test_resource_v6_server2"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			muxServer, err := tfmuxserver.NewMuxServer(ctx, testCase.servers...)
+
+			if err != nil {
+				if !testCase.expectedError {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if testCase.expectedError {
+				t.Fatalf("expected error")
+			}
+
+			if muxServer.ProtocolVersion != testCase.expectedProtocolVersion {
+				t.Errorf("expected protocol version %d, got %d", testCase.expectedProtocolVersion, muxServer.ProtocolVersion)
+			}
+
+			var resourceTypes []string
+
+			switch muxServer.ProtocolVersion {
+			case tfmuxserver.ProtocolVersion5:
+				if muxServer.V6ProviderServer != nil {
+					t.Errorf("unexpected V6ProviderServer")
+				}
+
+				resp, err := muxServer.V5ProviderServer().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				for resourceType := range resp.ResourceSchemas {
+					resourceTypes = append(resourceTypes, resourceType)
+				}
+			case tfmuxserver.ProtocolVersion6:
+				if muxServer.V5ProviderServer != nil {
+					t.Errorf("unexpected V5ProviderServer")
+				}
+
+				resp, err := muxServer.V6ProviderServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				for resourceType := range resp.ResourceSchemas {
+					resourceTypes = append(resourceTypes, resourceType)
+				}
+			}
+
+			sort.Strings(resourceTypes)
+
+			if diff := cmp.Diff(resourceTypes, testCase.expectedResourceTypes); diff != "" {
+				t.Errorf("unexpected resource types difference: %s", diff)
+			}
+		})
+	}
+}